@@ -0,0 +1,242 @@
+package backfill
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+const defaultBatchSize = 500
+
+// Progress persisted state of a named backfill.
+type Progress struct {
+	bun.BaseModel `bun:"table:backfill_progress,alias:backfill_progress"`
+
+	Name        string          `bun:"name,pk" json:"name"`
+	LastPk      json.RawMessage `bun:"last_pk,type:jsonb,nullzero" json:"lastPk"`
+	Processed   int64           `bun:"processed" json:"processed"`
+	Failed      int64           `bun:"failed" json:"failed"`
+	LastError   string          `bun:"last_error,nullzero" json:"lastError"`
+	UpdatedAt   time.Time       `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updatedAt"`
+	CompletedAt time.Time       `bun:"completed_at,nullzero" json:"completedAt"`
+}
+
+func (r Progress) Completed() bool {
+	return !r.CompletedAt.IsZero()
+}
+
+// Transform changes a batch of entities inside the batch transaction.
+type Transform[E metadata.Entity] func(ctx context.Context, tx bun.Tx, batch []E) error
+
+// Backfill runs Transform over entities matching Spec in primary key order.
+// Each batch is committed together with its progress, so a restarted backfill resumes after the last committed batch.
+// A failed batch is rolled back, counted with its error in the progress and stops the run; the next Run retries it.
+type Backfill[E metadata.Entity, T bun.Tx] struct {
+	Name      string
+	Repo      repository.BunCrudRepository[E, T]
+	Spec      dataset.Specifier
+	Transform Transform[E]
+	BatchSize int
+	// Delay throttles the backfill by pausing between batches.
+	Delay time.Duration
+}
+
+func NewBackfill[E metadata.Entity, T bun.Tx](
+	name string,
+	repo repository.BunCrudRepository[E, T],
+	transform Transform[E],
+) Backfill[E, T] {
+	return Backfill[E, T]{
+		Name:      name,
+		Repo:      repo,
+		Transform: transform,
+		BatchSize: defaultBatchSize,
+	}
+}
+
+// Run processes the remaining batches and returns the final progress. A completed backfill is not run again until Reset.
+func (r Backfill[E, T]) Run(ctx context.Context) (Progress, error) {
+	db := r.Repo.ConnSet.WritePool()
+
+	progress, err := r.Progress(ctx, db)
+	if err != nil {
+		return progress, fmt.Errorf("run backfill %s: %w", r.Name, err)
+	}
+
+	if progress.Completed() {
+		return progress, nil
+	}
+
+	after, err := decodePk(progress.LastPk)
+	if err != nil {
+		return progress, fmt.Errorf("run backfill %s: %w", r.Name, err)
+	}
+
+	var batches int
+
+	err = r.Repo.ForEachBatchByPkAfter(ctx, db, r.Spec, after, r.batchSize(), func(batch []E) error {
+		if batches > 0 {
+			if err := r.throttle(ctx); err != nil {
+				return err
+			}
+		}
+
+		batches++
+
+		last, err := json.Marshal(pkValues(db, &batch[len(batch)-1]))
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		next := progress
+		next.LastPk = last
+		next.Processed += int64(len(batch))
+
+		err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			if err := r.Transform(ctx, tx, batch); err != nil {
+				return err
+			}
+
+			return r.save(ctx, tx, next)
+		})
+		if err != nil {
+			progress.Failed += int64(len(batch))
+			progress.LastError = err.Error()
+
+			return errors.Join(err, r.save(ctx, db, progress))
+		}
+
+		progress = next
+
+		return nil
+	})
+	if err != nil {
+		return progress, fmt.Errorf("run backfill %s: %w", r.Name, err)
+	}
+
+	progress.LastError = ""
+	progress.CompletedAt = time.Now()
+
+	if err = r.save(ctx, db, progress); err != nil {
+		return progress, fmt.Errorf("run backfill %s: %w", r.Name, err)
+	}
+
+	return progress, nil
+}
+
+// Progress returns the stored progress, it's empty for a backfill that has not run yet.
+func (r Backfill[E, T]) Progress(ctx context.Context, tx bun.IDB) (Progress, error) {
+	progress := Progress{Name: r.Name}
+
+	if tx == nil {
+		tx = r.Repo.ConnSet.ReadPool()
+	}
+
+	err := tx.NewSelect().
+		Model(&progress).
+		Where("name = ?", r.Name).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Progress{Name: r.Name}, nil
+	}
+
+	if err != nil {
+		return progress, fmt.Errorf("progress: %w", err)
+	}
+
+	return progress, nil
+}
+
+// Reset removes the stored progress so the next Run starts over.
+func (r Backfill[E, T]) Reset(ctx context.Context) error {
+	_, err := r.Repo.ConnSet.WritePool().NewDelete().
+		Model((*Progress)(nil)).
+		Where("name = ?", r.Name).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("reset backfill %s: %w", r.Name, err)
+	}
+
+	return nil
+}
+
+func (r Backfill[E, T]) save(ctx context.Context, tx bun.IDB, progress Progress) error {
+	_, err := tx.NewInsert().
+		Model(&progress).
+		On("CONFLICT (name) DO UPDATE").
+		Set("last_pk = EXCLUDED.last_pk").
+		Set("processed = EXCLUDED.processed").
+		Set("failed = EXCLUDED.failed").
+		Set("last_error = EXCLUDED.last_error").
+		Set("completed_at = EXCLUDED.completed_at").
+		Set("updated_at = current_timestamp").
+		Returning("NULL").
+		Exec(ctx)
+
+	return err //nolint:wrapcheck
+}
+
+func (r Backfill[E, T]) throttle(ctx context.Context) error {
+	if r.Delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(r.Delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (r Backfill[E, T]) batchSize() int {
+	if r.BatchSize <= 0 {
+		return defaultBatchSize
+	}
+
+	return r.BatchSize
+}
+
+// pkValues returns entity primary key values in the table primary key order.
+func pkValues[E any](db bun.IDB, entity *E) []any {
+	table := db.Dialect().Tables().Get(reflect.TypeOf(entity).Elem())
+	strct := reflect.ValueOf(entity).Elem()
+	values := make([]any, len(table.PKs))
+
+	for i, f := range table.PKs {
+		values[i] = f.Value(strct).Interface()
+	}
+
+	return values
+}
+
+// decodePk keeps numbers as json.Number to avoid float precision loss on large keys.
+func decodePk(raw json.RawMessage) ([]any, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var values []any
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	if err := dec.Decode(&values); err != nil {
+		return nil, fmt.Errorf("decode last pk: %w", err)
+	}
+
+	return values, nil
+}
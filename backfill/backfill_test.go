@@ -0,0 +1,135 @@
+package backfill
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+var errTransform = errors.New("transform failed")
+
+type mockConnSet struct {
+	db *sql.DB
+}
+
+func (r mockConnSet) ReadPool() *bun.DB {
+	return bun.NewDB(r.db, pgdialect.New())
+}
+
+func (r mockConnSet) WritePool() *bun.DB {
+	return bun.NewDB(r.db, pgdialect.New())
+}
+
+type testEnt struct {
+	bun.BaseModel `bun:"table:test_entities,alias:test_entities"`
+
+	ID   int    `bun:"id,pk" json:"id"`
+	Name string `bun:"name" json:"name"`
+}
+
+func (r testEnt) EntityName() string {
+	return "testEnt"
+}
+
+func (r testEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+const progressSelect = `SELECT "backfill_progress"."name", "backfill_progress"."last_pk", "backfill_progress"."processed", "backfill_progress"."failed", "backfill_progress"."last_error", "backfill_progress"."updated_at", "backfill_progress"."completed_at" FROM "backfill_progress" WHERE (name = 'names')`
+
+func backfillSetUp(t *testing.T, transform Transform[testEnt]) (Backfill[testEnt, bun.Tx], sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	repo := repository.BunCrudRepository[testEnt, bun.Tx]{ConnSet: mockConnSet{db: db}}
+	backfill := NewBackfill[testEnt, bun.Tx]("names", repo, transform)
+	backfill.BatchSize = 2
+
+	return backfill, mock
+}
+
+func TestBackfill_Run(t *testing.T) {
+	t.Parallel()
+
+	var seen []int
+
+	backfill, mock := backfillSetUp(t, func(ctx context.Context, tx bun.Tx, batch []testEnt) error {
+		for _, e := range batch {
+			seen = append(seen, e.ID)
+		}
+
+		return nil
+	})
+
+	mock.ExpectQuery(regexp.QuoteMeta(progressSelect)).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "last_pk", "processed"}).AddRow("names", `[3]`, 3))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "test_entities" WHERE (("test_entities"."id") > ('3')) ORDER BY "test_entities"."id" ASC LIMIT 2`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(4, "d").AddRow(5, "e"))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "backfill_progress" AS "backfill_progress" ("name", "last_pk", "processed", "failed", "last_error", "updated_at", "completed_at") VALUES ('names', '[5]', 5, 0, DEFAULT, DEFAULT, DEFAULT) ON CONFLICT (name) DO UPDATE`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "test_entities" WHERE (("test_entities"."id") > (5)) ORDER BY "test_entities"."id" ASC LIMIT 2`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "backfill_progress" AS "backfill_progress" ("name", "last_pk", "processed", "failed", "last_error", "updated_at", "completed_at") VALUES ('names', '[5]', 5, 0, DEFAULT, DEFAULT, '`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	progress, err := backfill.Run(context.Background())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 5}, seen)
+	assert.Equal(t, int64(5), progress.Processed)
+	assert.True(t, progress.Completed())
+}
+
+func TestBackfill_RunTransformError(t *testing.T) {
+	t.Parallel()
+
+	backfill, mock := backfillSetUp(t, func(ctx context.Context, tx bun.Tx, batch []testEnt) error {
+		return errTransform
+	})
+
+	mock.ExpectQuery(regexp.QuoteMeta(progressSelect)).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "test_entities" ORDER BY "test_entities"."id" ASC LIMIT 2`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "backfill_progress" AS "backfill_progress" ("name", "last_pk", "processed", "failed", "last_error", "updated_at", "completed_at") VALUES ('names', DEFAULT, 0, 1, 'transform failed', DEFAULT, DEFAULT)`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	progress, err := backfill.Run(context.Background())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.ErrorIs(t, err, errTransform)
+	assert.Equal(t, int64(1), progress.Failed)
+	assert.False(t, progress.Completed())
+}
+
+func TestBackfill_RunCompleted(t *testing.T) {
+	t.Parallel()
+
+	backfill, mock := backfillSetUp(t, nil)
+
+	mock.ExpectQuery(regexp.QuoteMeta(progressSelect)).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "completed_at"}).AddRow("names", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	progress, err := backfill.Run(context.Background())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.True(t, progress.Completed())
+}
@@ -0,0 +1,132 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+var ErrDuplicateKey = errors.New("duplicate operation key")
+
+// Op is an operation of the batch, run within the batch transaction.
+type Op func(ctx context.Context, tx bun.IDB) (any, error)
+
+// Results are operation results by key, see Result.
+type Results map[string]any
+
+// Batch queues operations across repositories to execute them in one transaction.
+type Batch struct {
+	keys []string
+	ops  map[string]Op
+	err  error
+}
+
+func New() *Batch {
+	return &Batch{ops: make(map[string]Op)}
+}
+
+// Add queues the operation under the key, keys must be unique within the batch.
+func (r *Batch) Add(key string, op Op) *Batch {
+	if _, ok := r.ops[key]; ok {
+		r.err = errors.Join(r.err, fmt.Errorf("%s: %w", key, ErrDuplicateKey))
+
+		return r
+	}
+
+	r.keys = append(r.keys, key)
+	r.ops[key] = op
+
+	return r
+}
+
+// Exec runs queued operations in order in a transaction of db, a savepoint when db is a transaction,
+// rolling back all of them on the first error.
+func (r *Batch) Exec(ctx context.Context, db bun.IDB) (Results, error) {
+	if r.err != nil {
+		return nil, fmt.Errorf("batch: %w", r.err)
+	}
+
+	res := make(Results, len(r.keys))
+
+	err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, key := range r.keys {
+			v, err := r.ops[key](ctx, tx)
+			if err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+
+			res[key] = v
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("batch: %w", err)
+	}
+
+	return res, nil
+}
+
+// Result returns the typed result of the operation.
+func Result[V any](res Results, key string) (V, bool) {
+	v, ok := res[key].(V)
+
+	return v, ok
+}
+
+// Create queues CreateOne of the entity, its result is *E.
+func Create[E metadata.Entity, T bun.Tx](
+	b *Batch,
+	key string,
+	repo repository.CrudRepository[E, T],
+	entity *E,
+	columns []string,
+) *Batch {
+	return b.Add(key, func(ctx context.Context, tx bun.IDB) (any, error) {
+		return repo.CreateOne(ctx, tx, entity, columns)
+	})
+}
+
+// CreateAll queues CreateAll of the entities, its result is []E.
+func CreateAll[E metadata.Entity, T bun.Tx](
+	b *Batch,
+	key string,
+	repo repository.CrudRepository[E, T],
+	entities []E,
+	columns []string,
+) *Batch {
+	return b.Add(key, func(ctx context.Context, tx bun.IDB) (any, error) {
+		return repo.CreateAll(ctx, tx, entities, columns)
+	})
+}
+
+// Update queues UpdateOne of the entity, its result is *E.
+func Update[E metadata.Entity, T bun.Tx](
+	b *Batch,
+	key string,
+	repo repository.CrudRepository[E, T],
+	entity *E,
+	columnsToUpdate []string,
+	columns []string,
+) *Batch {
+	return b.Add(key, func(ctx context.Context, tx bun.IDB) (any, error) {
+		return repo.UpdateOne(ctx, tx, entity, columnsToUpdate, columns)
+	})
+}
+
+// Delete queues Delete by the spec, its result is the number of deleted rows.
+func Delete[E metadata.Entity, T bun.Tx](
+	b *Batch,
+	key string,
+	repo repository.CrudRepository[E, T],
+	spec dataset.Specifier,
+) *Batch {
+	return b.Add(key, func(ctx context.Context, tx bun.IDB) (any, error) {
+		return repo.Delete(ctx, tx, spec)
+	})
+}
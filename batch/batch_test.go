@@ -0,0 +1,139 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+type testEnt struct {
+	ID int
+}
+
+func (r testEnt) EntityName() string { return "testEnt" }
+
+func (r testEnt) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"id": r.ID} }
+
+type stubRepo struct {
+	repository.CrudRepository[testEnt, bun.Tx]
+
+	err error
+}
+
+func (r stubRepo) CreateOne(_ context.Context, tx bun.IDB, entity *testEnt, _ []string) (*testEnt, error) {
+	if _, ok := tx.(bun.Tx); !ok {
+		return nil, errors.New("no transaction")
+	}
+
+	return entity, r.err
+}
+
+func (r stubRepo) Delete(_ context.Context, tx bun.IDB, _ dataset.Specifier) (int, error) {
+	if _, ok := tx.(bun.Tx); !ok {
+		return 0, errors.New("no transaction")
+	}
+
+	return 2, r.err
+}
+
+func TestBatch_Exec(t *testing.T) {
+	t.Parallel()
+
+	failure := errors.New("failure")
+
+	tests := []struct {
+		name  string
+		batch func() *Batch
+		mock  func(mock sqlmock.Sqlmock)
+		check func(t *testing.T, res Results, err error)
+	}{
+		{
+			name: "committed",
+			batch: func() *Batch {
+				b := New()
+				Create[testEnt, bun.Tx](b, "user", stubRepo{}, &testEnt{ID: 1}, nil)
+				Delete[testEnt, bun.Tx](b, "tokens", stubRepo{}, dataspec.NewEqual("id", 1))
+
+				return b
+			},
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			check: func(t *testing.T, res Results, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+
+				user, ok := Result[*testEnt](res, "user")
+				assert.True(t, ok)
+				assert.Equal(t, &testEnt{ID: 1}, user)
+
+				deleted, ok := Result[int](res, "tokens")
+				assert.True(t, ok)
+				assert.Equal(t, 2, deleted)
+			},
+		},
+		{
+			name: "rolled back",
+			batch: func() *Batch {
+				b := New()
+				Create[testEnt, bun.Tx](b, "user", stubRepo{}, &testEnt{ID: 1}, nil)
+				Delete[testEnt, bun.Tx](b, "tokens", stubRepo{err: failure}, nil)
+
+				return b
+			},
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			check: func(t *testing.T, res Results, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, failure)
+				assert.ErrorContains(t, err, "batch: tokens: failure")
+				assert.Nil(t, res)
+			},
+		},
+		{
+			name: "duplicate key",
+			batch: func() *Batch {
+				b := New()
+				Create[testEnt, bun.Tx](b, "user", stubRepo{}, &testEnt{ID: 1}, nil)
+				Create[testEnt, bun.Tx](b, "user", stubRepo{}, &testEnt{ID: 2}, nil)
+
+				return b
+			},
+			mock: func(mock sqlmock.Sqlmock) {},
+			check: func(t *testing.T, res Results, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrDuplicateKey)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlDB, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+
+			db := bun.NewDB(sqlDB, pgdialect.New())
+			tt.mock(mock)
+
+			res, err := tt.batch().Exec(context.Background(), db)
+
+			tt.check(t, res, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package buffer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+var ErrClosed = errors.New("buffered writer closed")
+
+// Options of the buffered writer.
+type Options[E any] struct {
+	// Size flushes once that many entities are buffered.
+	Size int
+	// Interval flushes buffered entities periodically.
+	Interval time.Duration
+	// Columns are passed to CreateAll.
+	Columns []string
+	// OnError, when set, receives flush errors with the entities that were not written.
+	OnError func(err error, entities []E)
+}
+
+// Writer buffers entities of loss tolerant, high frequency inserts, e.g. metrics or logs,
+// and writes them with CreateAll by size and interval. Entities of failed flushes are dropped.
+type Writer[E metadata.Entity, T bun.Tx] struct {
+	repo repository.CrudRepository[E, T]
+	opts Options[E]
+
+	mu      sync.Mutex
+	pending []E
+	closed  bool
+
+	full chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewWriter starts the writer, Close drains it.
+func NewWriter[E metadata.Entity, T bun.Tx](repo repository.CrudRepository[E, T], opts Options[E]) *Writer[E, T] {
+	w := &Writer[E, T]{
+		repo: repo,
+		opts: opts,
+		full: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go w.run()
+
+	return w
+}
+
+// BufferedCreate adds the entity to the buffer without blocking on the database.
+func (r *Writer[E, T]) BufferedCreate(entity E) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return ErrClosed
+	}
+
+	r.pending = append(r.pending, entity)
+
+	if r.opts.Size > 0 && len(r.pending) >= r.opts.Size {
+		select {
+		case r.full <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Close stops accepting entities and flushes the buffered ones, waiting for an in-flight flush.
+func (r *Writer[E, T]) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+
+		return ErrClosed
+	}
+
+	r.closed = true
+	r.mu.Unlock()
+
+	close(r.stop)
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck
+	}
+
+	return r.flush(ctx)
+}
+
+func (r *Writer[E, T]) run() {
+	defer close(r.done)
+
+	var tick <-chan time.Time
+
+	if r.opts.Interval > 0 {
+		ticker := time.NewTicker(r.opts.Interval)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-tick:
+		case <-r.full:
+		}
+
+		_ = r.flush(context.Background())
+	}
+}
+
+func (r *Writer[E, T]) flush(ctx context.Context) error {
+	r.mu.Lock()
+	batch := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	_, err := r.repo.CreateAll(ctx, nil, batch, r.opts.Columns)
+	if err != nil && r.opts.OnError != nil {
+		r.opts.OnError(err, batch)
+	}
+
+	return err //nolint:wrapcheck
+}
@@ -0,0 +1,107 @@
+package buffer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type testEnt struct {
+	ID int
+}
+
+func (r testEnt) EntityName() string { return "testEnt" }
+
+func (r testEnt) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"id": r.ID} }
+
+type stubRepo struct {
+	repository.CrudRepository[testEnt, bun.Tx]
+
+	mu      sync.Mutex
+	batches [][]testEnt
+	err     error
+	written chan struct{}
+}
+
+func newStubRepo(err error) *stubRepo {
+	return &stubRepo{err: err, written: make(chan struct{}, 10)}
+}
+
+func (r *stubRepo) CreateAll(_ context.Context, _ bun.IDB, entities []testEnt, _ []string) ([]testEnt, error) {
+	r.mu.Lock()
+	r.batches = append(r.batches, entities)
+	r.mu.Unlock()
+
+	r.written <- struct{}{}
+
+	return entities, r.err
+}
+
+func (r *stubRepo) wait(t *testing.T) {
+	t.Helper()
+
+	select {
+	case <-r.written:
+	case <-time.After(time.Second):
+		t.Fatal("no flush")
+	}
+}
+
+func TestWriter_FlushBySize(t *testing.T) {
+	t.Parallel()
+
+	repo := newStubRepo(nil)
+	w := NewWriter[testEnt, bun.Tx](repo, Options[testEnt]{Size: 2})
+
+	assert.NoError(t, w.BufferedCreate(testEnt{ID: 1}))
+	assert.NoError(t, w.BufferedCreate(testEnt{ID: 2}))
+	repo.wait(t)
+
+	assert.NoError(t, w.BufferedCreate(testEnt{ID: 3}))
+	assert.NoError(t, w.Close(context.Background()))
+
+	assert.Equal(t, [][]testEnt{{{ID: 1}, {ID: 2}}, {{ID: 3}}}, repo.batches)
+	assert.ErrorIs(t, w.BufferedCreate(testEnt{ID: 4}), ErrClosed)
+	assert.ErrorIs(t, w.Close(context.Background()), ErrClosed)
+}
+
+func TestWriter_FlushByInterval(t *testing.T) {
+	t.Parallel()
+
+	repo := newStubRepo(nil)
+	w := NewWriter[testEnt, bun.Tx](repo, Options[testEnt]{Interval: time.Millisecond})
+
+	assert.NoError(t, w.BufferedCreate(testEnt{ID: 1}))
+	repo.wait(t)
+	assert.NoError(t, w.Close(context.Background()))
+
+	assert.Equal(t, [][]testEnt{{{ID: 1}}}, repo.batches)
+}
+
+func TestWriter_OnError(t *testing.T) {
+	t.Parallel()
+
+	failure := errors.New("failure")
+	repo := newStubRepo(failure)
+
+	var dropped []testEnt
+
+	w := NewWriter[testEnt, bun.Tx](repo, Options[testEnt]{
+		OnError: func(err error, entities []testEnt) {
+			assert.ErrorIs(t, err, failure)
+
+			dropped = append(dropped, entities...)
+		},
+	})
+
+	assert.NoError(t, w.BufferedCreate(testEnt{ID: 1}))
+	assert.ErrorIs(t, w.Close(context.Background()), failure)
+	assert.Equal(t, []testEnt{{ID: 1}}, dropped)
+}
@@ -0,0 +1,133 @@
+package chaos
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type testEnt struct {
+	ID int
+}
+
+func (r testEnt) EntityName() string { return "testEnt" }
+
+func (r testEnt) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"id": r.ID} }
+
+type stubRepo struct {
+	repository.CrudRepository[testEnt, bun.Tx]
+
+	created []testEnt
+}
+
+func (r *stubRepo) Count(context.Context, bun.IDB, dataset.Specifier) (int, error) {
+	return 1, nil
+}
+
+func (r *stubRepo) CreateAll(_ context.Context, _ bun.IDB, entities []testEnt, _ []string) ([]testEnt, error) {
+	r.created = append(r.created, entities...)
+
+	return entities, nil
+}
+
+func TestFaulty_Count(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		faults   []Fault
+		expected []error
+	}{
+		{
+			name:     "no faults",
+			expected: []error{nil, nil},
+		},
+		{
+			name:     "every call",
+			faults:   []Fault{{Op: "count", Err: ErrConnection}},
+			expected: []error{driver.ErrBadConn, driver.ErrBadConn},
+		},
+		{
+			name:     "other operation",
+			faults:   []Fault{{Op: "find one", Err: ErrConnection}},
+			expected: []error{nil, nil},
+		},
+		{
+			name:     "after and times",
+			faults:   []Fault{{After: 1, Times: 1, Err: ErrSerializationFailure}},
+			expected: []error{nil, ErrSerializationFailure, nil},
+		},
+		{
+			name: "first matching fault",
+			faults: []Fault{
+				{Times: 1, Err: ErrDeadlock},
+				{Err: ErrConnection},
+			},
+			expected: []error{ErrDeadlock, ErrConnection},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := NewFaulty[testEnt, bun.Tx](&stubRepo{}, NewInjector(tt.faults...))
+
+			for _, expected := range tt.expected {
+				_, err := repo.Count(context.Background(), nil, nil)
+				assert.ErrorIs(t, err, expected)
+			}
+		})
+	}
+}
+
+func TestFaulty_Latency(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFaulty[testEnt, bun.Tx](&stubRepo{}, NewInjector(Fault{Latency: time.Hour}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := repo.Count(ctx, nil, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	repo.Faults.Reset()
+	repo.Faults.Add(Fault{Latency: time.Millisecond})
+
+	count, err := repo.Count(context.Background(), nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestFaulty_CreateAllPartial(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubRepo{}
+	repo := NewFaulty[testEnt, bun.Tx](stub, NewInjector(Fault{Op: "create all", Partial: 2, Err: ErrConnection}))
+
+	res, err := repo.CreateAll(context.Background(), nil, []testEnt{{ID: 1}, {ID: 2}, {ID: 3}}, nil)
+
+	assert.ErrorIs(t, err, ErrConnection)
+	assert.Equal(t, []testEnt{{ID: 1}, {ID: 2}}, res)
+	assert.Equal(t, []testEnt{{ID: 1}, {ID: 2}}, stub.created)
+}
+
+func TestPgError(t *testing.T) {
+	t.Parallel()
+
+	var pgErr interface{ SQLState() string }
+
+	assert.True(t, errors.As(ErrSerializationFailure, &pgErr))
+	assert.Equal(t, "40001", pgErr.SQLState())
+	assert.Equal(t, "ERROR: deadlock detected (SQLSTATE=40P01)", ErrDeadlock.Error())
+}
@@ -0,0 +1,141 @@
+// Package chaos injects faults into repository calls to test retries and circuit breakers deterministically.
+package chaos
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PgError mimics a PostgreSQL error carrying a SQLSTATE code.
+type PgError struct {
+	Code    string
+	Message string
+}
+
+func (r *PgError) Error() string {
+	return fmt.Sprintf("ERROR: %s (SQLSTATE=%s)", r.Message, r.Code)
+}
+
+// SQLState returns the SQLSTATE code.
+func (r *PgError) SQLState() string {
+	return r.Code
+}
+
+var (
+	// ErrConnection is a broken connection, it matches driver.ErrBadConn.
+	ErrConnection = fmt.Errorf("injected connection error: %w", driver.ErrBadConn)
+	// ErrSerializationFailure is a retryable serializable transaction conflict.
+	ErrSerializationFailure = &PgError{Code: "40001", Message: "could not serialize access due to concurrent update"}
+	// ErrDeadlock is a retryable deadlock.
+	ErrDeadlock = &PgError{Code: "40P01", Message: "deadlock detected"}
+)
+
+// Fault describes what to inject into matching calls.
+type Fault struct {
+	// Op is the operation name, e.g. "find page" or "create all". Empty matches every operation.
+	Op string
+	// After is the number of matching calls let through before the fault fires.
+	After int
+	// Times limits how many times the fault fires, zero means every matching call.
+	Times int
+	// Latency delays the call, it is interrupted by the context.
+	Latency time.Duration
+	// Err is returned instead of calling the repository.
+	Err error
+	// Partial makes create all write the first Partial entities before returning Err.
+	Partial int
+}
+
+type rule struct {
+	Fault
+	calls int
+	fired int
+}
+
+// Injector holds faults of a test, the first matching fault applies to a call.
+type Injector struct {
+	mu    sync.Mutex
+	rules []*rule
+}
+
+func NewInjector(faults ...Fault) *Injector {
+	i := &Injector{}
+
+	for _, f := range faults {
+		i.Add(f)
+	}
+
+	return i
+}
+
+// Add appends a fault.
+func (r *Injector) Add(f Fault) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rules = append(r.rules, &rule{Fault: f})
+}
+
+// Reset removes all faults.
+func (r *Injector) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rules = nil
+}
+
+// next returns the fault firing for the call and counts the call for every matching rule.
+func (r *Injector) next(op string) (Fault, bool) {
+	if r == nil {
+		return Fault{}, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var (
+		res   Fault
+		found bool
+	)
+
+	for _, rl := range r.rules {
+		if rl.Op != "" && rl.Op != op {
+			continue
+		}
+
+		rl.calls++
+
+		if found || rl.calls <= rl.After || (rl.Times > 0 && rl.fired >= rl.Times) {
+			continue
+		}
+
+		rl.fired++
+		res, found = rl.Fault, true
+	}
+
+	return res, found
+}
+
+// inject applies the fault latency and returns the fault for the call.
+func (r *Injector) inject(ctx context.Context, op string) (Fault, error) {
+	f, ok := r.next(op)
+	if !ok {
+		return Fault{}, nil
+	}
+
+	if f.Latency > 0 {
+		timer := time.NewTimer(f.Latency)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return f, ctx.Err() //nolint:wrapcheck
+		case <-timer.C:
+		}
+	}
+
+	return f, f.Err
+}
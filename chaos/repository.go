@@ -0,0 +1,277 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+// Faulty decorates a repository with faults of the injector, operations are named after
+// the repository error prefixes, e.g. "find page" or "create all".
+type Faulty[E metadata.Entity, T bun.Tx] struct {
+	repository.CrudRepository[E, T]
+
+	Faults *Injector
+}
+
+func NewFaulty[E metadata.Entity, T bun.Tx](
+	repo repository.CrudRepository[E, T],
+	faults *Injector,
+) Faulty[E, T] {
+	return Faulty[E, T]{
+		CrudRepository: repo,
+		Faults:         faults,
+	}
+}
+
+func (r Faulty[E, T]) FindOne(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+) (*E, error) {
+	if _, err := r.Faults.inject(ctx, "find one"); err != nil {
+		return nil, err
+	}
+
+	return r.CrudRepository.FindOne(ctx, tx, columns, spec) //nolint:wrapcheck
+}
+
+func (r Faulty[E, T]) FindOneByPk(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	pk metadata.PrimaryKey,
+) (*E, error) {
+	if _, err := r.Faults.inject(ctx, "find one by pk"); err != nil {
+		return nil, err
+	}
+
+	return r.CrudRepository.FindOneByPk(ctx, tx, columns, pk) //nolint:wrapcheck
+}
+
+func (r Faulty[E, T]) FindOneBy(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	criteria map[string]any,
+) (*E, error) {
+	if _, err := r.Faults.inject(ctx, "find one by"); err != nil {
+		return nil, err
+	}
+
+	return r.CrudRepository.FindOneBy(ctx, tx, columns, criteria) //nolint:wrapcheck
+}
+
+func (r Faulty[E, T]) FindAll(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+) ([]E, error) {
+	if _, err := r.Faults.inject(ctx, "find all"); err != nil {
+		return nil, err
+	}
+
+	return r.CrudRepository.FindAll(ctx, tx, columns, spec) //nolint:wrapcheck
+}
+
+func (r Faulty[E, T]) FindAllBy(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	criteria map[string]any,
+) ([]E, error) {
+	if _, err := r.Faults.inject(ctx, "find all by"); err != nil {
+		return nil, err
+	}
+
+	return r.CrudRepository.FindAllBy(ctx, tx, columns, criteria) //nolint:wrapcheck
+}
+
+func (r Faulty[E, T]) FindPage(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+	page dataset.Pager,
+	sort dataset.Sorter,
+) ([]E, error) {
+	if _, err := r.Faults.inject(ctx, "find page"); err != nil {
+		return nil, err
+	}
+
+	return r.CrudRepository.FindPage(ctx, tx, columns, spec, page, sort) //nolint:wrapcheck
+}
+
+func (r Faulty[E, T]) FindAllByPks(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	pks []metadata.PrimaryKey,
+) ([]E, error) {
+	if _, err := r.Faults.inject(ctx, "find all by pks"); err != nil {
+		return nil, err
+	}
+
+	return r.CrudRepository.FindAllByPks(ctx, tx, columns, pks) //nolint:wrapcheck
+}
+
+func (r Faulty[E, T]) Count(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (int, error) {
+	if _, err := r.Faults.inject(ctx, "count"); err != nil {
+		return 0, err
+	}
+
+	return r.CrudRepository.Count(ctx, tx, spec) //nolint:wrapcheck
+}
+
+func (r Faulty[E, T]) Exists(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (bool, error) {
+	if _, err := r.Faults.inject(ctx, "exists"); err != nil {
+		return false, err
+	}
+
+	return r.CrudRepository.Exists(ctx, tx, spec) //nolint:wrapcheck
+}
+
+func (r Faulty[E, T]) CreateOne(
+	ctx context.Context,
+	tx bun.IDB,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	if _, err := r.Faults.inject(ctx, "create one"); err != nil {
+		return nil, err
+	}
+
+	return r.CrudRepository.CreateOne(ctx, tx, entity, columns) //nolint:wrapcheck
+}
+
+// CreateAll writes the first Partial entities before returning a partial fault error.
+func (r Faulty[E, T]) CreateAll(
+	ctx context.Context,
+	tx bun.IDB,
+	entities []E,
+	columns []string,
+) ([]E, error) {
+	f, err := r.Faults.inject(ctx, "create all")
+	if err == nil {
+		return r.CrudRepository.CreateAll(ctx, tx, entities, columns) //nolint:wrapcheck
+	}
+
+	if f.Partial <= 0 || f.Err == nil || ctx.Err() != nil {
+		return nil, err
+	}
+
+	res, perr := r.CrudRepository.CreateAll(ctx, tx, entities[:min(f.Partial, len(entities))], columns)
+	if perr != nil {
+		return nil, perr //nolint:wrapcheck
+	}
+
+	return res, err
+}
+
+func (r Faulty[E, T]) FirstOrCreate(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	if _, err := r.Faults.inject(ctx, "first or create"); err != nil {
+		return nil, err
+	}
+
+	return r.CrudRepository.FirstOrCreate(ctx, tx, spec, entity, columns) //nolint:wrapcheck
+}
+
+func (r Faulty[E, T]) FirstOrCreateLocked(
+	ctx context.Context,
+	tx bun.IDB,
+	key string,
+	spec dataset.Specifier,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	if _, err := r.Faults.inject(ctx, "first or create locked"); err != nil {
+		return nil, err
+	}
+
+	return r.CrudRepository.FirstOrCreateLocked(ctx, tx, key, spec, entity, columns) //nolint:wrapcheck
+}
+
+func (r Faulty[E, T]) UpdateOrCreate(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	values map[string]any,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	if _, err := r.Faults.inject(ctx, "update or create"); err != nil {
+		return nil, err
+	}
+
+	return r.CrudRepository.UpdateOrCreate(ctx, tx, spec, values, entity, columns) //nolint:wrapcheck
+}
+
+func (r Faulty[E, T]) UpdateOne(
+	ctx context.Context,
+	tx bun.IDB,
+	entity *E,
+	columnsToUpdate []string,
+	columns []string,
+) (*E, error) {
+	if _, err := r.Faults.inject(ctx, "update one"); err != nil {
+		return nil, err
+	}
+
+	return r.CrudRepository.UpdateOne(ctx, tx, entity, columnsToUpdate, columns) //nolint:wrapcheck
+}
+
+func (r Faulty[E, T]) ForceDelete(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (int, error) {
+	if _, err := r.Faults.inject(ctx, "force delete"); err != nil {
+		return 0, err
+	}
+
+	return r.CrudRepository.ForceDelete(ctx, tx, spec) //nolint:wrapcheck
+}
+
+func (r Faulty[E, T]) Delete(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (int, error) {
+	if _, err := r.Faults.inject(ctx, "delete"); err != nil {
+		return 0, err
+	}
+
+	return r.CrudRepository.Delete(ctx, tx, spec) //nolint:wrapcheck
+}
+
+func (r Faulty[E, T]) IsColumnValueUnique(
+	ctx context.Context,
+	tx bun.IDB,
+	column string,
+	value any,
+) (bool, error) {
+	if _, err := r.Faults.inject(ctx, "is column value unique"); err != nil {
+		return false, err
+	}
+
+	return r.CrudRepository.IsColumnValueUnique(ctx, tx, column, value) //nolint:wrapcheck
+}
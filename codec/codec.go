@@ -0,0 +1,137 @@
+package codec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnsupportedType = errors.New("codec: unsupported type")
+	ErrUnknownCodec    = errors.New("codec: unknown codec")
+	ErrUnknownVersion  = errors.New("codec: unknown schema version")
+	ErrMalformed       = errors.New("codec: malformed envelope")
+)
+
+// Codec serializes entities for cache, outbox, snapshot and CDC payloads.
+type Codec interface {
+	// Name returns a stable codec identifier written into envelopes.
+	Name() string
+	// Marshal encodes value.
+	Marshal(value any) ([]byte, error)
+	// Unmarshal decodes data into value.
+	Unmarshal(data []byte, value any) error
+}
+
+// Envelope wraps payloads with the codec name and entity schema version.
+//
+// Layout: uint16 version | uint8 name length | name | payload.
+type Envelope struct {
+	Codec   Codec
+	Version uint16
+}
+
+func NewEnvelope(codec Codec, version uint16) Envelope {
+	return Envelope{
+		Codec:   codec,
+		Version: version,
+	}
+}
+
+// Encode marshals value and prefixes it with the envelope header.
+func (r Envelope) Encode(value any) ([]byte, error) {
+	name := r.Codec.Name()
+	if len(name) > maxNameLen {
+		return nil, fmt.Errorf("encode: codec name %q: %w", name, ErrMalformed)
+	}
+
+	payload, err := r.Codec.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("encode: %w", err)
+	}
+
+	data := make([]byte, 0, headerSize+len(name)+len(payload))
+	data = binary.BigEndian.AppendUint16(data, r.Version)
+	data = append(data, byte(len(name)))
+	data = append(data, name...)
+	data = append(data, payload...)
+
+	return data, nil
+}
+
+// Decode unmarshals data into value and returns the schema version it was written with.
+// Payloads written by a newer schema version than the envelope knows are rejected.
+func (r Envelope) Decode(data []byte, value any) (uint16, error) {
+	version, name, payload, err := split(data)
+	if err != nil {
+		return 0, fmt.Errorf("decode: %w", err)
+	}
+
+	if name != r.Codec.Name() {
+		return 0, fmt.Errorf("decode: codec %q: %w", name, ErrUnknownCodec)
+	}
+
+	if version > r.Version {
+		return 0, fmt.Errorf("decode: version %d: %w", version, ErrUnknownVersion)
+	}
+
+	if err = r.Codec.Unmarshal(payload, value); err != nil {
+		return 0, fmt.Errorf("decode: %w", err)
+	}
+
+	return version, nil
+}
+
+// Registry decodes envelopes written by any of the registered codecs,
+// which keeps old payloads readable after switching codec.
+type Registry struct {
+	Version uint16
+	codecs  map[string]Codec
+}
+
+func NewRegistry(version uint16, codecs ...Codec) *Registry {
+	r := &Registry{
+		Version: version,
+		codecs:  make(map[string]Codec, len(codecs)),
+	}
+
+	for _, c := range codecs {
+		r.codecs[c.Name()] = c
+	}
+
+	return r
+}
+
+func (r *Registry) Decode(data []byte, value any) (uint16, error) {
+	_, name, _, err := split(data)
+	if err != nil {
+		return 0, fmt.Errorf("decode: %w", err)
+	}
+
+	c, ok := r.codecs[name]
+	if !ok {
+		return 0, fmt.Errorf("decode: codec %q: %w", name, ErrUnknownCodec)
+	}
+
+	return NewEnvelope(c, r.Version).Decode(data, value)
+}
+
+const (
+	headerSize = 3
+	maxNameLen = 255
+)
+
+func split(data []byte) (uint16, string, []byte, error) {
+	if len(data) < headerSize {
+		return 0, "", nil, ErrMalformed
+	}
+
+	version := binary.BigEndian.Uint16(data)
+	nameLen := int(data[2])
+
+	if len(data) < headerSize+nameLen {
+		return 0, "", nil, ErrMalformed
+	}
+
+	return version, string(data[headerSize : headerSize+nameLen]), data[headerSize+nameLen:], nil
+}
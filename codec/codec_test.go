@@ -0,0 +1,106 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type testEnt struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		codec Codec
+	}{
+		{
+			name:  "json",
+			codec: JSON{},
+		},
+		{
+			name:  "msgpack",
+			codec: Msgpack{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			env := NewEnvelope(tt.codec, 2)
+
+			data, err := env.Encode(testEnt{ID: 1, Name: "John"})
+			assert.NoError(t, err)
+
+			var res testEnt
+
+			version, err := env.Decode(data, &res)
+			assert.NoError(t, err)
+			assert.Equal(t, uint16(2), version)
+			assert.Equal(t, testEnt{ID: 1, Name: "John"}, res)
+		})
+	}
+}
+
+func TestEnvelope_Proto(t *testing.T) {
+	t.Parallel()
+
+	env := NewEnvelope(Proto{}, 1)
+
+	data, err := env.Encode(wrapperspb.String("John"))
+	assert.NoError(t, err)
+
+	res := &wrapperspb.StringValue{}
+	_, err = env.Decode(data, res)
+	assert.NoError(t, err)
+	assert.Equal(t, "John", res.GetValue())
+
+	_, err = env.Encode(testEnt{})
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+}
+
+func TestEnvelope_DecodeErrors(t *testing.T) {
+	t.Parallel()
+
+	newer, err := NewEnvelope(JSON{}, 3).Encode(testEnt{ID: 1})
+	assert.NoError(t, err)
+
+	_, err = NewEnvelope(JSON{}, 2).Decode(newer, &testEnt{})
+	assert.ErrorIs(t, err, ErrUnknownVersion)
+
+	_, err = NewEnvelope(Msgpack{}, 3).Decode(newer, &testEnt{})
+	assert.ErrorIs(t, err, ErrUnknownCodec)
+
+	_, err = NewEnvelope(JSON{}, 3).Decode([]byte{0, 1, 9, 'j'}, &testEnt{})
+	assert.ErrorIs(t, err, ErrMalformed)
+}
+
+func TestRegistry_Decode(t *testing.T) {
+	t.Parallel()
+
+	reg := NewRegistry(1, JSON{}, Msgpack{})
+
+	for _, c := range []Codec{JSON{}, Msgpack{}} {
+		data, err := NewEnvelope(c, 1).Encode(testEnt{ID: 7, Name: "Ann"})
+		assert.NoError(t, err)
+
+		var res testEnt
+
+		_, err = reg.Decode(data, &res)
+		assert.NoError(t, err)
+		assert.Equal(t, testEnt{ID: 7, Name: "Ann"}, res)
+	}
+
+	data, err := NewEnvelope(Proto{}, 1).Encode(wrapperspb.String("x"))
+	assert.NoError(t, err)
+
+	_, err = reg.Decode(data, &testEnt{})
+	assert.ErrorIs(t, err, ErrUnknownCodec)
+}
@@ -0,0 +1,19 @@
+package codec
+
+import (
+	"encoding/json"
+)
+
+type JSON struct{}
+
+func (r JSON) Name() string {
+	return "json"
+}
+
+func (r JSON) Marshal(value any) ([]byte, error) {
+	return json.Marshal(value) //nolint:wrapcheck
+}
+
+func (r JSON) Unmarshal(data []byte, value any) error {
+	return json.Unmarshal(data, value) //nolint:wrapcheck
+}
@@ -0,0 +1,40 @@
+package codec
+
+import (
+	"bytes"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Msgpack encodes structs using their json tags, so presenter names stay stable across codecs.
+type Msgpack struct{}
+
+func (r Msgpack) Name() string {
+	return "msgpack"
+}
+
+func (r Msgpack) Marshal(value any) ([]byte, error) {
+	enc := msgpack.GetEncoder()
+	defer msgpack.PutEncoder(enc)
+
+	var buf bytes.Buffer
+
+	enc.Reset(&buf)
+	enc.SetCustomStructTag("json")
+
+	if err := enc.Encode(value); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (r Msgpack) Unmarshal(data []byte, value any) error {
+	dec := msgpack.GetDecoder()
+	defer msgpack.PutDecoder(dec)
+
+	dec.Reset(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+
+	return dec.Decode(value) //nolint:wrapcheck
+}
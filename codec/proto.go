@@ -0,0 +1,32 @@
+package codec
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Proto encodes values implementing proto.Message.
+type Proto struct{}
+
+func (r Proto) Name() string {
+	return "proto"
+}
+
+func (r Proto) Marshal(value any) ([]byte, error) {
+	msg, ok := value.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("proto marshal %T: %w", value, ErrUnsupportedType)
+	}
+
+	return proto.Marshal(msg) //nolint:wrapcheck
+}
+
+func (r Proto) Unmarshal(data []byte, value any) error {
+	msg, ok := value.(proto.Message)
+	if !ok {
+		return fmt.Errorf("proto unmarshal %T: %w", value, ErrUnsupportedType)
+	}
+
+	return proto.Unmarshal(data, msg) //nolint:wrapcheck
+}
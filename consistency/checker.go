@@ -0,0 +1,163 @@
+package consistency
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aso779/bun-pg-connector"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+type DriftKind string
+
+const (
+	// MissingOnReplica row exists on primary only.
+	MissingOnReplica DriftKind = "missing_on_replica"
+	// ExtraOnReplica row exists on replica only.
+	ExtraOnReplica DriftKind = "extra_on_replica"
+	// Changed row exists on both sides with different content.
+	Changed DriftKind = "changed"
+)
+
+// Range PK range, From is inclusive and To is exclusive. Values follow the entity PK column order.
+type Range struct {
+	From []any
+	To   []any
+}
+
+type Drift struct {
+	PK   metadata.PrimaryKey
+	Kind DriftKind
+}
+
+type Report struct {
+	Checked int
+	Drifts  []Drift
+}
+
+func (r Report) HasDrift() bool {
+	return len(r.Drifts) > 0
+}
+
+// Checker compares row hashes of the primary (write) pool with the replica (read) pool.
+type Checker[E metadata.Entity] struct {
+	ConnSet bunpgconnector.BunConnSet
+}
+
+func NewChecker[E metadata.Entity](connSet bunpgconnector.BunConnSet) Checker[E] {
+	return Checker[E]{
+		ConnSet: connSet,
+	}
+}
+
+func (r Checker[E]) Check(ctx context.Context, ranges ...Range) (Report, error) {
+	var report Report
+
+	for _, rng := range ranges {
+		primary, err := r.hashes(ctx, r.ConnSet.WritePool(), rng)
+		if err != nil {
+			return report, fmt.Errorf("check primary: %w", err)
+		}
+
+		replica, err := r.hashes(ctx, r.ConnSet.ReadPool(), rng)
+		if err != nil {
+			return report, fmt.Errorf("check replica: %w", err)
+		}
+
+		for _, row := range primary.rows {
+			report.Checked++
+
+			other, ok := replica.byKey[row.key]
+
+			switch {
+			case !ok:
+				report.Drifts = append(report.Drifts, Drift{PK: row.pk, Kind: MissingOnReplica})
+			case other.hash != row.hash:
+				report.Drifts = append(report.Drifts, Drift{PK: row.pk, Kind: Changed})
+			}
+		}
+
+		for _, row := range replica.rows {
+			if _, ok := primary.byKey[row.key]; !ok {
+				report.Checked++
+				report.Drifts = append(report.Drifts, Drift{PK: row.pk, Kind: ExtraOnReplica})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+type hashedRow struct {
+	key  string
+	pk   metadata.PrimaryKey
+	hash string
+}
+
+type hashedRows struct {
+	rows  []hashedRow
+	byKey map[string]hashedRow
+}
+
+func (r Checker[E]) hashes(ctx context.Context, db *bun.DB, rng Range) (hashedRows, error) {
+	var (
+		entity E
+		result []map[string]any
+	)
+
+	table := db.Table(reflect.TypeOf(entity))
+	pkColumns := make([]string, 0, len(table.PKs))
+
+	for _, f := range table.PKs {
+		pkColumns = append(pkColumns, f.Name)
+	}
+
+	tuple := bun.Safe("(" + strings.Join(pkColumns, ", ") + ")")
+
+	query := db.NewSelect().
+		Model(&entity).
+		Column(pkColumns...).
+		ColumnExpr("md5(?TableAlias::text) AS hash").
+		OrderExpr("?", tuple)
+
+	if len(rng.From) > 0 {
+		query.Where("? >= (?)", tuple, bun.In(rng.From))
+	}
+
+	if len(rng.To) > 0 {
+		query.Where("? < (?)", tuple, bun.In(rng.To))
+	}
+
+	if err := query.Scan(ctx, &result); err != nil {
+		return hashedRows{}, err //nolint:wrapcheck
+	}
+
+	rows := hashedRows{
+		rows:  make([]hashedRow, 0, len(result)),
+		byKey: make(map[string]hashedRow, len(result)),
+	}
+
+	for _, v := range result {
+		row := hashedRow{
+			pk:   make(metadata.PrimaryKey, len(pkColumns)),
+			hash: fmt.Sprint(v["hash"]),
+		}
+
+		keyParts := make([]string, 0, len(pkColumns))
+
+		for _, c := range pkColumns {
+			row.pk[c] = v[c]
+			keyParts = append(keyParts, fmt.Sprint(v[c]))
+		}
+
+		row.key = strings.Join(keyParts, "\x00")
+
+		rows.rows = append(rows.rows, row)
+		rows.byKey[row.key] = row
+	}
+
+	return rows, nil
+}
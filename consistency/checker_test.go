@@ -0,0 +1,81 @@
+package consistency
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+type mockConnSet struct {
+	primary *sql.DB
+	replica *sql.DB
+}
+
+func (r mockConnSet) ReadPool() *bun.DB {
+	return bun.NewDB(r.replica, pgdialect.New())
+}
+
+func (r mockConnSet) WritePool() *bun.DB {
+	return bun.NewDB(r.primary, pgdialect.New())
+}
+
+type testEnt struct {
+	bun.BaseModel `bun:"table:test_entities,alias:test_entities"`
+
+	ID   int    `bun:"id,pk" json:"id"`
+	Name string `bun:"name" json:"name"`
+}
+
+func (r testEnt) EntityName() string {
+	return "testEnt"
+}
+
+func (r testEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+func TestChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	primary, primaryMock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	replica, replicaMock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	query := "^SELECT \"test_entities\"\\.\"id\", md5\\(\"test_entities\"::text\\) AS hash FROM \"test_entities\" WHERE \\(\\(id\\) >= \\(1\\)\\) AND \\(\\(id\\) < \\(100\\)\\) ORDER BY \\(id\\)$"
+
+	primaryMock.ExpectQuery(query).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "hash"}).
+			AddRow(1, "a").
+			AddRow(2, "b").
+			AddRow(3, "c"),
+	)
+	replicaMock.ExpectQuery(query).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "hash"}).
+			AddRow(1, "a").
+			AddRow(2, "x").
+			AddRow(4, "d"),
+	)
+
+	checker := NewChecker[testEnt](mockConnSet{primary: primary, replica: replica})
+
+	report, err := checker.Check(context.Background(), Range{From: []any{1}, To: []any{100}})
+
+	assert.NoError(t, err)
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+	assert.True(t, report.HasDrift())
+	assert.Equal(t, 4, report.Checked)
+	assert.Equal(t, []Drift{
+		{PK: metadata.PrimaryKey{"id": int64(2)}, Kind: Changed},
+		{PK: metadata.PrimaryKey{"id": int64(3)}, Kind: MissingOnReplica},
+		{PK: metadata.PrimaryKey{"id": int64(4)}, Kind: ExtraOnReplica},
+	}, report.Drifts)
+}
@@ -0,0 +1,192 @@
+// Package entgraph exports the relation graph of entities registered in a metadata.EntityMetaContainer.
+package entgraph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aso779/crud-repository/entrel"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+)
+
+var (
+	ErrUnknownEntity  = errors.New("unknown entity")
+	ErrBrokenRelation = errors.New("relation without meta")
+)
+
+type Kind string
+
+const (
+	ToOne  Kind = "to_one"
+	ToMany Kind = "to_many"
+	// Other relation implemented outside entrel.
+	Other Kind = "other"
+)
+
+type Node struct {
+	Entity string
+	Table  string
+}
+
+// Edge is a relation registered on the From entity under Key.
+type Edge struct {
+	From string
+	To   string
+	Key  string
+	Kind Kind
+	// Via is the link table of to many relations.
+	Via string
+}
+
+// Graph lists nodes and edges sorted by entity name and relation key.
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+// Build walks relations of the given entities and of entities they reach.
+// The container can't list its entries, so root entity names are passed explicitly.
+func Build(container metadata.EntityMetaContainer, entities ...string) (Graph, error) {
+	b := builder{nodes: make(map[string]Node)}
+
+	for _, name := range entities {
+		m := container.Get(name)
+		if m == nil {
+			return Graph{}, fmt.Errorf("%s: %w", name, ErrUnknownEntity)
+		}
+
+		if err := b.walk(m); err != nil {
+			return Graph{}, err
+		}
+	}
+
+	return b.graph(), nil
+}
+
+type builder struct {
+	nodes map[string]Node
+	edges []Edge
+}
+
+func (b *builder) walk(m metadata.Meta) error {
+	if _, ok := b.nodes[m.EntityName()]; ok {
+		return nil
+	}
+
+	b.nodes[m.EntityName()] = Node{Entity: m.EntityName(), Table: m.PersistenceName()}
+
+	relations := m.Relations()
+
+	keys := make([]string, 0, len(relations))
+	for k := range relations {
+		// nested keys are reached through the related entity meta
+		if !strings.Contains(k, ".") {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		rel := relations[k]
+		if rel == nil || rel.GetMeta() == nil {
+			return fmt.Errorf("%s.%s: %w", m.EntityName(), k, ErrBrokenRelation)
+		}
+
+		edge := Edge{From: m.EntityName(), To: rel.GetMeta().EntityName(), Key: k, Kind: Other}
+
+		switch r := rel.(type) {
+		case entrel.ToOne, *entrel.ToOne:
+			edge.Kind = ToOne
+		case entrel.ToMany:
+			edge.Kind, edge.Via = ToMany, r.ViaTable
+		case *entrel.ToMany:
+			edge.Kind, edge.Via = ToMany, r.ViaTable
+		}
+
+		b.edges = append(b.edges, edge)
+
+		if err := b.walk(rel.GetMeta()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *builder) graph() Graph {
+	g := Graph{Edges: b.edges}
+
+	for _, n := range b.nodes {
+		g.Nodes = append(g.Nodes, n)
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].Entity < g.Nodes[j].Entity })
+	sort.SliceStable(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+
+		return g.Edges[i].Key < g.Edges[j].Key
+	})
+
+	return g
+}
+
+// DOT renders the graph in Graphviz format, to many edges are dashed.
+func (g Graph) DOT() string {
+	var sb strings.Builder
+
+	sb.WriteString("digraph entities {\n")
+
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&sb, "\t%q [label=%q];\n", n.Entity, n.Entity+"\n"+n.Table)
+	}
+
+	for _, e := range g.Edges {
+		label := e.Key
+		if e.Via != "" {
+			label += " via " + e.Via
+		}
+
+		style := ""
+		if e.Kind == ToMany {
+			style = ", style=dashed"
+		}
+
+		fmt.Fprintf(&sb, "\t%q -> %q [label=%q%s];\n", e.From, e.To, label, style)
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart, to many edges are dotted.
+func (g Graph) Mermaid() string {
+	var sb strings.Builder
+
+	sb.WriteString("flowchart LR\n")
+
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&sb, "    %s[\"%s<br/>%s\"]\n", n.Entity, n.Entity, n.Table)
+	}
+
+	for _, e := range g.Edges {
+		label := e.Key
+		if e.Via != "" {
+			label += " via " + e.Via
+		}
+
+		arrow := "-->"
+		if e.Kind == ToMany {
+			arrow = "-.->"
+		}
+
+		fmt.Fprintf(&sb, "    %s %s|%s| %s\n", e.From, arrow, label, e.To)
+	}
+
+	return sb.String()
+}
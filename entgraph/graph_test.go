@@ -0,0 +1,132 @@
+package entgraph
+
+import (
+	"testing"
+
+	"github.com/aso779/crud-repository/entrel"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/entmeta"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type testCustomer struct {
+	bun.BaseModel `bun:"table:customers,alias:customers"`
+
+	ID int `bun:"id,pk" json:"id"`
+}
+
+func (r testCustomer) EntityName() string { return "Customer" }
+
+func (r testCustomer) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"id": r.ID} }
+
+type testCustomerMeta struct{ testCustomer }
+
+func (r testCustomerMeta) Entity() metadata.Entity { return r.testCustomer }
+
+func (r testCustomerMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+type testProduct struct {
+	bun.BaseModel `bun:"table:products,alias:products"`
+
+	ID int `bun:"id,pk" json:"id"`
+}
+
+func (r testProduct) EntityName() string { return "Product" }
+
+func (r testProduct) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"id": r.ID} }
+
+type testProductMeta struct{ testProduct }
+
+func (r testProductMeta) Entity() metadata.Entity { return r.testProduct }
+
+func (r testProductMeta) Relations() map[string]metadata.Relation {
+	return map[string]metadata.Relation{
+		"Vendor": entrel.ToOne{Meta: meta.Parser(testCustomerMeta{}), JoinTable: "customers"},
+	}
+}
+
+type testOrder struct {
+	bun.BaseModel `bun:"table:orders,alias:orders"`
+
+	ID int `bun:"id,pk" json:"id"`
+}
+
+func (r testOrder) EntityName() string { return "Order" }
+
+func (r testOrder) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"id": r.ID} }
+
+type testOrderMeta struct{ testOrder }
+
+func (r testOrderMeta) Entity() metadata.Entity { return r.testOrder }
+
+func (r testOrderMeta) Relations() map[string]metadata.Relation {
+	return map[string]metadata.Relation{
+		"Customer": entrel.ToOne{Meta: meta.Parser(testCustomerMeta{}), JoinTable: "customers"},
+		"Products": entrel.ToMany{Meta: meta.Parser(testProductMeta{}), JoinTable: "products", ViaTable: "order_products"},
+	}
+}
+
+type stubContainer map[string]metadata.Meta
+
+func (r stubContainer) Add(_ metadata.EntityMetaDecorator, _ metadata.MetaParser) {}
+
+func (r stubContainer) Get(entName string) metadata.Meta { return r[entName] }
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	c := entmeta.NewContainer()
+	c.Add(testOrderMeta{}, meta.Parser)
+	c.Add(testCustomerMeta{}, meta.Parser)
+
+	g, err := Build(c, "Order", "Customer")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Node{
+		{Entity: "Customer", Table: "customers"},
+		{Entity: "Order", Table: "orders"},
+		{Entity: "Product", Table: "products"},
+	}, g.Nodes)
+	assert.Equal(t, []Edge{
+		{From: "Order", To: "Customer", Key: "Customer", Kind: ToOne},
+		{From: "Order", To: "Product", Key: "Products", Kind: ToMany, Via: "order_products"},
+		{From: "Product", To: "Customer", Key: "Vendor", Kind: ToOne},
+	}, g.Edges)
+
+	assert.Equal(t, `digraph entities {
+	"Customer" [label="Customer\ncustomers"];
+	"Order" [label="Order\norders"];
+	"Product" [label="Product\nproducts"];
+	"Order" -> "Customer" [label="Customer"];
+	"Order" -> "Product" [label="Products via order_products", style=dashed];
+	"Product" -> "Customer" [label="Vendor"];
+}
+`, g.DOT())
+
+	assert.Equal(t, `flowchart LR
+    Customer["Customer<br/>customers"]
+    Order["Order<br/>orders"]
+    Product["Product<br/>products"]
+    Order -->|Customer| Customer
+    Order -.->|Products via order_products| Product
+    Product -->|Vendor| Customer
+`, g.Mermaid())
+}
+
+func TestBuild_Errors(t *testing.T) {
+	t.Parallel()
+
+	broken := entmeta.NewMeta()
+	broken.SetEntityName("Order")
+	broken.SetRelations(map[string]metadata.Relation{"Customer": entrel.ToOne{JoinTable: "customers"}})
+
+	c := stubContainer{"Order": broken}
+
+	_, err := Build(c, "Missing")
+	assert.ErrorIs(t, err, ErrUnknownEntity)
+
+	_, err = Build(c, "Order")
+	assert.ErrorIs(t, err, ErrBrokenRelation)
+}
@@ -0,0 +1,177 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const defaultBatchSize = 100
+
+// Checkpoint last global position processed by a projection.
+type Checkpoint struct {
+	bun.BaseModel `bun:"table:projection_checkpoints,alias:projection_checkpoints"`
+
+	Name      string    `bun:"name,pk" json:"name"`
+	Position  int64     `bun:"position" json:"position"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updatedAt"`
+}
+
+// Handler applies an event to read models inside the projection transaction.
+type Handler func(ctx context.Context, tx bun.Tx, event Event) error
+
+// Projection replays store events through handlers keyed by event type.
+// Each batch and its checkpoint are committed in one transaction, so a restarted projection
+// resumes after the last committed batch. Events without a handler are skipped.
+//
+// Store appends are serialized until commit, so no event gets committed below a processed position.
+type Projection struct {
+	Name      string
+	Store     Store
+	Handlers  map[string]Handler
+	BatchSize int
+}
+
+func NewProjection(name string, store Store, handlers map[string]Handler) Projection {
+	return Projection{
+		Name:      name,
+		Store:     store,
+		Handlers:  handlers,
+		BatchSize: defaultBatchSize,
+	}
+}
+
+// Run processes events until the projection catches up and returns the number of processed events.
+func (r Projection) Run(ctx context.Context) (int, error) {
+	var total int
+
+	for {
+		processed, err := r.runBatch(ctx)
+		total += processed
+
+		if err != nil {
+			return total, fmt.Errorf("run projection %s: %w", r.Name, err)
+		}
+
+		if processed < r.batchSize() {
+			return total, nil
+		}
+	}
+}
+
+// Rebuild resets the checkpoint, calls reset to clear read models in the same transaction and replays all events.
+func (r Projection) Rebuild(
+	ctx context.Context,
+	reset func(ctx context.Context, tx bun.Tx) error,
+) (int, error) {
+	err := r.Store.ConnSet.WritePool().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if reset != nil {
+			if err := reset(ctx, tx); err != nil {
+				return err
+			}
+		}
+
+		return r.saveCheckpoint(ctx, tx, 0)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("rebuild projection %s: %w", r.Name, err)
+	}
+
+	return r.Run(ctx)
+}
+
+// Position returns the last processed global position.
+func (r Projection) Position(ctx context.Context, tx bun.IDB) (int64, error) {
+	var checkpoint Checkpoint
+
+	if tx == nil {
+		tx = r.Store.ConnSet.ReadPool()
+	}
+
+	err := tx.NewSelect().
+		Model(&checkpoint).
+		Where("name = ?", r.Name).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("position: %w", err)
+	}
+
+	return checkpoint.Position, nil
+}
+
+func (r Projection) runBatch(ctx context.Context) (int, error) {
+	var processed int
+
+	err := r.Store.ConnSet.WritePool().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		checkpoint := Checkpoint{Name: r.Name}
+
+		_, err := tx.NewInsert().
+			Model(&checkpoint).
+			On("CONFLICT (name) DO NOTHING").
+			Returning("NULL").
+			Exec(ctx)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		err = tx.NewSelect().
+			Model(&checkpoint).
+			Where("name = ?", r.Name).
+			For("UPDATE").
+			Scan(ctx)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		events, err := r.Store.ReadAll(ctx, tx, checkpoint.Position, r.batchSize())
+		if err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		for _, e := range events {
+			if h, ok := r.Handlers[e.Type]; ok {
+				if err = h(ctx, tx, e); err != nil {
+					return fmt.Errorf("handle %s at %d: %w", e.Type, e.Position, err)
+				}
+			}
+		}
+
+		processed = len(events)
+
+		return r.saveCheckpoint(ctx, tx, events[len(events)-1].Position)
+	})
+
+	return processed, err //nolint:wrapcheck
+}
+
+func (r Projection) saveCheckpoint(ctx context.Context, tx bun.Tx, position int64) error {
+	_, err := tx.NewInsert().
+		Model(&Checkpoint{Name: r.Name, Position: position}).
+		On("CONFLICT (name) DO UPDATE").
+		Set("position = EXCLUDED.position").
+		Set("updated_at = current_timestamp").
+		Returning("NULL").
+		Exec(ctx)
+
+	return err //nolint:wrapcheck
+}
+
+func (r Projection) batchSize() int {
+	if r.BatchSize <= 0 {
+		return defaultBatchSize
+	}
+
+	return r.BatchSize
+}
@@ -0,0 +1,88 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+var errHandler = errors.New("handler failed")
+
+const (
+	checkpointInsert = `INSERT INTO "projection_checkpoints" AS "projection_checkpoints" ("name", "position", "updated_at") VALUES ('orders', 0, DEFAULT) ON CONFLICT (name) DO NOTHING`
+	checkpointSelect = `SELECT "projection_checkpoints"."name", "projection_checkpoints"."position", "projection_checkpoints"."updated_at" FROM "projection_checkpoints" WHERE (name = 'orders') FOR UPDATE`
+)
+
+func TestProjection_Run(t *testing.T) {
+	t.Parallel()
+
+	store, mock := storeSetUp(t)
+
+	var handled []string
+
+	projection := NewProjection("orders", store, map[string]Handler{
+		"Paid": func(ctx context.Context, tx bun.Tx, event Event) error {
+			handled = append(handled, event.StreamID)
+
+			return nil
+		},
+	})
+	projection.BatchSize = 2
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(checkpointInsert)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(checkpointSelect)).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "position"}).AddRow("orders", 4))
+	mock.ExpectQuery(`^SELECT .* FROM "events" WHERE \(position > 4\) ORDER BY "position" LIMIT 2$`).
+		WillReturnRows(sqlmock.NewRows([]string{"stream_id", "position", "type"}).
+			AddRow("order-1", 5, "Paid").
+			AddRow("order-2", 6, "Created"))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "projection_checkpoints" AS "projection_checkpoints" ("name", "position", "updated_at") VALUES ('orders', 6, DEFAULT) ON CONFLICT (name) DO UPDATE SET position = EXCLUDED.position, updated_at = current_timestamp`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(checkpointInsert)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(checkpointSelect)).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "position"}).AddRow("orders", 6))
+	mock.ExpectQuery(`^SELECT .* FROM "events" WHERE \(position > 6\) ORDER BY "position" LIMIT 2$`).
+		WillReturnRows(sqlmock.NewRows([]string{"stream_id", "position", "type"}))
+	mock.ExpectCommit()
+
+	processed, err := projection.Run(context.Background())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, processed)
+	assert.Equal(t, []string{"order-1"}, handled)
+}
+
+func TestProjection_RunHandlerError(t *testing.T) {
+	t.Parallel()
+
+	store, mock := storeSetUp(t)
+
+	projection := NewProjection("orders", store, map[string]Handler{
+		"Paid": func(ctx context.Context, tx bun.Tx, event Event) error {
+			return errHandler
+		},
+	})
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(checkpointInsert)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(checkpointSelect)).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "position"}).AddRow("orders", 0))
+	mock.ExpectQuery(`^SELECT .* FROM "events" WHERE \(position > 0\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"stream_id", "position", "type"}).AddRow("order-1", 1, "Paid"))
+	mock.ExpectRollback()
+
+	processed, err := projection.Run(context.Background())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.ErrorIs(t, err, errHandler)
+	assert.Equal(t, 0, processed)
+}
@@ -0,0 +1,310 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aso779/bun-pg-connector"
+	"github.com/aso779/crud-repository/codec"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrVersionConflict = errors.New("stream version conflict")
+	ErrNoSnapshot      = errors.New("no snapshot")
+)
+
+// Event persisted event. Version is the 1-based position inside the stream,
+// Position is the global append order used by projections.
+type Event struct {
+	bun.BaseModel `bun:"table:events,alias:events"`
+
+	StreamID  string    `bun:"stream_id,pk" json:"streamId"`
+	Version   int64     `bun:"version,pk" json:"version"`
+	Position  int64     `bun:"position,autoincrement" json:"position"`
+	Type      string    `bun:"type" json:"type"`
+	Data      []byte    `bun:"data,type:bytea" json:"data"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"createdAt"`
+}
+
+type Snapshot struct {
+	bun.BaseModel `bun:"table:event_snapshots,alias:event_snapshots"`
+
+	StreamID  string    `bun:"stream_id,pk" json:"streamId"`
+	Version   int64     `bun:"version" json:"version"`
+	Data      []byte    `bun:"data,type:bytea" json:"data"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"createdAt"`
+}
+
+// Message event to append.
+type Message struct {
+	Type string
+	Data any
+}
+
+// Store append-only event store backed by the events and event_snapshots tables.
+type Store struct {
+	ConnSet bunpgconnector.BunConnSet
+	Codec   codec.Codec
+}
+
+func NewStore(connSet bunpgconnector.BunConnSet) Store {
+	return Store{
+		ConnSet: connSet,
+		Codec:   codec.JSON{},
+	}
+}
+
+// appendLockKey is the advisory lock serializing appends, so positions are assigned in commit order
+// and projections reading past their checkpoint never skip an event committed late with a lower position.
+const appendLockKey int64 = 0x6576656e7473
+
+// Append appends messages to the stream if its current version equals expectedVersion.
+// Use 0 as expected version for a new stream.
+//
+// Appends hold a transaction-level advisory lock until commit, so concurrent appends of all streams
+// are serialized; keep transactions passed as tx short. Without a transaction Append opens one.
+func (r Store) Append(
+	ctx context.Context,
+	tx bun.IDB,
+	streamID string,
+	expectedVersion int64,
+	messages ...Message,
+) ([]Event, error) {
+	if t, ok := tx.(bun.Tx); ok {
+		return r.append(ctx, t, streamID, expectedVersion, messages)
+	}
+
+	if tx == nil {
+		tx = r.ConnSet.WritePool()
+	}
+
+	var events []Event
+
+	err := tx.RunInTx(ctx, nil, func(ctx context.Context, t bun.Tx) error {
+		var err error
+
+		events, err = r.append(ctx, t, streamID, expectedVersion, messages)
+
+		return err
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return events, nil
+}
+
+func (r Store) append(
+	ctx context.Context,
+	tx bun.Tx,
+	streamID string,
+	expectedVersion int64,
+	messages []Message,
+) ([]Event, error) {
+	events := make([]Event, 0, len(messages))
+
+	for i, m := range messages {
+		data, err := r.Codec.Marshal(m.Data)
+		if err != nil {
+			return nil, fmt.Errorf("append: %w", err)
+		}
+
+		events = append(events, Event{
+			StreamID: streamID,
+			Version:  expectedVersion + int64(i) + 1,
+			Type:     m.Type,
+			Data:     data,
+		})
+	}
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(?)", appendLockKey); err != nil {
+		return nil, fmt.Errorf("append: %w", err)
+	}
+
+	current, err := r.Version(ctx, tx, streamID)
+	if err != nil {
+		return nil, fmt.Errorf("append: %w", err)
+	}
+
+	if current != expectedVersion {
+		return nil, fmt.Errorf("append: expected %d, actual %d: %w", expectedVersion, current, ErrVersionConflict)
+	}
+
+	if len(events) == 0 {
+		return events, nil
+	}
+
+	_, err = tx.NewInsert().
+		Model(&events).
+		Returning("position, created_at").
+		Exec(ctx)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("append: %w", ErrVersionConflict)
+		}
+
+		return nil, fmt.Errorf("append: %w", err)
+	}
+
+	return events, nil
+}
+
+// Version returns the current stream version, 0 for an empty stream.
+func (r Store) Version(ctx context.Context, tx bun.IDB, streamID string) (int64, error) {
+	var version int64
+
+	if tx == nil {
+		tx = r.ConnSet.ReadPool()
+	}
+
+	err := tx.NewSelect().
+		Model((*Event)(nil)).
+		ColumnExpr("coalesce(max(version), 0)").
+		Where("stream_id = ?", streamID).
+		Scan(ctx, &version)
+	if err != nil {
+		return 0, fmt.Errorf("version: %w", err)
+	}
+
+	return version, nil
+}
+
+// ReadStream returns up to limit stream events starting from fromVersion inclusive.
+func (r Store) ReadStream(
+	ctx context.Context,
+	tx bun.IDB,
+	streamID string,
+	fromVersion int64,
+	limit int,
+) ([]Event, error) {
+	var events = make([]Event, 0)
+
+	if tx == nil {
+		tx = r.ConnSet.ReadPool()
+	}
+
+	err := tx.NewSelect().
+		Model(&events).
+		Where("stream_id = ?", streamID).
+		Where("version >= ?", fromVersion).
+		Order("version").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return events, fmt.Errorf("read stream: %w", err)
+	}
+
+	return events, nil
+}
+
+// ReadAll returns up to limit events of all streams appended after the given global position.
+// Appends are serialized, so positions grow in commit order and no event can show up below a read position.
+func (r Store) ReadAll(
+	ctx context.Context,
+	tx bun.IDB,
+	afterPosition int64,
+	limit int,
+) ([]Event, error) {
+	var events = make([]Event, 0)
+
+	if tx == nil {
+		tx = r.ConnSet.ReadPool()
+	}
+
+	err := tx.NewSelect().
+		Model(&events).
+		Where("position > ?", afterPosition).
+		Order("position").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return events, fmt.Errorf("read all: %w", err)
+	}
+
+	return events, nil
+}
+
+// Decode decodes event data into value.
+func (r Store) Decode(event Event, value any) error {
+	if err := r.Codec.Unmarshal(event.Data, value); err != nil {
+		return fmt.Errorf("decode %s: %w", event.Type, err)
+	}
+
+	return nil
+}
+
+// SaveSnapshot stores the aggregate state at the given stream version, replacing the previous snapshot.
+func (r Store) SaveSnapshot(
+	ctx context.Context,
+	tx bun.IDB,
+	streamID string,
+	version int64,
+	state any,
+) error {
+	if tx == nil {
+		tx = r.ConnSet.WritePool()
+	}
+
+	data, err := r.Codec.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("save snapshot: %w", err)
+	}
+
+	_, err = tx.NewInsert().
+		Model(&Snapshot{StreamID: streamID, Version: version, Data: data}).
+		On("CONFLICT (stream_id) DO UPDATE").
+		Set("version = EXCLUDED.version").
+		Set("data = EXCLUDED.data").
+		Set("created_at = EXCLUDED.created_at").
+		Returning("NULL").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("save snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSnapshot decodes the latest snapshot into state and returns its version.
+func (r Store) LoadSnapshot(
+	ctx context.Context,
+	tx bun.IDB,
+	streamID string,
+	state any,
+) (int64, error) {
+	var snapshot Snapshot
+
+	if tx == nil {
+		tx = r.ConnSet.ReadPool()
+	}
+
+	err := tx.NewSelect().
+		Model(&snapshot).
+		Where("stream_id = ?", streamID).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("load snapshot: %w", ErrNoSnapshot)
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("load snapshot: %w", err)
+	}
+
+	if err = r.Codec.Unmarshal(snapshot.Data, state); err != nil {
+		return 0, fmt.Errorf("load snapshot: %w", err)
+	}
+
+	return snapshot.Version, nil
+}
+
+const uniqueViolation = "23505"
+
+func isUniqueViolation(err error) bool {
+	var pgErr interface{ Field(k byte) string }
+
+	return errors.As(err, &pgErr) && pgErr.Field('C') == uniqueViolation
+}
@@ -0,0 +1,203 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+type mockConnSet struct {
+	db *sql.DB
+}
+
+func (r mockConnSet) ReadPool() *bun.DB {
+	return bun.NewDB(r.db, pgdialect.New())
+}
+
+func (r mockConnSet) WritePool() *bun.DB {
+	return bun.NewDB(r.db, pgdialect.New())
+}
+
+func storeSetUp(t *testing.T) (Store, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	return NewStore(mockConnSet{db: db}), mock
+}
+
+type pgError struct {
+	code string
+}
+
+func (r pgError) Field(k byte) string {
+	if k == 'C' {
+		return r.code
+	}
+
+	return ""
+}
+
+func (r pgError) Error() string {
+	return "pg error " + r.code
+}
+
+const (
+	versionQuery = `SELECT coalesce(max(version), 0) FROM "events" WHERE (stream_id = 'order-1')`
+	appendLock   = `SELECT pg_advisory_xact_lock(111559182283891)`
+)
+
+func TestStore_Append(t *testing.T) {
+	t.Parallel()
+
+	store, mock := storeSetUp(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(appendLock)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(versionQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "events" ("stream_id", "version", "position", "type", "data", "created_at") VALUES ('order-1', 2, DEFAULT, 'Paid', '\x7b22616d6f756e74223a31307d', DEFAULT), ('order-1', 3, DEFAULT, 'Shipped', '\x6e756c6c', DEFAULT) RETURNING position, created_at`)).
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(10).AddRow(11))
+	mock.ExpectCommit()
+
+	events, err := store.Append(
+		context.Background(),
+		nil,
+		"order-1",
+		1,
+		Message{Type: "Paid", Data: map[string]int{"amount": 10}},
+		Message{Type: "Shipped"},
+	)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, int64(3), events[1].Version)
+	assert.Equal(t, int64(11), events[1].Position)
+}
+
+func TestStore_AppendVersionConflict(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		mock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "stale expected version",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(versionQuery)).
+					WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(2))
+			},
+		},
+		{
+			name: "concurrent append",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(versionQuery)).
+					WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(1))
+				mock.ExpectQuery(`^INSERT INTO "events"`).
+					WillReturnError(pgError{code: "23505"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store, mock := storeSetUp(t)
+
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(appendLock)).WillReturnResult(sqlmock.NewResult(0, 0))
+			tt.mock(mock)
+			mock.ExpectRollback()
+
+			_, err := store.Append(context.Background(), nil, "order-1", 1, Message{Type: "Paid"})
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+			assert.ErrorIs(t, err, ErrVersionConflict)
+		})
+	}
+}
+
+// TestStore_AppendInTx checks the append lock is taken in the caller transaction before the position is
+// assigned, a concurrent append waits for the commit and can't commit an event below a read position.
+func TestStore_AppendInTx(t *testing.T) {
+	t.Parallel()
+
+	store, mock := storeSetUp(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(appendLock)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(versionQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(0))
+	mock.ExpectQuery(`^INSERT INTO "events"`).
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err := store.ConnSet.WritePool().RunInTx(context.Background(), nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := store.Append(ctx, tx, "order-1", 0, Message{Type: "Created"})
+
+		return err
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+}
+
+func TestStore_ReadStream(t *testing.T) {
+	t.Parallel()
+
+	store, mock := storeSetUp(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "events"."stream_id", "events"."version", "events"."position", "events"."type", "events"."data", "events"."created_at" FROM "events" WHERE (stream_id = 'order-1') AND (version >= 2) ORDER BY "version" LIMIT 10`)).
+		WillReturnRows(sqlmock.NewRows([]string{"stream_id", "version", "type", "data"}).
+			AddRow("order-1", 2, "Paid", []byte(`{"amount":10}`)))
+
+	events, err := store.ReadStream(context.Background(), nil, "order-1", 2, 10)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(events))
+
+	var data map[string]int
+
+	assert.NoError(t, store.Decode(events[0], &data))
+	assert.Equal(t, 10, data["amount"])
+}
+
+func TestStore_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	store, mock := storeSetUp(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "event_snapshots" AS "event_snapshots" ("stream_id", "version", "data", "created_at") VALUES ('order-1', 3, '\x7b22746f74616c223a357d', DEFAULT) ON CONFLICT (stream_id) DO UPDATE SET version = EXCLUDED.version, data = EXCLUDED.data, created_at = EXCLUDED.created_at`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`^SELECT .* FROM "event_snapshots" WHERE \(stream_id = 'order-1'\)$`).
+		WillReturnRows(sqlmock.NewRows([]string{"stream_id", "version", "data"}).
+			AddRow("order-1", 3, []byte(`{"total":5}`)))
+	mock.ExpectQuery(`^SELECT .* FROM "event_snapshots" WHERE \(stream_id = 'order-2'\)$`).
+		WillReturnRows(sqlmock.NewRows([]string{"stream_id", "version", "data"}))
+
+	assert.NoError(t, store.SaveSnapshot(context.Background(), nil, "order-1", 3, map[string]int{"total": 5}))
+
+	var state map[string]int
+
+	version, err := store.LoadSnapshot(context.Background(), nil, "order-1", &state)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+	assert.Equal(t, 5, state["total"])
+
+	_, err = store.LoadSnapshot(context.Background(), nil, "order-2", &state)
+	assert.ErrorIs(t, err, ErrNoSnapshot)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
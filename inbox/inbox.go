@@ -0,0 +1,102 @@
+package inbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aso779/bun-pg-connector"
+	"github.com/uptrace/bun"
+)
+
+// Message processed message record.
+type Message struct {
+	bun.BaseModel `bun:"table:inbox_messages,alias:inbox_messages"`
+
+	Consumer    string    `bun:"consumer,pk" json:"consumer"`
+	ID          string    `bun:"id,pk" json:"id"`
+	ProcessedAt time.Time `bun:"processed_at,nullzero,notnull,default:current_timestamp" json:"processedAt"`
+}
+
+// Inbox deduplicates message redeliveries per consumer by recording processed message ids
+// in the same transaction as the consumer writes.
+type Inbox struct {
+	ConnSet  bunpgconnector.BunConnSet
+	Consumer string
+}
+
+func NewInbox(connSet bunpgconnector.BunConnSet, consumer string) Inbox {
+	return Inbox{
+		ConnSet:  connSet,
+		Consumer: consumer,
+	}
+}
+
+// Process records the message and runs fn in one transaction. It returns false without calling fn
+// when the message has already been processed. An fn error rolls back both.
+func (r Inbox) Process(
+	ctx context.Context,
+	messageID string,
+	fn func(ctx context.Context, tx bun.Tx) error,
+) (bool, error) {
+	var recorded bool
+
+	err := r.ConnSet.WritePool().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+
+		recorded, err = r.Record(ctx, tx, messageID)
+		if err != nil || !recorded {
+			return err
+		}
+
+		return fn(ctx, tx)
+	})
+	if err != nil {
+		return false, fmt.Errorf("process %s: %w", messageID, err)
+	}
+
+	return recorded, nil
+}
+
+// Record marks the message processed within tx and returns false if it was already recorded.
+func (r Inbox) Record(ctx context.Context, tx bun.IDB, messageID string) (bool, error) {
+	if tx == nil {
+		tx = r.ConnSet.WritePool()
+	}
+
+	res, err := tx.NewInsert().
+		Model(&Message{Consumer: r.Consumer, ID: messageID}).
+		On("CONFLICT (consumer, id) DO NOTHING").
+		Returning("NULL").
+		Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("record: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("record: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// Purge removes records processed more than olderThan ago. Redeliveries after that are not deduplicated.
+func (r Inbox) Purge(ctx context.Context, tx bun.IDB, olderThan time.Duration) (int, error) {
+	if tx == nil {
+		tx = r.ConnSet.WritePool()
+	}
+
+	res, err := tx.NewDelete().
+		Model((*Message)(nil)).
+		Where("consumer = ?", r.Consumer).
+		Where("processed_at < ?", time.Now().Add(-olderThan)).
+		Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("purge: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+
+	return int(rows), err //nolint:wrapcheck
+}
@@ -0,0 +1,121 @@
+package inbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+var errConsumer = errors.New("consumer failed")
+
+type mockConnSet struct {
+	db *sql.DB
+}
+
+func (r mockConnSet) ReadPool() *bun.DB {
+	return bun.NewDB(r.db, pgdialect.New())
+}
+
+func (r mockConnSet) WritePool() *bun.DB {
+	return bun.NewDB(r.db, pgdialect.New())
+}
+
+const recordQuery = `INSERT INTO "inbox_messages" AS "inbox_messages" ("consumer", "id", "processed_at") VALUES ('billing', 'm-1', DEFAULT) ON CONFLICT (consumer, id) DO NOTHING`
+
+func TestInbox_Process(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(mock sqlmock.Sqlmock)
+		fn       func(ctx context.Context, tx bun.Tx) error
+		expected func(t *testing.T, processed bool, called bool, err error)
+	}{
+		{
+			name: "first delivery",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(recordQuery)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context, tx bun.Tx) error {
+				return nil
+			},
+			expected: func(t *testing.T, processed bool, called bool, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.True(t, processed)
+				assert.True(t, called)
+			},
+		},
+		{
+			name: "redelivery",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(recordQuery)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context, tx bun.Tx) error {
+				return nil
+			},
+			expected: func(t *testing.T, processed bool, called bool, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.False(t, processed)
+				assert.False(t, called)
+			},
+		},
+		{
+			name: "consumer error rolls back",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(recordQuery)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectRollback()
+			},
+			fn: func(ctx context.Context, tx bun.Tx) error {
+				return errConsumer
+			},
+			expected: func(t *testing.T, processed bool, called bool, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, errConsumer)
+				assert.False(t, processed)
+				assert.True(t, called)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+
+			tt.mock(mock)
+
+			var called bool
+
+			processed, err := NewInbox(mockConnSet{db: db}, "billing").Process(
+				context.Background(),
+				"m-1",
+				func(ctx context.Context, tx bun.Tx) error {
+					called = true
+
+					return tt.fn(ctx, tx)
+				},
+			)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			tt.expected(t, processed, called, err)
+		})
+	}
+}
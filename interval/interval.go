@@ -0,0 +1,141 @@
+// Package interval maps PostgreSQL interval columns to time.Duration.
+//
+// Plain time.Duration fields scan and bind as integers, so interval columns use Duration instead,
+// it converts to and from time.Duration without loss down to microseconds.
+package interval
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Units PostgreSQL uses for intervals with day or month parts when converting them to seconds.
+const (
+	Day   = 24 * time.Hour
+	Month = 30 * Day
+	Year  = 365*Day + 6*time.Hour
+)
+
+var ErrInvalidInterval = errors.New("invalid interval")
+
+// Duration is a time.Duration stored as a PostgreSQL interval.
+type Duration time.Duration
+
+// Std returns the duration as time.Duration.
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+// String returns the interval literal Value binds.
+func (d Duration) String() string {
+	return strconv.FormatInt(time.Duration(d).Microseconds(), 10) + " microseconds"
+}
+
+// Value implements driver.Valuer.
+func (d Duration) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implements sql.Scanner for intervals in the default postgres output style.
+func (d *Duration) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*d = 0
+
+		return nil
+	case []byte:
+		return d.parse(string(v))
+	case string:
+		return d.parse(v)
+	case int64:
+		*d = Duration(v)
+
+		return nil
+	default:
+		return fmt.Errorf("%T: %w", src, ErrInvalidInterval)
+	}
+}
+
+// parse parses values like "1 year 2 mons -3 days +04:05:06.789".
+func (d *Duration) parse(s string) error {
+	var res time.Duration
+
+	fields := strings.Fields(s)
+
+	for i := 0; i < len(fields); i++ {
+		if strings.Contains(fields[i], ":") {
+			t, err := parseClock(fields[i])
+			if err != nil {
+				return fmt.Errorf("%q: %w", s, err)
+			}
+
+			res += t
+
+			continue
+		}
+
+		if i+1 == len(fields) {
+			return fmt.Errorf("%q: %w", s, ErrInvalidInterval)
+		}
+
+		n, err := strconv.ParseInt(fields[i], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q: %w", s, ErrInvalidInterval)
+		}
+
+		i++
+
+		switch strings.TrimSuffix(fields[i], "s") {
+		case "year":
+			res += time.Duration(n) * Year
+		case "mon":
+			res += time.Duration(n) * Month
+		case "day":
+			res += time.Duration(n) * Day
+		default:
+			return fmt.Errorf("%q: %w", s, ErrInvalidInterval)
+		}
+	}
+
+	*d = Duration(res)
+
+	return nil
+}
+
+// parseClock parses a signed [-+]HH:MM:SS[.ffffff] part.
+func parseClock(s string) (time.Duration, error) {
+	sign := time.Duration(1)
+
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, ErrInvalidInterval
+	}
+
+	h, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, ErrInvalidInterval
+	}
+
+	m, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, ErrInvalidInterval
+	}
+
+	sec, err := time.ParseDuration(parts[2] + "s")
+	if err != nil || strings.HasPrefix(parts[2], "-") {
+		return 0, ErrInvalidInterval
+	}
+
+	return sign * (time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + sec), nil
+}
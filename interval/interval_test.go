@@ -0,0 +1,55 @@
+package interval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuration_Scan(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		src      any
+		expected time.Duration
+		err      error
+	}{
+		{name: "null", src: nil},
+		{name: "clock", src: []byte("01:02:03"), expected: time.Hour + 2*time.Minute + 3*time.Second},
+		{name: "fraction", src: "00:00:00.000123", expected: 123 * time.Microsecond},
+		{name: "negative clock", src: "-00:00:01", expected: -time.Second},
+		{name: "days", src: "2 days 01:00:00", expected: 49 * time.Hour},
+		{name: "mixed signs", src: "-1 days +02:00:00", expected: -22 * time.Hour},
+		{name: "months and years", src: "1 year 1 mon", expected: Year + Month},
+		{name: "microseconds", src: int64(time.Second), expected: time.Second},
+		{name: "unknown unit", src: "1 week", err: ErrInvalidInterval},
+		{name: "iso style", src: "P1D", err: ErrInvalidInterval},
+		{name: "bad clock", src: "01:02", err: ErrInvalidInterval},
+		{name: "unsupported type", src: 1.5, err: ErrInvalidInterval},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var d Duration
+
+			err := d.Scan(tt.src)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, d.Std())
+		})
+	}
+}
+
+func TestDuration_Value(t *testing.T) {
+	t.Parallel()
+
+	v, err := Duration(-90 * time.Minute).Value()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "-5400000000 microseconds", v)
+}
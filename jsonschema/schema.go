@@ -0,0 +1,152 @@
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/aso779/crud-repository/meta"
+)
+
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+var ErrUnknownEntity = errors.New("unknown entity")
+
+// Schema is the JSON Schema subset generated for entities, it is a valid OpenAPI 3.1 schema object as well.
+type Schema struct {
+	Schema     string             `json:"$schema,omitempty"`
+	Title      string             `json:"title,omitempty"`
+	Type       any                `json:"type,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	Minimum    *int               `json:"minimum,omitempty"`
+	Enum       []any              `json:"enum,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+}
+
+// Entity returns the JSON Schema document of the registered entity, properties are named by presenter names.
+func Entity(container *meta.Container, name string) (*Schema, error) {
+	s, err := entity(container, name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Schema = Draft
+
+	return s, nil
+}
+
+// Components returns schemas of all registered entities by entity name,
+// for the components.schemas section of an OpenAPI document.
+func Components(container *meta.Container) (map[string]*Schema, error) {
+	res := make(map[string]*Schema)
+
+	for _, name := range container.Entities() {
+		s, err := entity(container, name)
+		if err != nil {
+			return nil, err
+		}
+
+		res[name] = s
+	}
+
+	return res, nil
+}
+
+func entity(container *meta.Container, name string) (*Schema, error) {
+	info, ok := container.Describe(name)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, ErrUnknownEntity)
+	}
+
+	s := &Schema{
+		Title:      name,
+		Type:       "object",
+		Properties: make(map[string]*Schema, len(info.Columns)),
+	}
+
+	for _, c := range info.Columns {
+		prop, nullable := typeSchema(c.Type)
+		prop.Enum = info.Enums[c.Presenter]
+
+		if nullable && prop.Type != nil {
+			prop.Type = []any{prop.Type, "null"}
+
+			if prop.Enum != nil {
+				prop.Enum = append(prop.Enum[:len(prop.Enum):len(prop.Enum)], nil)
+			}
+		}
+
+		if !nullable {
+			s.Required = append(s.Required, c.Presenter)
+		}
+
+		s.Properties[c.Presenter] = prop
+	}
+
+	return s, nil
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// typeSchema maps the Go type to a schema, pointers, slices and maps are nullable.
+//
+//nolint:cyclop
+func typeSchema(t reflect.Type) (*Schema, bool) {
+	if t.Kind() == reflect.Pointer {
+		s, _ := typeSchema(t.Elem())
+
+		return s, true
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}, false
+	case t.Implements(jsonMarshalerType) && !t.Implements(textMarshalerType):
+		// custom JSON encoding, the shape is unknown
+		return &Schema{}, false
+	case t.Implements(textMarshalerType):
+		return &Schema{Type: "string"}, false
+	}
+
+	zero := 0
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, false
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}, false
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}, false
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: &zero}, false
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}, false
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}, false
+	case reflect.String:
+		return &Schema{Type: "string"}, false
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}, t.Kind() == reflect.Slice
+		}
+
+		items, _ := typeSchema(t.Elem())
+
+		return &Schema{Type: "array", Items: items}, t.Kind() == reflect.Slice
+	case reflect.Map:
+		return &Schema{Type: "object"}, true
+	case reflect.Struct:
+		return &Schema{Type: "object"}, false
+	default:
+		return &Schema{}, false
+	}
+}
@@ -0,0 +1,76 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type testOrder struct {
+	bun.BaseModel `bun:"table:orders,alias:orders"`
+
+	ID        uint            `bun:"id,pk" json:"id"`
+	Status    string          `bun:"status" json:"status"`
+	Priority  *int32          `bun:"priority" json:"priority"`
+	Total     decimal.Decimal `bun:"total" json:"total"`
+	Tags      []string        `bun:"tags,array" json:"tags"`
+	Paid      bool            `bun:"paid" json:"paid"`
+	CreatedAt time.Time       `bun:"created_at" json:"createdAt"`
+	Internal  string          `bun:"internal"`
+}
+
+func (r testOrder) EntityName() string { return "Order" }
+
+func (r testOrder) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"id": r.ID} }
+
+func (r testOrder) Enums() map[string][]any {
+	return map[string][]any{"status": {"new", "paid"}}
+}
+
+type testOrderMeta struct{ testOrder }
+
+func (r testOrderMeta) Entity() metadata.Entity { return r.testOrder }
+
+func (r testOrderMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func TestEntity(t *testing.T) {
+	t.Parallel()
+
+	c := meta.NewContainer()
+	c.Add(testOrderMeta{}, meta.Parser)
+
+	s, err := Entity(c, "Order")
+	assert.NoError(t, err)
+
+	res, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Order",
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer", "minimum": 0},
+			"status": {"type": "string", "enum": ["new", "paid"]},
+			"priority": {"type": ["integer", "null"], "format": "int32"},
+			"total": {"type": "string"},
+			"tags": {"type": ["array", "null"], "items": {"type": "string"}},
+			"paid": {"type": "boolean"},
+			"createdAt": {"type": "string", "format": "date-time"}
+		},
+		"required": ["id", "status", "total", "paid", "createdAt"]
+	}`, string(res))
+
+	_, err = Entity(c, "Missing")
+	assert.ErrorIs(t, err, ErrUnknownEntity)
+
+	components, err := Components(c)
+	assert.NoError(t, err)
+	assert.Empty(t, components["Order"].Schema)
+	assert.Equal(t, "Order", components["Order"].Title)
+}
@@ -0,0 +1,213 @@
+package meta
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+)
+
+// Column describes a parsed entity column.
+type Column struct {
+	// Field is the Go struct field name.
+	Field       string
+	Presenter   string
+	Persistence string
+	PrimaryKey  bool
+	// Type is the Go field type.
+	Type reflect.Type
+}
+
+// RelationInfo describes a relation registered on an entity.
+type RelationInfo struct {
+	Key    string
+	Entity string
+	Table  string
+}
+
+// EntityInfo describes a registered entity for admin UIs and generators.
+type EntityInfo struct {
+	Entity string
+	Table  string
+	// Columns follow the struct field order.
+	Columns []Column
+	// PrimaryKey lists persistence names of the primary key columns, sorted.
+	PrimaryKey []string
+	// Relations are sorted by key.
+	Relations []RelationInfo
+	// Enums are allowed values by presenter name of an Enumerated entity.
+	Enums map[string][]any
+	// ReadOnly is set for ReadOnly entities.
+	ReadOnly bool
+}
+
+// Enumerated entity lists allowed values of its fields by presenter name, e.g. for schema generators.
+type Enumerated interface {
+	Enums() map[string][]any
+}
+
+// ReadOnly entity maps a database view or another relation which can't be written, e.g. a reporting view,
+// when ReadOnly returns true. Repositories reject its writes, it may have an empty primary key.
+type ReadOnly interface {
+	ReadOnly() bool
+}
+
+// ErrMetaConflict is returned when an entity name is registered again with a different meta.
+var ErrMetaConflict = errors.New("entity meta conflict")
+
+type entry struct {
+	decorator metadata.EntityMetaDecorator
+	meta      metadata.Meta
+}
+
+// Container is a metadata.EntityMetaContainer which also describes registered entities.
+// It is safe for concurrent use, entities may be registered after startup.
+type Container struct {
+	mu      sync.RWMutex
+	entries map[string]entry
+}
+
+func NewContainer() *Container {
+	return &Container{
+		entries: make(map[string]entry),
+	}
+}
+
+// Add registers the entity and panics on a conflicting registration, see Register.
+func (r *Container) Add(decorator metadata.EntityMetaDecorator, parser metadata.MetaParser) {
+	if err := r.Register(decorator, parser); err != nil {
+		panic(err)
+	}
+}
+
+// Register registers the entity. Registering the same entity again is a no-op,
+// registering a different meta under a taken entity name returns ErrMetaConflict.
+func (r *Container) Register(decorator metadata.EntityMetaDecorator, parser metadata.MetaParser) error {
+	e := entry{
+		decorator: decorator,
+		meta:      Freeze(parser(decorator), decorator),
+	}
+	name := decorator.Entity().EntityName()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.entries[name]; ok {
+		if !reflect.DeepEqual(existing.describe(name), e.describe(name)) {
+			return fmt.Errorf("%s: %w", name, ErrMetaConflict)
+		}
+
+		return nil
+	}
+
+	r.entries[name] = e
+
+	return nil
+}
+
+func (r *Container) Get(entName string) metadata.Meta {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.entries[entName].meta
+}
+
+// Entities returns registered entity names, sorted.
+func (r *Container) Entities() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.entries))
+	for k := range r.entries {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Describe returns the registered entity description.
+func (r *Container) Describe(entName string) (EntityInfo, bool) {
+	r.mu.RLock()
+	e, ok := r.entries[entName]
+	r.mu.RUnlock()
+
+	if !ok {
+		return EntityInfo{}, false
+	}
+
+	return e.describe(entName), true
+}
+
+func (r entry) describe(entName string) EntityInfo {
+	info := EntityInfo{
+		Entity:     entName,
+		Table:      r.meta.PersistenceName(),
+		PrimaryKey: r.decorator.Entity().PrimaryKey().SortedKeys(),
+	}
+
+	for _, c := range structColumns(reflect.TypeOf(r.decorator.Entity())) {
+		c.PrimaryKey = slices.Contains(info.PrimaryKey, c.Persistence)
+		info.Columns = append(info.Columns, c)
+	}
+
+	if enumerated, ok := r.decorator.Entity().(Enumerated); ok {
+		info.Enums = enumerated.Enums()
+	}
+
+	if readOnly, ok := r.decorator.Entity().(ReadOnly); ok {
+		info.ReadOnly = readOnly.ReadOnly()
+	}
+
+	for k, rel := range r.meta.Relations() {
+		ri := RelationInfo{Key: k, Table: rel.Table()}
+		if rel.GetMeta() != nil {
+			ri.Entity = rel.GetMeta().EntityName()
+		}
+
+		info.Relations = append(info.Relations, ri)
+	}
+
+	sort.Slice(info.Relations, func(i, j int) bool { return info.Relations[i].Key < info.Relations[j].Key })
+
+	return info
+}
+
+// structColumns lists columns the same way structParser registers them.
+func structColumns(t reflect.Type, prefix ...string) []Column {
+	var columns []Column
+
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Name == "BaseModel" {
+			continue
+		}
+
+		tag := t.Field(i).Tag.Get("bun")
+
+		// the embedding field itself is not a column
+		if strings.HasPrefix(tag, "embed:") {
+			if t.Field(i).Type.Kind() == reflect.Struct {
+				columns = append(columns, structColumns(t.Field(i).Type, strings.TrimPrefix(tag, "embed:"))...)
+			}
+
+			continue
+		}
+
+		if ok, fieldTags := fieldParser(t.Field(i)); ok {
+			columns = append(columns, Column{
+				Field:       fieldTags.Name,
+				Presenter:   strings.Join(append(prefix, fieldTags.Presenter), ""),
+				Persistence: strings.Join(append(prefix, fieldTags.Persistence), ""),
+				Type:        t.Field(i).Type,
+			})
+		}
+	}
+
+	return columns
+}
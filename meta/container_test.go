@@ -0,0 +1,167 @@
+package meta
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/aso779/crud-repository/entrel"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type testAudit struct {
+	CreatedBy string `bun:"created_by" json:"createdBy"`
+}
+
+type testUser struct {
+	bun.BaseModel `bun:"table:users,alias:users"`
+
+	TenantID int       `bun:"tenant_id,pk" json:"tenantId"`
+	ID       int       `bun:"id,pk" json:"id"`
+	Name     string    `bun:"name" json:"name"`
+	Secret   string    `bun:"secret"`
+	Audit    testAudit `bun:"embed:audit_" json:"audit"`
+}
+
+func (r testUser) EntityName() string { return "User" }
+
+func (r testUser) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"tenant_id": r.TenantID, "id": r.ID}
+}
+
+type testUserMeta struct{ testUser }
+
+func (r testUserMeta) Entity() metadata.Entity { return r.testUser }
+
+func (r testUserMeta) Relations() map[string]metadata.Relation {
+	return map[string]metadata.Relation{
+		"Manager": entrel.ToOne{Meta: Parser(testManagerMeta{}), JoinTable: "users"},
+	}
+}
+
+type testManagerMeta struct{ testUser }
+
+func (r testManagerMeta) Entity() metadata.Entity { return r.testUser }
+
+func (r testManagerMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func TestContainer(t *testing.T) {
+	t.Parallel()
+
+	c := NewContainer()
+	c.Add(testUserMeta{}, Parser)
+
+	assert.Equal(t, []string{"User"}, c.Entities())
+	assert.Equal(t, "users", c.Get("User").PersistenceName())
+	assert.Nil(t, c.Get("Missing"))
+
+	info, ok := c.Describe("User")
+
+	assert.True(t, ok)
+	assert.Equal(t, EntityInfo{
+		Entity: "User",
+		Table:  "users",
+		Columns: []Column{
+			{Field: "TenantID", Presenter: "tenantId", Persistence: "tenant_id", PrimaryKey: true, Type: reflect.TypeOf(0)},
+			{Field: "ID", Presenter: "id", Persistence: "id", PrimaryKey: true, Type: reflect.TypeOf(0)},
+			{Field: "Name", Presenter: "name", Persistence: "name", Type: reflect.TypeOf("")},
+			{Field: "CreatedBy", Presenter: "audit_createdBy", Persistence: "audit_created_by", Type: reflect.TypeOf("")},
+		},
+		PrimaryKey: []string{"id", "tenant_id"},
+		Relations:  []RelationInfo{{Key: "Manager", Entity: "User", Table: "users"}},
+	}, info)
+
+	_, ok = c.Describe("Missing")
+	assert.False(t, ok)
+}
+
+type testAccount struct {
+	bun.BaseModel `bun:"table:accounts,alias:accounts"`
+
+	ID int `bun:"id,pk" json:"id"`
+}
+
+func (r testAccount) EntityName() string { return "User" }
+
+func (r testAccount) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"id": r.ID} }
+
+type testAccountMeta struct{ testAccount }
+
+func (r testAccountMeta) Entity() metadata.Entity { return r.testAccount }
+
+func (r testAccountMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+type testSalesView struct {
+	bun.BaseModel `bun:"table:sales_views,alias:sales_views"`
+
+	Region string `bun:"region" json:"region"`
+}
+
+func (r testSalesView) EntityName() string { return "SalesView" }
+
+func (r testSalesView) PrimaryKey() metadata.PrimaryKey { return nil }
+
+func (r testSalesView) ReadOnly() bool { return true }
+
+type testSalesViewMeta struct{ testSalesView }
+
+func (r testSalesViewMeta) Entity() metadata.Entity { return r.testSalesView }
+
+func (r testSalesViewMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func TestContainer_DescribeReadOnly(t *testing.T) {
+	t.Parallel()
+
+	c := NewContainer()
+	c.Add(testSalesViewMeta{}, Parser)
+
+	info, ok := c.Describe("SalesView")
+
+	assert.True(t, ok)
+	assert.True(t, info.ReadOnly)
+	assert.Empty(t, info.PrimaryKey)
+	assert.Equal(t, []Column{{Field: "Region", Presenter: "region", Persistence: "region", Type: reflect.TypeOf("")}}, info.Columns)
+}
+
+func TestContainer_Register(t *testing.T) {
+	t.Parallel()
+
+	c := NewContainer()
+
+	assert.NoError(t, c.Register(testUserMeta{}, Parser))
+	assert.NoError(t, c.Register(testUserMeta{}, Parser))
+	assert.ErrorIs(t, c.Register(testAccountMeta{}, Parser), ErrMetaConflict)
+	assert.Equal(t, "users", c.Get("User").PersistenceName())
+	assert.Panics(t, func() { c.Add(testAccountMeta{}, Parser) })
+}
+
+func TestContainer_RegisterConcurrent(t *testing.T) {
+	t.Parallel()
+
+	c := NewContainer()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, c.Register(testUserMeta{}, Parser))
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			c.Get("User")
+			c.Entities()
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, []string{"User"}, c.Entities())
+}
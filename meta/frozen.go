@@ -0,0 +1,125 @@
+package meta
+
+import (
+	"maps"
+	"reflect"
+	"strings"
+
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+)
+
+// Aliased is a meta of a table queried under an alias, e.g. a schema qualified table. PersistenceName
+// is the table name for DDL and catalog lookups, Alias qualifies columns in specs and sorts.
+type Aliased interface {
+	Alias() string
+}
+
+// frozen is an immutable metadata.Meta. Mappings are copied on construction, so it is safe to share across
+// goroutines. Mapping getters return the frozen maps without copying them, callers must treat them as read-only.
+type frozen struct {
+	entityName             string
+	persistenceName        string
+	alias                  string
+	relations              map[string]metadata.Relation
+	fieldToPresenter       map[string]string
+	presenterToPersistence map[string]string
+	persistenceToPresenter map[string]string
+}
+
+// Freeze returns an immutable copy of the meta. Field to presenter translation is copied for the fields of
+// the entity of the decorator, so decorator must be the one the meta was parsed from.
+func Freeze(m metadata.Meta, decorator metadata.EntityMetaDecorator) metadata.Meta {
+	if f, ok := m.(*frozen); ok {
+		return f
+	}
+
+	f := &frozen{
+		entityName:             m.EntityName(),
+		persistenceName:        m.PersistenceName(),
+		alias:                  tableAlias(reflect.TypeOf(decorator.Entity())),
+		relations:              maps.Clone(m.Relations()),
+		fieldToPresenter:       make(map[string]string),
+		presenterToPersistence: maps.Clone(m.PresenterPersistenceMapping()),
+		persistenceToPresenter: maps.Clone(m.PersistencePresenterMapping()),
+	}
+
+	for _, name := range fieldNames(reflect.TypeOf(decorator.Entity())) {
+		if presenter := m.FieldToPresenter(name); presenter != "" {
+			f.fieldToPresenter[name] = presenter
+		}
+	}
+
+	return f
+}
+
+func (r *frozen) EntityName() string {
+	return r.entityName
+}
+
+func (r *frozen) PersistenceName() string {
+	return r.persistenceName
+}
+
+func (r *frozen) Alias() string {
+	if r.alias == "" {
+		return r.persistenceName
+	}
+
+	return r.alias
+}
+
+func (r *frozen) FieldToPresenter(fieldName string) string {
+	return r.fieldToPresenter[fieldName]
+}
+
+func (r *frozen) PresenterToPersistence(presenterName string) string {
+	return r.presenterToPersistence[presenterName]
+}
+
+func (r *frozen) PresenterSetToPersistenceSet(presenterNames []string) []string {
+	var res []string
+
+	for _, v := range presenterNames {
+		res = append(res, r.presenterToPersistence[v])
+	}
+
+	return res
+}
+
+// PresenterPersistenceMapping returns the frozen map, it must not be modified.
+func (r *frozen) PresenterPersistenceMapping() map[string]string {
+	return r.presenterToPersistence
+}
+
+// PersistencePresenterMapping returns the frozen map, it must not be modified.
+func (r *frozen) PersistencePresenterMapping() map[string]string {
+	return r.persistenceToPresenter
+}
+
+// Relations returns the frozen map, it must not be modified.
+func (r *frozen) Relations() map[string]metadata.Relation {
+	return r.relations
+}
+
+// fieldNames lists struct field names the way structParser visits them, including embedded struct fields.
+func fieldNames(t reflect.Type) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var names []string
+
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Name == "BaseModel" {
+			continue
+		}
+
+		names = append(names, t.Field(i).Name)
+
+		if strings.HasPrefix(t.Field(i).Tag.Get("bun"), "embed:") && t.Field(i).Type.Kind() == reflect.Struct {
+			names = append(names, fieldNames(t.Field(i).Type)...)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,101 @@
+package meta
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/entmeta"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+func TestParser_Frozen(t *testing.T) {
+	t.Parallel()
+
+	m := Parser(testUserMeta{})
+
+	assert.Equal(t, "name", m.PresenterToPersistence("name"))
+	assert.Equal(t, "name", m.PersistencePresenterMapping()["name"])
+	assert.Contains(t, m.Relations(), "Manager")
+	assert.Equal(t, "audit_created_by", m.PresenterToPersistence("audit_createdBy"))
+	assert.Equal(t, "createdBy", m.FieldToPresenter("CreatedBy"))
+	assert.Equal(t, "audit", m.FieldToPresenter("Audit"))
+	assert.Same(t, m, Freeze(m, testUserMeta{}))
+	assert.Equal(t, reflect.ValueOf(m.PresenterPersistenceMapping()).Pointer(),
+		reflect.ValueOf(m.PresenterPersistenceMapping()).Pointer())
+	assert.Equal(t, reflect.ValueOf(m.PersistencePresenterMapping()).Pointer(),
+		reflect.ValueOf(m.PersistencePresenterMapping()).Pointer())
+	assert.Equal(t, reflect.ValueOf(m.Relations()).Pointer(), reflect.ValueOf(m.Relations()).Pointer())
+}
+
+func TestFreeze_CopiesMutableMeta(t *testing.T) {
+	t.Parallel()
+
+	m := entmeta.NewMeta()
+	m.SetEntityName("User")
+	m.AddPresenterToPersistence("name", "name")
+	m.AddFieldToPresenter("Name", "name")
+
+	f := Freeze(m, testUserMeta{})
+
+	m.AddPresenterToPersistence("name", "changed")
+	m.AddFieldToPresenter("Name", "changed")
+
+	assert.Equal(t, "User", f.EntityName())
+	assert.Equal(t, "name", f.PresenterToPersistence("name"))
+	assert.Equal(t, "name", f.FieldToPresenter("Name"))
+}
+
+func TestParser_ConcurrentReads(t *testing.T) {
+	t.Parallel()
+
+	m := Parser(testUserMeta{})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				assert.Equal(t, "name", m.PresenterToPersistence("name"))
+				assert.Equal(t, "name", m.PersistencePresenterMapping()["name"])
+				assert.Len(t, m.PresenterSetToPersistenceSet([]string{"id", "name"}), 2)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+type testRemoteOrder struct {
+	bun.BaseModel `bun:"table:remote.orders,alias:orders"`
+
+	ID int `bun:"id,pk" json:"id"`
+}
+
+func (r testRemoteOrder) EntityName() string { return "RemoteOrder" }
+
+func (r testRemoteOrder) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"id": r.ID} }
+
+type testRemoteOrderMeta struct{ testRemoteOrder }
+
+func (r testRemoteOrderMeta) Entity() metadata.Entity { return r.testRemoteOrder }
+
+func (r testRemoteOrderMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func TestParser_Alias(t *testing.T) {
+	t.Parallel()
+
+	remote := Parser(testRemoteOrderMeta{})
+	assert.Equal(t, "remote.orders", remote.PersistenceName())
+	assert.Equal(t, "orders", remote.(Aliased).Alias())
+
+	local := Parser(testUserMeta{})
+	assert.Equal(t, "users", local.PersistenceName())
+	assert.Equal(t, "users", local.(Aliased).Alias())
+}
@@ -43,6 +43,33 @@ func structParser(t reflect.Type, m *entmeta.Meta, prefix ...string) {
 	}
 }
 
+// tableAlias is the alias qualifying columns of schema qualified tables in specs, e.g. of foreign tables
+// imported into a schema, as bun selects them aliased. It is empty for other tables.
+func tableAlias(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	f, ok := t.FieldByName("BaseModel")
+	if !ok {
+		return ""
+	}
+
+	tagValues := strings.Split(f.Tag.Get("bun"), ",")
+
+	if !strings.Contains(tagValues[0], ".") {
+		return ""
+	}
+
+	for _, v := range tagValues[1:] {
+		if alias, ok := strings.CutPrefix(v, "alias:"); ok {
+			return alias
+		}
+	}
+
+	return ""
+}
+
 func fieldParser(field reflect.StructField) (bool, *FieldTags) {
 	bunName, bunOk := field.Tag.Lookup("bun")
 	if !bunOk || strings.Contains(bunName, "fk") || strings.Contains(bunName, "many2many") {
@@ -88,6 +115,7 @@ func relationsParser(relations map[string]metadata.Relation, parent string) map[
 	return relations
 }
 
+// Parser parses the entity meta from bun and json tags of the decorated entity, the result is immutable, see Freeze.
 var Parser = func(decorator metadata.EntityMetaDecorator) metadata.Meta {
 	m := entmeta.NewMeta()
 	m.SetDecorator(decorator)
@@ -99,5 +127,5 @@ var Parser = func(decorator metadata.EntityMetaDecorator) metadata.Meta {
 
 	m.SetRelations(relationsParser(decorator.Relations(), ""))
 
-	return m
+	return Freeze(m, decorator)
 }
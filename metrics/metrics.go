@@ -0,0 +1,83 @@
+// Package metrics exports connection pool and query metrics in Prometheus format.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+const namespace = "crud_repository"
+
+// QueryHook bun query hook counting operations and errors and observing latency per table and operation.
+// sql.ErrNoRows is not counted as an error. Register it with db.AddQueryHook.
+type QueryHook struct {
+	operations *prometheus.CounterVec
+	errors     *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+}
+
+var _ bun.QueryHook = (*QueryHook)(nil)
+
+func NewQueryHook(reg prometheus.Registerer) (*QueryHook, error) {
+	labels := []string{"table", "operation"}
+
+	hook := &QueryHook{
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "operations_total",
+			Help:      "Total number of executed queries.",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Total number of failed queries.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "operation_duration_seconds",
+			Help:      "Query latency.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+	}
+
+	for _, c := range []prometheus.Collector{hook.operations, hook.errors, hook.duration} {
+		if err := reg.Register(c); err != nil {
+			return nil, fmt.Errorf("register query metrics: %w", err)
+		}
+	}
+
+	return hook, nil
+}
+
+func (r *QueryHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (r *QueryHook) AfterQuery(_ context.Context, event *bun.QueryEvent) {
+	labels := prometheus.Labels{
+		"table":     table(event),
+		"operation": event.Operation(),
+	}
+
+	r.operations.With(labels).Inc()
+	r.duration.With(labels).Observe(time.Since(event.StartTime).Seconds())
+
+	if event.Err != nil && !errors.Is(event.Err, sql.ErrNoRows) {
+		r.errors.With(labels).Inc()
+	}
+}
+
+func table(event *bun.QueryEvent) string {
+	if m, ok := event.Model.(interface{ Table() *schema.Table }); ok && m.Table() != nil {
+		return m.Table().Name
+	}
+
+	return ""
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+var errQuery = errors.New("query failed")
+
+type mockConnSet struct {
+	db *bun.DB
+}
+
+func (r mockConnSet) ReadPool() *bun.DB {
+	return r.db
+}
+
+func (r mockConnSet) WritePool() *bun.DB {
+	return r.db
+}
+
+type testEnt struct {
+	bun.BaseModel `bun:"table:test_entities,alias:test_entities"`
+
+	ID int `bun:"id,pk"`
+}
+
+func TestQueryHook(t *testing.T) {
+	t.Parallel()
+
+	sqldb, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	reg := prometheus.NewRegistry()
+
+	hook, err := NewQueryHook(reg)
+	assert.NoError(t, err)
+
+	db := bun.NewDB(sqldb, pgdialect.New())
+	db.AddQueryHook(hook)
+
+	mock.ExpectQuery("^SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery("^SELECT").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("^SELECT").WillReturnError(errQuery)
+
+	for i := 0; i < 3; i++ {
+		_ = db.NewSelect().Model(&testEnt{}).Scan(context.Background())
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP crud_repository_errors_total Total number of failed queries.
+# TYPE crud_repository_errors_total counter
+crud_repository_errors_total{operation="SELECT",table="test_entities"} 1
+# HELP crud_repository_operations_total Total number of executed queries.
+# TYPE crud_repository_operations_total counter
+crud_repository_operations_total{operation="SELECT",table="test_entities"} 3
+`), "crud_repository_errors_total", "crud_repository_operations_total"))
+	assert.Equal(t, 1, testutil.CollectAndCount(hook.duration))
+}
+
+func TestPoolCollector(t *testing.T) {
+	t.Parallel()
+
+	sqldb, _, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqldb.SetMaxOpenConns(5)
+
+	collector := NewPoolCollector(mockConnSet{db: bun.NewDB(sqldb, pgdialect.New())})
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP crud_repository_pool_max_open_connections Maximum number of open connections to the database.
+# TYPE crud_repository_pool_max_open_connections gauge
+crud_repository_pool_max_open_connections{pool="read"} 5
+crud_repository_pool_max_open_connections{pool="write"} 5
+`), "crud_repository_pool_max_open_connections"))
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/aso779/bun-pg-connector"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector exports database/sql stats of the read and write pools labeled by pool.
+type PoolCollector struct {
+	ConnSet bunpgconnector.BunConnSet
+
+	maxOpen      *prometheus.Desc
+	open         *prometheus.Desc
+	inUse        *prometheus.Desc
+	idle         *prometheus.Desc
+	waitCount    *prometheus.Desc
+	waitDuration *prometheus.Desc
+}
+
+func NewPoolCollector(connSet bunpgconnector.BunConnSet) *PoolCollector {
+	labels := []string{"pool"}
+
+	return &PoolCollector{
+		ConnSet: connSet,
+		maxOpen: prometheus.NewDesc(namespace+"_pool_max_open_connections",
+			"Maximum number of open connections to the database.", labels, nil),
+		open: prometheus.NewDesc(namespace+"_pool_open_connections",
+			"Number of established connections both in use and idle.", labels, nil),
+		inUse: prometheus.NewDesc(namespace+"_pool_in_use_connections",
+			"Number of connections currently in use.", labels, nil),
+		idle: prometheus.NewDesc(namespace+"_pool_idle_connections",
+			"Number of idle connections.", labels, nil),
+		waitCount: prometheus.NewDesc(namespace+"_pool_wait_count_total",
+			"Total number of connections waited for.", labels, nil),
+		waitDuration: prometheus.NewDesc(namespace+"_pool_wait_duration_seconds_total",
+			"Total time blocked waiting for a new connection.", labels, nil),
+	}
+}
+
+func (r *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- r.maxOpen
+	ch <- r.open
+	ch <- r.inUse
+	ch <- r.idle
+	ch <- r.waitCount
+	ch <- r.waitDuration
+}
+
+func (r *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	r.collect(ch, "read", r.ConnSet.ReadPool().Stats())
+	r.collect(ch, "write", r.ConnSet.WritePool().Stats())
+}
+
+func (r *PoolCollector) collect(ch chan<- prometheus.Metric, pool string, stats sql.DBStats) {
+	ch <- prometheus.MustNewConstMetric(r.maxOpen, prometheus.GaugeValue, float64(stats.MaxOpenConnections), pool)
+	ch <- prometheus.MustNewConstMetric(r.open, prometheus.GaugeValue, float64(stats.OpenConnections), pool)
+	ch <- prometheus.MustNewConstMetric(r.inUse, prometheus.GaugeValue, float64(stats.InUse), pool)
+	ch <- prometheus.MustNewConstMetric(r.idle, prometheus.GaugeValue, float64(stats.Idle), pool)
+	ch <- prometheus.MustNewConstMetric(r.waitCount, prometheus.CounterValue, float64(stats.WaitCount), pool)
+	ch <- prometheus.MustNewConstMetric(r.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds(), pool)
+}
@@ -0,0 +1,229 @@
+package migration
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+// DualWrite writes to the old repository and mirrors successful writes to the new one.
+// Mirrored creates and updates store the mapped result of the old write; deletes reuse the spec,
+// so its fields must exist in both entities. Reads are served by the old repository.
+type DualWrite[O metadata.Entity, N metadata.Entity, T bun.Tx] struct {
+	repository.CrudRepository[O, T]
+
+	New    repository.CrudRepository[N, T]
+	Map    func(O) N
+	Logger *slog.Logger
+}
+
+func NewDualWrite[O metadata.Entity, N metadata.Entity, T bun.Tx](
+	old repository.CrudRepository[O, T],
+	newRepo repository.CrudRepository[N, T],
+	mapper func(O) N,
+) DualWrite[O, N, T] {
+	return DualWrite[O, N, T]{
+		CrudRepository: old,
+		New:            newRepo,
+		Map:            mapper,
+	}
+}
+
+func (r DualWrite[O, N, T]) CreateOne(
+	ctx context.Context,
+	tx bun.IDB,
+	entity *O,
+	columns []string,
+) (*O, error) {
+	res, err := r.CrudRepository.CreateOne(ctx, tx, entity, columns)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	r.diverged(ctx, "create one", mirror(ctx, tx, func(ctx context.Context, tx bun.IDB) error {
+		mapped := r.Map(*res)
+		_, err := r.New.CreateOne(ctx, tx, &mapped, nil)
+
+		return err //nolint:wrapcheck
+	}))
+
+	return res, nil
+}
+
+func (r DualWrite[O, N, T]) CreateAll(
+	ctx context.Context,
+	tx bun.IDB,
+	entities []O,
+	columns []string,
+) ([]O, error) {
+	res, err := r.CrudRepository.CreateAll(ctx, tx, entities, columns)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	r.diverged(ctx, "create all", mirror(ctx, tx, func(ctx context.Context, tx bun.IDB) error {
+		_, err := r.New.CreateAll(ctx, tx, r.mapAll(res), nil)
+
+		return err //nolint:wrapcheck
+	}))
+
+	return res, nil
+}
+
+func (r DualWrite[O, N, T]) FirstOrCreate(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	entity *O,
+	columns []string,
+) (*O, error) {
+	res, err := r.CrudRepository.FirstOrCreate(ctx, tx, spec, entity, columns)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	r.diverged(ctx, "first or create", mirror(ctx, tx, func(ctx context.Context, tx bun.IDB) error {
+		mapped := r.Map(*res)
+		_, err := r.New.FirstOrCreate(ctx, tx, spec, &mapped, nil)
+
+		return err //nolint:wrapcheck
+	}))
+
+	return res, nil
+}
+
+func (r DualWrite[O, N, T]) FirstOrCreateLocked(
+	ctx context.Context,
+	tx bun.IDB,
+	key string,
+	spec dataset.Specifier,
+	entity *O,
+	columns []string,
+) (*O, error) {
+	res, err := r.CrudRepository.FirstOrCreateLocked(ctx, tx, key, spec, entity, columns)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	r.diverged(ctx, "first or create locked", mirror(ctx, tx, func(ctx context.Context, tx bun.IDB) error {
+		mapped := r.Map(*res)
+		_, err := r.New.FirstOrCreateLocked(ctx, tx, key, spec, &mapped, nil)
+
+		return err //nolint:wrapcheck
+	}))
+
+	return res, nil
+}
+
+func (r DualWrite[O, N, T]) UpdateOrCreate(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	values map[string]any,
+	entity *O,
+	columns []string,
+) (*O, error) {
+	res, err := r.CrudRepository.UpdateOrCreate(ctx, tx, spec, values, entity, columns)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	r.diverged(ctx, "update or create", mirror(ctx, tx, func(ctx context.Context, tx bun.IDB) error {
+		mapped := r.Map(*entity)
+		_, err := r.New.UpdateOrCreate(ctx, tx, spec, values, &mapped, nil)
+
+		return err //nolint:wrapcheck
+	}))
+
+	return res, nil
+}
+
+func (r DualWrite[O, N, T]) UpdateOne(
+	ctx context.Context,
+	tx bun.IDB,
+	entity *O,
+	columnsToUpdate []string,
+	columns []string,
+) (*O, error) {
+	res, err := r.CrudRepository.UpdateOne(ctx, tx, entity, columnsToUpdate, columns)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	r.diverged(ctx, "update one", mirror(ctx, tx, func(ctx context.Context, tx bun.IDB) error {
+		mapped := r.Map(*entity)
+		_, err := r.New.UpdateOne(ctx, tx, &mapped, nil, nil)
+
+		return err //nolint:wrapcheck
+	}))
+
+	return res, nil
+}
+
+func (r DualWrite[O, N, T]) Delete(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (int, error) {
+	res, err := r.CrudRepository.Delete(ctx, tx, spec)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	r.diverged(ctx, "delete", mirror(ctx, tx, func(ctx context.Context, tx bun.IDB) error {
+		mirrored, err := r.New.Delete(ctx, tx, spec)
+		if err == nil && mirrored != res {
+			r.logger().WarnContext(ctx, "dual write affected rows differ", "op", "delete", "old", res, "new", mirrored)
+		}
+
+		return err //nolint:wrapcheck
+	}))
+
+	return res, nil
+}
+
+func (r DualWrite[O, N, T]) ForceDelete(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (int, error) {
+	res, err := r.CrudRepository.ForceDelete(ctx, tx, spec)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	r.diverged(ctx, "force delete", mirror(ctx, tx, func(ctx context.Context, tx bun.IDB) error {
+		mirrored, err := r.New.ForceDelete(ctx, tx, spec)
+		if err == nil && mirrored != res {
+			r.logger().WarnContext(ctx, "dual write affected rows differ", "op", "force delete", "old", res, "new", mirrored)
+		}
+
+		return err //nolint:wrapcheck
+	}))
+
+	return res, nil
+}
+
+func (r DualWrite[O, N, T]) diverged(ctx context.Context, op string, err error) {
+	if err != nil {
+		r.logger().ErrorContext(ctx, "dual write failed", "op", op, "error", err)
+	}
+}
+
+func (r DualWrite[O, N, T]) mapAll(entities []O) []N {
+	mapped := make([]N, len(entities))
+
+	for i := range entities {
+		mapped[i] = r.Map(entities[i])
+	}
+
+	return mapped
+}
+
+func (r DualWrite[O, N, T]) logger() *slog.Logger {
+	return logger(r.Logger)
+}
@@ -0,0 +1,82 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var errMirror = errors.New("mirror failed")
+
+func TestDualWrite_CreateOne(t *testing.T) {
+	t.Parallel()
+
+	s := setUp(t)
+	repo := NewDualWrite[oldEnt, newEnt](s.old, s.new, toNew)
+	repo.Logger = s.logger
+
+	s.oldMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "old_entities" ("id", "name") VALUES (1, 'a')`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.newMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "new_entities" ("id", "title") VALUES (1, 'a')`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res, err := repo.CreateOne(context.Background(), nil, &oldEnt{ID: 1, Name: "a"}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "a", res.Name)
+	assert.NoError(t, s.oldMock.ExpectationsWereMet())
+	assert.NoError(t, s.newMock.ExpectationsWereMet())
+	assert.Empty(t, s.log.String())
+}
+
+func TestDualWrite_CreateOneMirrorError(t *testing.T) {
+	t.Parallel()
+
+	s := setUp(t)
+	repo := NewDualWrite[oldEnt, newEnt](s.old, s.new, toNew)
+	repo.Logger = s.logger
+
+	s.oldMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "old_entities"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.newMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "new_entities"`)).
+		WillReturnError(errMirror)
+
+	_, err := repo.CreateOne(context.Background(), nil, &oldEnt{ID: 1, Name: "a"}, nil)
+
+	assert.NoError(t, err)
+	assert.NoError(t, s.newMock.ExpectationsWereMet())
+	assert.Contains(t, s.log.String(), `msg="dual write failed" op="create one"`)
+}
+
+func TestDualWrite_CreateOneInTx(t *testing.T) {
+	t.Parallel()
+
+	s := setUp(t)
+	repo := NewDualWrite[oldEnt, newEnt](s.old, s.new, toNew)
+	repo.Logger = s.logger
+
+	s.oldMock.ExpectBegin()
+	s.oldMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "old_entities"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.oldMock.ExpectExec(`^SAVEPOINT SP_`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.oldMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "new_entities"`)).
+		WillReturnError(errMirror)
+	s.oldMock.ExpectExec(`^ROLLBACK TO SAVEPOINT SP_`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.oldMock.ExpectCommit()
+
+	tx, err := s.old.ConnSet.WritePool().BeginTx(context.Background(), nil)
+	assert.NoError(t, err)
+
+	_, err = repo.CreateOne(context.Background(), tx, &oldEnt{ID: 1, Name: "a"}, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	assert.NoError(t, s.oldMock.ExpectationsWereMet())
+	assert.Contains(t, s.log.String(), `msg="dual write failed"`)
+}
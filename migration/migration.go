@@ -0,0 +1,37 @@
+// Package migration provides repository decorators for zero-downtime moves between entities or schemas.
+// The old repository stays the source of truth: its results and errors are returned, while the work against
+// the new repository is only mirrored and divergences are logged.
+package migration
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+// mirror runs fn against the new repository. Inside a transaction fn runs under a savepoint,
+// so a failed mirror statement doesn't abort the caller transaction.
+func mirror(ctx context.Context, tx bun.IDB, fn func(ctx context.Context, tx bun.IDB) error) error {
+	if tx == nil {
+		return fn(ctx, nil)
+	}
+
+	return tx.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error { //nolint:wrapcheck
+		return fn(ctx, tx)
+	})
+}
+
+func logger(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return slog.Default()
+	}
+
+	return l
+}
+
+func pkKey(pk metadata.PrimaryKey) string {
+	return fmt.Sprint(map[string]any(pk))
+}
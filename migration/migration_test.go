@@ -0,0 +1,96 @@
+package migration
+
+import (
+	"bytes"
+	"database/sql"
+	"log/slog"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+type mockConnSet struct {
+	db *sql.DB
+}
+
+func (r mockConnSet) ReadPool() *bun.DB {
+	return bun.NewDB(r.db, pgdialect.New())
+}
+
+func (r mockConnSet) WritePool() *bun.DB {
+	return bun.NewDB(r.db, pgdialect.New())
+}
+
+type oldEnt struct {
+	bun.BaseModel `bun:"table:old_entities,alias:old_entities"`
+
+	ID   int    `bun:"id,pk" json:"id"`
+	Name string `bun:"name" json:"name"`
+}
+
+func (r oldEnt) EntityName() string {
+	return "oldEnt"
+}
+
+func (r oldEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+type newEnt struct {
+	bun.BaseModel `bun:"table:new_entities,alias:new_entities"`
+
+	ID    int    `bun:"id,pk" json:"id"`
+	Title string `bun:"title" json:"title"`
+}
+
+func (r newEnt) EntityName() string {
+	return "newEnt"
+}
+
+func (r newEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+func toNew(e oldEnt) newEnt {
+	return newEnt{ID: e.ID, Title: e.Name}
+}
+
+type subject struct {
+	old     repository.BunCrudRepository[oldEnt, bun.Tx]
+	new     repository.BunCrudRepository[newEnt, bun.Tx]
+	oldMock sqlmock.Sqlmock
+	newMock sqlmock.Sqlmock
+	log     *bytes.Buffer
+	logger  *slog.Logger
+}
+
+func setUp(t *testing.T) subject {
+	t.Helper()
+
+	oldDB, oldMock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	newDB, newMock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	var log bytes.Buffer
+
+	return subject{
+		old:     repository.BunCrudRepository[oldEnt, bun.Tx]{ConnSet: mockConnSet{db: oldDB}},
+		new:     repository.BunCrudRepository[newEnt, bun.Tx]{ConnSet: mockConnSet{db: newDB}},
+		oldMock: oldMock,
+		newMock: newMock,
+		log:     &log,
+		logger:  slog.New(slog.NewTextHandler(&log, nil)),
+	}
+}
+
+var (
+	_ repository.CrudRepository[oldEnt, bun.Tx] = DualWrite[oldEnt, newEnt, bun.Tx]{}
+	_ repository.CrudRepository[oldEnt, bun.Tx] = ReadShadow[oldEnt, newEnt, bun.Tx]{}
+)
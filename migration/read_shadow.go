@@ -0,0 +1,235 @@
+package migration
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+// ReadShadow serves reads from the old repository and repeats them against the new one,
+// logging results that differ after mapping. Lists are compared by primary key regardless of order.
+// Columns and specs are passed to both repositories as is.
+type ReadShadow[O metadata.Entity, N metadata.Entity, T bun.Tx] struct {
+	repository.CrudRepository[O, T]
+
+	New repository.CrudRepository[N, T]
+	Map func(O) N
+	// Equal compares mapped old and new entities, reflect.DeepEqual is used when nil.
+	Equal  func(a, b N) bool
+	Logger *slog.Logger
+}
+
+func NewReadShadow[O metadata.Entity, N metadata.Entity, T bun.Tx](
+	old repository.CrudRepository[O, T],
+	newRepo repository.CrudRepository[N, T],
+	mapper func(O) N,
+) ReadShadow[O, N, T] {
+	return ReadShadow[O, N, T]{
+		CrudRepository: old,
+		New:            newRepo,
+		Map:            mapper,
+	}
+}
+
+func (r ReadShadow[O, N, T]) FindOne(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+) (*O, error) {
+	res, err := r.CrudRepository.FindOne(ctx, tx, columns, spec)
+
+	r.compareOne(ctx, "find one", res, err, tx, func(ctx context.Context, tx bun.IDB) (*N, error) {
+		return r.New.FindOne(ctx, tx, columns, spec) //nolint:wrapcheck
+	})
+
+	return res, err //nolint:wrapcheck
+}
+
+func (r ReadShadow[O, N, T]) FindOneByPk(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	pk metadata.PrimaryKey,
+) (*O, error) {
+	res, err := r.CrudRepository.FindOneByPk(ctx, tx, columns, pk)
+
+	r.compareOne(ctx, "find one by pk", res, err, tx, func(ctx context.Context, tx bun.IDB) (*N, error) {
+		return r.New.FindOneByPk(ctx, tx, columns, pk) //nolint:wrapcheck
+	})
+
+	return res, err //nolint:wrapcheck
+}
+
+func (r ReadShadow[O, N, T]) FindAll(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+) ([]O, error) {
+	res, err := r.CrudRepository.FindAll(ctx, tx, columns, spec)
+
+	r.compareAll(ctx, "find all", res, err, tx, func(ctx context.Context, tx bun.IDB) ([]N, error) {
+		return r.New.FindAll(ctx, tx, columns, spec) //nolint:wrapcheck
+	})
+
+	return res, err //nolint:wrapcheck
+}
+
+func (r ReadShadow[O, N, T]) FindPage(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+	page dataset.Pager,
+	sort dataset.Sorter,
+) ([]O, error) {
+	res, err := r.CrudRepository.FindPage(ctx, tx, columns, spec, page, sort)
+
+	r.compareAll(ctx, "find page", res, err, tx, func(ctx context.Context, tx bun.IDB) ([]N, error) {
+		return r.New.FindPage(ctx, tx, columns, spec, page, sort) //nolint:wrapcheck
+	})
+
+	return res, err //nolint:wrapcheck
+}
+
+func (r ReadShadow[O, N, T]) FindAllByPks(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	pks []metadata.PrimaryKey,
+) ([]O, error) {
+	res, err := r.CrudRepository.FindAllByPks(ctx, tx, columns, pks)
+
+	r.compareAll(ctx, "find all by pks", res, err, tx, func(ctx context.Context, tx bun.IDB) ([]N, error) {
+		return r.New.FindAllByPks(ctx, tx, columns, pks) //nolint:wrapcheck
+	})
+
+	return res, err //nolint:wrapcheck
+}
+
+func (r ReadShadow[O, N, T]) Count(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (int, error) {
+	res, err := r.CrudRepository.Count(ctx, tx, spec)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	var shadow int
+
+	shadowErr := mirror(ctx, tx, func(ctx context.Context, tx bun.IDB) error {
+		var err error
+
+		shadow, err = r.New.Count(ctx, tx, spec)
+
+		return err //nolint:wrapcheck
+	})
+
+	switch {
+	case shadowErr != nil:
+		r.logger().ErrorContext(ctx, "shadow read failed", "op", "count", "error", shadowErr)
+	case shadow != res:
+		r.logger().WarnContext(ctx, "shadow read diverged", "op", "count", "old", res, "new", shadow)
+	}
+
+	return res, nil
+}
+
+func (r ReadShadow[O, N, T]) compareOne(
+	ctx context.Context,
+	op string,
+	res *O,
+	err error,
+	tx bun.IDB,
+	shadowFn func(ctx context.Context, tx bun.IDB) (*N, error),
+) {
+	var shadow *N
+
+	shadowErr := mirror(ctx, tx, func(ctx context.Context, tx bun.IDB) error {
+		var err error
+
+		shadow, err = shadowFn(ctx, tx)
+
+		return err
+	})
+
+	switch {
+	case err != nil && shadowErr != nil:
+		return
+	case err != nil || shadowErr != nil:
+		r.logger().WarnContext(ctx, "shadow read diverged", "op", op, "old_error", err, "new_error", shadowErr)
+	case !r.equal(r.Map(*res), *shadow):
+		r.logger().WarnContext(ctx, "shadow read diverged", "op", op, "pk", (*res).PrimaryKey())
+	}
+}
+
+func (r ReadShadow[O, N, T]) compareAll(
+	ctx context.Context,
+	op string,
+	res []O,
+	err error,
+	tx bun.IDB,
+	shadowFn func(ctx context.Context, tx bun.IDB) ([]N, error),
+) {
+	if err != nil {
+		return
+	}
+
+	var shadow []N
+
+	shadowErr := mirror(ctx, tx, func(ctx context.Context, tx bun.IDB) error {
+		var err error
+
+		shadow, err = shadowFn(ctx, tx)
+
+		return err
+	})
+	if shadowErr != nil {
+		r.logger().ErrorContext(ctx, "shadow read failed", "op", op, "error", shadowErr)
+
+		return
+	}
+
+	byPk := make(map[string]N, len(shadow))
+	for _, e := range shadow {
+		byPk[pkKey(e.PrimaryKey())] = e
+	}
+
+	var diverged []metadata.PrimaryKey
+
+	for _, e := range res {
+		pk := e.PrimaryKey()
+
+		s, ok := byPk[pkKey(pk)]
+		if !ok || !r.equal(r.Map(e), s) {
+			diverged = append(diverged, pk)
+		}
+
+		delete(byPk, pkKey(pk))
+	}
+
+	if len(diverged) > 0 || len(byPk) > 0 {
+		r.logger().WarnContext(ctx, "shadow read diverged",
+			"op", op, "changed", diverged, "extra", len(byPk))
+	}
+}
+
+func (r ReadShadow[O, N, T]) equal(a, b N) bool {
+	if r.Equal != nil {
+		return r.Equal(a, b)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func (r ReadShadow[O, N, T]) logger() *slog.Logger {
+	return logger(r.Logger)
+}
@@ -0,0 +1,66 @@
+package migration
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadShadow_FindAll(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(s subject)
+		expected func(t *testing.T, log string)
+	}{
+		{
+			name: "find all matching",
+			mock: func(s subject) {
+				s.newMock.ExpectQuery(regexp.QuoteMeta(`FROM "new_entities"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(2, "b").AddRow(1, "a"))
+			},
+			expected: func(t *testing.T, log string) {
+				t.Helper()
+				assert.Empty(t, log)
+			},
+		},
+		{
+			name: "find all diverged",
+			mock: func(s subject) {
+				s.newMock.ExpectQuery(regexp.QuoteMeta(`FROM "new_entities"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(1, "x").AddRow(3, "c"))
+			},
+			expected: func(t *testing.T, log string) {
+				t.Helper()
+				assert.Contains(t, log, `msg="shadow read diverged" op="find all" changed="[map[id:1] map[id:2]]" extra=1`)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := setUp(t)
+			repo := NewReadShadow[oldEnt, newEnt](s.old, s.new, toNew)
+			repo.Logger = s.logger
+
+			s.oldMock.ExpectQuery(regexp.QuoteMeta(`FROM "old_entities"`)).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b"))
+			tt.mock(s)
+
+			res, err := repo.FindAll(context.Background(), nil, nil, nil)
+
+			assert.NoError(t, err)
+			assert.Len(t, res, 2)
+			assert.NoError(t, s.oldMock.ExpectationsWereMet())
+			assert.NoError(t, s.newMock.ExpectationsWereMet())
+			tt.expected(t, s.log.String())
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package readthrough
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/uptrace/bun"
+)
+
+// Fetcher loads an entity missing in all repositories by primary key, e.g. from an external API.
+// It returns sql.ErrNoRows when the entity doesn't exist there either.
+type Fetcher[E any] func(ctx context.Context, pk metadata.PrimaryKey) (*E, error)
+
+// Chain reads single entities through cache layers, e.g. memory repositories, then the embedded
+// repository and then Fetch, writing entities found in a later layer back to the earlier ones.
+// Other reads and all writes go to the embedded repository, writes invalidate the cache layers,
+// writes made with a transaction under RunInTx after it commits.
+type Chain[E metadata.Entity, T bun.Tx] struct {
+	repository.CrudRepository[E, T]
+
+	// Caches are tried in order before the embedded repository, they are used without a transaction.
+	Caches []repository.CrudRepository[E, T]
+	// Fetch, when set, loads entities missing in the embedded repository by FindOneByPk.
+	Fetch Fetcher[E]
+	// OnError, when set, receives write back and invalidation errors, which don't fail the operation.
+	OnError func(err error)
+	// Bus, when set, fans invalidations out to other instances, see WithBus and Listen.
+	Bus Bus
+
+	origin string
+}
+
+func NewChain[E metadata.Entity, T bun.Tx](
+	repo repository.CrudRepository[E, T],
+	fetch Fetcher[E],
+	caches ...repository.CrudRepository[E, T],
+) Chain[E, T] {
+	return Chain[E, T]{
+		CrudRepository: repo,
+		Caches:         caches,
+		Fetch:          fetch,
+	}
+}
+
+func (r Chain[E, T]) FindOne(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+) (*E, error) {
+	return r.find(ctx, tx, nil, columns, func(repo repository.CrudRepository[E, T], tx bun.IDB) (*E, error) {
+		return repo.FindOne(ctx, tx, columns, spec)
+	})
+}
+
+func (r Chain[E, T]) FindOneByPk(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	pk metadata.PrimaryKey,
+) (*E, error) {
+	return r.find(ctx, tx, pk, columns, func(repo repository.CrudRepository[E, T], tx bun.IDB) (*E, error) {
+		return repo.FindOneByPk(ctx, tx, columns, pk)
+	})
+}
+
+func (r Chain[E, T]) FindOneBy(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	criteria map[string]any,
+) (*E, error) {
+	return r.find(ctx, tx, nil, columns, func(repo repository.CrudRepository[E, T], tx bun.IDB) (*E, error) {
+		return repo.FindOneBy(ctx, tx, columns, criteria)
+	})
+}
+
+func (r Chain[E, T]) UpdateOrCreate(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	values map[string]any,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	res, err := r.CrudRepository.UpdateOrCreate(ctx, tx, spec, values, entity, columns)
+	if err == nil {
+		r.invalidate(ctx, tx, spec, nil)
+	}
+
+	return res, err //nolint:wrapcheck
+}
+
+func (r Chain[E, T]) UpdateOne(
+	ctx context.Context,
+	tx bun.IDB,
+	entity *E,
+	columnsToUpdate []string,
+	columns []string,
+) (*E, error) {
+	res, err := r.CrudRepository.UpdateOne(ctx, tx, entity, columnsToUpdate, columns)
+	if err == nil {
+		r.invalidate(ctx, tx, pkSpec((*entity).PrimaryKey()), []metadata.PrimaryKey{(*entity).PrimaryKey()})
+	}
+
+	return res, err //nolint:wrapcheck
+}
+
+func (r Chain[E, T]) ForceDelete(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (int, error) {
+	res, err := r.CrudRepository.ForceDelete(ctx, tx, spec)
+	if err == nil {
+		r.invalidate(ctx, tx, spec, nil)
+	}
+
+	return res, err //nolint:wrapcheck
+}
+
+func (r Chain[E, T]) Delete(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (int, error) {
+	res, err := r.CrudRepository.Delete(ctx, tx, spec)
+	if err == nil {
+		r.invalidate(ctx, tx, spec, nil)
+	}
+
+	return res, err //nolint:wrapcheck
+}
+
+// find tries the layers in order, a layer misses with sql.ErrNoRows, other errors are returned as is.
+// Entities read with restricted columns aren't written back, the caches would serve them to full reads.
+func (r Chain[E, T]) find(
+	ctx context.Context,
+	tx bun.IDB,
+	pk metadata.PrimaryKey,
+	columns []string,
+	find func(repo repository.CrudRepository[E, T], tx bun.IDB) (*E, error),
+) (*E, error) {
+	caches := r.Caches
+	if repository.Locking(ctx) {
+		// caches can't lock rows
+		caches = nil
+	}
+
+	for i, cache := range caches {
+		entity, err := find(cache, nil)
+		if err == nil {
+			r.writeBack(ctx, caches[:i], entity)
+
+			return entity, nil
+		}
+
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	complete := len(columns) == 0 || slices.Contains(columns, "*")
+
+	entity, err := find(r.CrudRepository, tx)
+	if err == nil {
+		if complete {
+			r.writeBack(ctx, r.Caches, entity)
+		}
+
+		return entity, nil
+	}
+
+	if pk == nil || r.Fetch == nil || !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	entity, err = r.Fetch(ctx, pk)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.CrudRepository.CreateOne(ctx, tx, entity, nil); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if complete {
+		r.writeBack(ctx, r.Caches, entity)
+	}
+
+	return entity, nil
+}
+
+func (r Chain[E, T]) writeBack(ctx context.Context, caches []repository.CrudRepository[E, T], entity *E) {
+	for _, cache := range caches {
+		copied := *entity
+
+		if _, err := cache.CreateOne(ctx, nil, &copied, nil); err != nil && r.OnError != nil {
+			r.OnError(err)
+		}
+	}
+}
+
+// invalidate removes entities matching spec from the cache layers and publishes the keys,
+// all cached entities of other instances are invalidated without keys.
+// Writes made with a transaction under RunInTx or WithPending are invalidated after commit,
+// otherwise at once, then a concurrent read may cache the old rows again until the commit.
+func (r Chain[E, T]) invalidate(ctx context.Context, tx bun.IDB, spec dataset.Specifier, keys []metadata.PrimaryKey) {
+	if p := pending(ctx); tx != nil && p != nil {
+		p.add(func(ctx context.Context) {
+			r.publish(ctx, spec, keys)
+		})
+
+		return
+	}
+
+	r.publish(ctx, spec, keys)
+}
+
+func (r Chain[E, T]) publish(ctx context.Context, spec dataset.Specifier, keys []metadata.PrimaryKey) {
+	r.evict(ctx, spec)
+
+	if r.Bus == nil {
+		return
+	}
+
+	err := r.Bus.Publish(ctx, Invalidation{Origin: r.origin, Entity: (*new(E)).EntityName(), Keys: keys})
+	if err != nil && r.OnError != nil {
+		r.OnError(err)
+	}
+}
+
+func (r Chain[E, T]) evict(ctx context.Context, spec dataset.Specifier) {
+	for _, cache := range r.Caches {
+		if _, err := cache.ForceDelete(ctx, nil, spec); err != nil && r.OnError != nil {
+			r.OnError(err)
+		}
+	}
+}
+
+func pkSpec(pk metadata.PrimaryKey) dataset.Specifier {
+	spec := dataspec.NewAnd()
+
+	for _, k := range repository.PrimaryKeyOrder(pk) {
+		spec.Append(dataspec.NewEqual(k, pk[k]))
+	}
+
+	return spec
+}
@@ -0,0 +1,217 @@
+package readthrough
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type testEnt struct {
+	ID   int
+	Name string
+}
+
+func (r testEnt) EntityName() string { return "testEnt" }
+
+func (r testEnt) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"id": r.ID} }
+
+// memRepo keeps entities by id, ForceDelete clears all of them.
+type memRepo struct {
+	repository.CrudRepository[testEnt, bun.Tx]
+
+	items     map[int]testEnt
+	reads     int
+	evictions int
+}
+
+func newMemRepo(items ...testEnt) *memRepo {
+	r := &memRepo{items: make(map[int]testEnt)}
+	for _, v := range items {
+		r.items[v.ID] = v
+	}
+
+	return r
+}
+
+func (r *memRepo) FindOneByPk(_ context.Context, _ bun.IDB, _ []string, pk metadata.PrimaryKey) (*testEnt, error) {
+	r.reads++
+
+	if v, ok := r.items[pk["id"].(int)]; ok { //nolint:forcetypeassert
+		return &v, nil
+	}
+
+	return nil, fmt.Errorf("find one: %w", sql.ErrNoRows)
+}
+
+func (r *memRepo) CreateOne(_ context.Context, _ bun.IDB, entity *testEnt, _ []string) (*testEnt, error) {
+	r.items[entity.ID] = *entity
+
+	return entity, nil
+}
+
+func (r *memRepo) UpdateOne(_ context.Context, _ bun.IDB, entity *testEnt, _ []string, _ []string) (*testEnt, error) {
+	r.items[entity.ID] = *entity
+
+	return entity, nil
+}
+
+func (r *memRepo) ForceDelete(context.Context, bun.IDB, dataset.Specifier) (int, error) {
+	r.evictions++
+
+	n := len(r.items)
+	clear(r.items)
+
+	return n, nil
+}
+
+func TestChain_FindOneByPk(t *testing.T) {
+	t.Parallel()
+
+	failure := errors.New("failure")
+
+	tests := []struct {
+		name     string
+		cache    *memRepo
+		db       *memRepo
+		fetch    Fetcher[testEnt]
+		expected *testEnt
+		err      error
+		cached   bool
+		stored   bool
+	}{
+		{
+			name:     "cache hit",
+			cache:    newMemRepo(testEnt{ID: 1, Name: "cached"}),
+			db:       newMemRepo(testEnt{ID: 1, Name: "stored"}),
+			expected: &testEnt{ID: 1, Name: "cached"},
+			cached:   true,
+			stored:   true,
+		},
+		{
+			name:     "db hit written back",
+			cache:    newMemRepo(),
+			db:       newMemRepo(testEnt{ID: 1, Name: "stored"}),
+			expected: &testEnt{ID: 1, Name: "stored"},
+			cached:   true,
+			stored:   true,
+		},
+		{
+			name:  "fetched written back",
+			cache: newMemRepo(),
+			db:    newMemRepo(),
+			fetch: func(_ context.Context, pk metadata.PrimaryKey) (*testEnt, error) {
+				return &testEnt{ID: pk["id"].(int), Name: "fetched"}, nil //nolint:forcetypeassert
+			},
+			expected: &testEnt{ID: 1, Name: "fetched"},
+			cached:   true,
+			stored:   true,
+		},
+		{
+			name:  "missing everywhere",
+			cache: newMemRepo(),
+			db:    newMemRepo(),
+			fetch: func(context.Context, metadata.PrimaryKey) (*testEnt, error) {
+				return nil, sql.ErrNoRows
+			},
+			err: sql.ErrNoRows,
+		},
+		{
+			name:  "fetch failure",
+			cache: newMemRepo(),
+			db:    newMemRepo(),
+			fetch: func(context.Context, metadata.PrimaryKey) (*testEnt, error) {
+				return nil, failure
+			},
+			err: failure,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			chain := NewChain[testEnt, bun.Tx](tt.db, tt.fetch, tt.cache)
+
+			res, err := chain.FindOneByPk(context.Background(), nil, nil, metadata.PrimaryKey{"id": 1})
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, res)
+			assert.Equal(t, tt.cached, len(tt.cache.items) == 1)
+			assert.Equal(t, tt.stored, len(tt.db.items) == 1)
+		})
+	}
+}
+
+func TestChain_UpdateOneInvalidates(t *testing.T) {
+	t.Parallel()
+
+	cache := newMemRepo(testEnt{ID: 1, Name: "old"})
+	db := newMemRepo(testEnt{ID: 1, Name: "old"})
+	chain := NewChain[testEnt, bun.Tx](db, nil, cache)
+
+	_, err := chain.UpdateOne(context.Background(), nil, &testEnt{ID: 1, Name: "new"}, nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, cache.items)
+
+	res, err := chain.FindOneByPk(context.Background(), nil, nil, metadata.PrimaryKey{"id": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "new", res.Name)
+	assert.Equal(t, "new", cache.items[1].Name)
+	assert.Equal(t, 1, db.reads)
+}
+
+func TestChain_FindOneByPkLocking(t *testing.T) {
+	t.Parallel()
+
+	cache := newMemRepo(testEnt{ID: 1, Name: "cached"})
+	db := newMemRepo(testEnt{ID: 1, Name: "stored"})
+	chain := NewChain[testEnt, bun.Tx](db, nil, cache)
+
+	ctx := repository.WithLock(context.Background(), repository.LockForUpdate, repository.LockWaitBlock)
+
+	res, err := chain.FindOneByPk(ctx, nil, nil, metadata.PrimaryKey{"id": 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &testEnt{ID: 1, Name: "stored"}, res)
+	assert.Equal(t, map[int]testEnt{1: {ID: 1, Name: "stored"}}, cache.items)
+	assert.Zero(t, cache.reads)
+}
+
+func TestChain_FindOneByPkColumns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		columns []string
+		cached  bool
+	}{
+		{name: "all", columns: nil, cached: true},
+		{name: "star", columns: []string{"*"}, cached: true},
+		{name: "restricted", columns: []string{"name"}, cached: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cache := newMemRepo()
+			chain := NewChain[testEnt, bun.Tx](newMemRepo(testEnt{ID: 1, Name: "stored"}), nil, cache)
+
+			res, err := chain.FindOneByPk(context.Background(), nil, tt.columns, metadata.PrimaryKey{"id": 1})
+
+			assert.NoError(t, err)
+			assert.Equal(t, &testEnt{ID: 1, Name: "stored"}, res)
+			assert.Equal(t, tt.cached, len(cache.items) == 1)
+		})
+	}
+}
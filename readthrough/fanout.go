@@ -0,0 +1,60 @@
+package readthrough
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+)
+
+// Invalidation is a cache invalidation message, without keys all cached entities are invalidated.
+// Keys decoded from JSON hold float64 numbers, cache layers should match them across numeric types.
+type Invalidation struct {
+	// Origin is the publishing chain, which ignores its own messages.
+	Origin string                `json:"origin"`
+	Entity string                `json:"entity"`
+	Keys   []metadata.PrimaryKey `json:"keys,omitempty"`
+}
+
+// Bus is a pub/sub channel shared by service instances, e.g. on Redis or NATS.
+type Bus interface {
+	Publish(ctx context.Context, msg Invalidation) error
+	// Subscribe delivers messages to handle until ctx is done.
+	Subscribe(ctx context.Context, handle func(ctx context.Context, msg Invalidation)) error
+}
+
+// WithBus returns the chain publishing its invalidations to the bus, run Listen to apply the ones of other instances.
+// Invalidations of writes made under RunInTx are published after the transaction commits.
+func (r Chain[E, T]) WithBus(bus Bus) Chain[E, T] {
+	origin := make([]byte, 8)
+	_, _ = rand.Read(origin)
+
+	r.Bus = bus
+	r.origin = hex.EncodeToString(origin)
+
+	return r
+}
+
+// Listen evicts entities invalidated by other instances from the cache layers until ctx is done.
+func (r Chain[E, T]) Listen(ctx context.Context) error {
+	entity := (*new(E)).EntityName()
+
+	handle := func(ctx context.Context, msg Invalidation) {
+		if msg.Origin == r.origin || msg.Entity != entity {
+			return
+		}
+
+		if len(msg.Keys) == 0 {
+			r.evict(ctx, nil)
+
+			return
+		}
+
+		for _, pk := range msg.Keys {
+			r.evict(ctx, pkSpec(pk))
+		}
+	}
+
+	return r.Bus.Subscribe(ctx, handle) //nolint:wrapcheck
+}
@@ -0,0 +1,87 @@
+package readthrough
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+// memBus delivers published messages to all subscribers synchronously.
+type memBus struct {
+	mu       sync.Mutex
+	handlers []func(ctx context.Context, msg Invalidation)
+}
+
+func (r *memBus) Publish(ctx context.Context, msg Invalidation) error {
+	r.mu.Lock()
+	handlers := r.handlers
+	r.mu.Unlock()
+
+	for _, h := range handlers {
+		h(ctx, msg)
+	}
+
+	return nil
+}
+
+func (r *memBus) Subscribe(ctx context.Context, handle func(ctx context.Context, msg Invalidation)) error {
+	r.mu.Lock()
+	r.handlers = append(r.handlers, handle)
+	r.mu.Unlock()
+
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func (r *memBus) subscribers() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.handlers)
+}
+
+func TestChain_Listen(t *testing.T) {
+	t.Parallel()
+
+	bus := &memBus{}
+	db := newMemRepo(testEnt{ID: 1, Name: "old"})
+	cacheA := newMemRepo(testEnt{ID: 1, Name: "old"})
+	cacheB := newMemRepo(testEnt{ID: 1, Name: "old"})
+
+	a := NewChain[testEnt, bun.Tx](db, nil, cacheA).WithBus(bus)
+	b := NewChain[testEnt, bun.Tx](db, nil, cacheB).WithBus(bus)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, c := range []Chain[testEnt, bun.Tx]{a, b} {
+		go func(c Chain[testEnt, bun.Tx]) { _ = c.Listen(ctx) }(c)
+	}
+
+	assert.Eventually(t, func() bool { return bus.subscribers() == 2 }, time.Second, time.Millisecond)
+
+	_, err := a.UpdateOne(context.Background(), nil, &testEnt{ID: 1, Name: "new"}, nil, nil)
+	assert.NoError(t, err)
+
+	// a evicts locally and ignores its own message
+	assert.Equal(t, 1, cacheA.evictions)
+	assert.Equal(t, 1, cacheB.evictions)
+	assert.Empty(t, cacheB.items)
+
+	assert.NoError(t, bus.Publish(context.Background(), Invalidation{Origin: "other", Entity: "otherEnt"}))
+	assert.Equal(t, 1, cacheB.evictions)
+
+	assert.NoError(t, bus.Publish(context.Background(), Invalidation{
+		Origin: "other",
+		Entity: "testEnt",
+		Keys:   []metadata.PrimaryKey{{"id": 1}, {"id": 2}},
+	}))
+	assert.Equal(t, 3, cacheB.evictions)
+	assert.Equal(t, 3, cacheA.evictions)
+}
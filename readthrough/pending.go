@@ -0,0 +1,90 @@
+package readthrough
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/uptrace/bun"
+)
+
+type pendingKey struct{}
+
+// Pending collects cache invalidations of writes made in a transaction, they run once it commits,
+// so other readers can't cache the old rows again before the new ones are visible, see RunInTx.
+type Pending struct {
+	mu  sync.Mutex
+	fns []func(ctx context.Context)
+}
+
+// WithPending returns a context collecting invalidations of Chain writes made with a transaction.
+// The caller runs them by Commit after the transaction commits or drops them by Discard.
+func WithPending(ctx context.Context) (context.Context, *Pending) {
+	p := &Pending{}
+
+	return context.WithValue(ctx, pendingKey{}, p), p
+}
+
+func pending(ctx context.Context) *Pending {
+	p, _ := ctx.Value(pendingKey{}).(*Pending)
+
+	return p
+}
+
+func (p *Pending) add(fn func(ctx context.Context)) {
+	p.mu.Lock()
+	p.fns = append(p.fns, fn)
+	p.mu.Unlock()
+}
+
+func (p *Pending) take() []func(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fns := p.fns
+	p.fns = nil
+
+	return fns
+}
+
+// Commit runs and clears the collected invalidations.
+func (p *Pending) Commit(ctx context.Context) {
+	for _, fn := range p.take() {
+		fn(ctx)
+	}
+}
+
+// Discard clears the collected invalidations without running them.
+func (p *Pending) Discard() {
+	p.take()
+}
+
+// RunInTx runs fn in a transaction of db and runs invalidations of Chain writes made in it after commit.
+// Nested calls hand their invalidations over to the outer call, which runs them after the outer commit.
+func RunInTx(
+	ctx context.Context,
+	db bun.IDB,
+	opts *sql.TxOptions,
+	fn func(ctx context.Context, tx bun.Tx) error,
+) error {
+	outer := pending(ctx)
+	txCtx, p := WithPending(ctx)
+
+	if err := db.RunInTx(txCtx, opts, fn); err != nil {
+		p.Discard()
+
+		return err //nolint:wrapcheck
+	}
+
+	if outer != nil {
+		for _, fn := range p.take() {
+			outer.add(fn)
+		}
+
+		return nil
+	}
+
+	p.Commit(ctx)
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package readthrough
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestRunInTx(t *testing.T) {
+	t.Parallel()
+
+	failure := errors.New("failure")
+
+	tests := []struct {
+		name    string
+		nested  bool
+		err     error
+		evicted bool
+	}{
+		{name: "commit", evicted: true},
+		{name: "nested commit", nested: true, evicted: true},
+		{name: "rollback", err: failure},
+		{name: "nested rollback", nested: true, err: failure},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqldb, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+
+			db := bun.NewDB(sqldb, pgdialect.New())
+
+			mock.ExpectBegin()
+
+			if tt.nested {
+				mock.ExpectExec("SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+
+				if tt.err == nil {
+					mock.ExpectExec("RELEASE SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+				} else {
+					mock.ExpectExec("ROLLBACK TO SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+				}
+			}
+
+			if tt.err == nil {
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			cache := newMemRepo(testEnt{ID: 1, Name: "old"})
+			chain := NewChain[testEnt, bun.Tx](newMemRepo(testEnt{ID: 1, Name: "old"}), nil, cache)
+
+			update := func(ctx context.Context, tx bun.Tx) error {
+				_, err := chain.UpdateOne(ctx, tx, &testEnt{ID: 1, Name: "new"}, nil, nil)
+				assert.NoError(t, err)
+				assert.Zero(t, cache.evictions)
+
+				return tt.err
+			}
+
+			err = RunInTx(context.Background(), db, nil, func(ctx context.Context, tx bun.Tx) error {
+				if tt.nested {
+					return RunInTx(ctx, tx, nil, update)
+				}
+
+				return update(ctx, tx)
+			})
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.evicted, cache.evictions == 1)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPending(t *testing.T) {
+	t.Parallel()
+
+	cache := newMemRepo(testEnt{ID: 1, Name: "old"})
+	chain := NewChain[testEnt, bun.Tx](newMemRepo(testEnt{ID: 1, Name: "old"}), nil, cache)
+
+	ctx, pending := WithPending(context.Background())
+
+	_, err := chain.UpdateOne(ctx, bun.Tx{}, &testEnt{ID: 1, Name: "new"}, nil, nil)
+	assert.NoError(t, err)
+
+	pending.Discard()
+	pending.Commit(ctx)
+	assert.Zero(t, cache.evictions)
+
+	_, err = chain.UpdateOne(ctx, bun.Tx{}, &testEnt{ID: 1, Name: "new"}, nil, nil)
+	assert.NoError(t, err)
+	assert.Zero(t, cache.evictions)
+
+	pending.Commit(ctx)
+	assert.Equal(t, 1, cache.evictions)
+
+	_, err = chain.UpdateOne(ctx, nil, &testEnt{ID: 1, Name: "newer"}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cache.evictions)
+}
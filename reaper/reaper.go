@@ -0,0 +1,79 @@
+// Package reaper periodically removes expired soft deleted rows of recycled repositories.
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const defaultInterval = time.Hour
+
+// Job repository able to remove expired trash, e.g. BunCrudRepository of a Recycled entity.
+type Job interface {
+	ReapTrash(ctx context.Context, tx bun.IDB) (int, error)
+}
+
+// Reaper runs jobs on every interval tick. A failing job is logged and doesn't stop the others.
+type Reaper struct {
+	Jobs     []Job
+	Interval time.Duration
+	Logger   *slog.Logger
+}
+
+func NewReaper(jobs ...Job) Reaper {
+	return Reaper{
+		Jobs:     jobs,
+		Interval: defaultInterval,
+	}
+}
+
+// Run reaps immediately and then on every tick until ctx is done.
+func (r Reaper) Run(ctx context.Context) error {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.Reap(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err() //nolint:wrapcheck
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reap runs every job once and returns the total number of removed rows.
+func (r Reaper) Reap(ctx context.Context) int {
+	var total int
+
+	for _, job := range r.Jobs {
+		rows, err := job.ReapTrash(ctx, nil)
+		if err != nil {
+			r.logger().ErrorContext(ctx, "reap trash failed", "job", fmt.Sprintf("%T", job), "error", err)
+
+			continue
+		}
+
+		total += rows
+	}
+
+	return total
+}
+
+func (r Reaper) logger() *slog.Logger {
+	if r.Logger == nil {
+		return slog.Default()
+	}
+
+	return r.Logger
+}
@@ -0,0 +1,58 @@
+package reaper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+var errReap = errors.New("reap failed")
+
+type jobFunc func(ctx context.Context, tx bun.IDB) (int, error)
+
+func (f jobFunc) ReapTrash(ctx context.Context, tx bun.IDB) (int, error) {
+	return f(ctx, tx)
+}
+
+func TestReaper_Reap(t *testing.T) {
+	t.Parallel()
+
+	var log bytes.Buffer
+
+	reaper := NewReaper(
+		jobFunc(func(ctx context.Context, tx bun.IDB) (int, error) { return 2, nil }),
+		jobFunc(func(ctx context.Context, tx bun.IDB) (int, error) { return 0, errReap }),
+		jobFunc(func(ctx context.Context, tx bun.IDB) (int, error) { return 3, nil }),
+	)
+	reaper.Logger = slog.New(slog.NewTextHandler(&log, nil))
+
+	assert.Equal(t, 5, reaper.Reap(context.Background()))
+	assert.Contains(t, log.String(), "reap failed")
+}
+
+func TestReaper_Run(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var runs int
+
+	reaper := NewReaper(jobFunc(func(ctx context.Context, tx bun.IDB) (int, error) {
+		runs++
+		if runs == 2 {
+			cancel()
+		}
+
+		return 0, nil
+	}))
+	reaper.Interval = time.Millisecond
+
+	assert.ErrorIs(t, reaper.Run(ctx), context.Canceled)
+	assert.GreaterOrEqual(t, runs, 2)
+}
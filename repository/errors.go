@@ -0,0 +1,40 @@
+package repository
+
+import "errors"
+
+var (
+	ErrUnknownRelation        = errors.New("unknown relation")
+	ErrUnsupportedRelation    = errors.New("unsupported relation")
+	ErrInvalidBatchSize       = errors.New("invalid batch size")
+	ErrUnknownColumn          = errors.New("unknown column")
+	ErrUnknownField           = errors.New("unknown field")
+	ErrChecksumNotSupported   = errors.New("checksum not supported")
+	ErrBiTemporalNotSupported = errors.New("bi-temporal not supported")
+	ErrValidityOverlap        = errors.New("validity overlap")
+	ErrInconsistentColumns    = errors.New("inconsistent columns")
+	ErrEmptyValues            = errors.New("empty values")
+	ErrInvalidValue           = errors.New("invalid value")
+	ErrConcurrentCreate       = errors.New("concurrent create")
+	ErrStrictViolation        = errors.New("strict mode violation")
+	ErrUnknownRole            = errors.New("unknown role")
+	ErrSoftDeleteNotSupported = errors.New("soft delete not supported")
+	ErrRecycleNotSupported    = errors.New("recycle not supported")
+	ErrTxRequired             = errors.New("transaction required")
+	ErrPartitionNotSupported  = errors.New("partition not supported")
+	ErrViewNotSupported       = errors.New("materialized view not supported")
+	ErrReturningRequired      = errors.New("returning required")
+	ErrRelatedNotFound        = errors.New("related not found")
+	ErrFullTable              = errors.New("full table operation")
+	ErrValidation             = errors.New("validation failed")
+	ErrQueryTimeout           = errors.New("query timeout")
+	ErrNotFound               = errors.New("not found")
+	ErrConflict               = errors.New("conflict")
+	ErrForeignKeyViolation    = errors.New("foreign key violation")
+	ErrNotNullViolation       = errors.New("not null violation")
+	ErrCheckViolation         = errors.New("check violation")
+	ErrSerializationFailure   = errors.New("serialization failure")
+	ErrDeadlock               = errors.New("deadlock")
+	ErrCopyNotSupported       = errors.New("copy not supported")
+	ErrReadOnlyEntity         = errors.New("read-only entity")
+	ErrNoPrimaryKey           = errors.New("no primary key")
+)
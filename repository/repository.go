@@ -23,6 +23,13 @@ type CrudRepository[E metadata.Entity, T bun.Tx] interface {
 		pk metadata.PrimaryKey,
 	) (*E, error)
 
+	FindOneBy(
+		ctx context.Context,
+		tx bun.IDB,
+		columns []string,
+		criteria map[string]any,
+	) (*E, error)
+
 	FindAll(
 		ctx context.Context,
 		tx bun.IDB,
@@ -30,6 +37,13 @@ type CrudRepository[E metadata.Entity, T bun.Tx] interface {
 		spec dataset.Specifier,
 	) ([]E, error)
 
+	FindAllBy(
+		ctx context.Context,
+		tx bun.IDB,
+		columns []string,
+		criteria map[string]any,
+	) ([]E, error)
+
 	FindPage(
 		ctx context.Context,
 		tx bun.IDB,
@@ -52,6 +66,12 @@ type CrudRepository[E metadata.Entity, T bun.Tx] interface {
 		spec dataset.Specifier,
 	) (int, error)
 
+	Exists(
+		ctx context.Context,
+		tx bun.IDB,
+		spec dataset.Specifier,
+	) (bool, error)
+
 	CreateOne(
 		ctx context.Context,
 		tx bun.IDB,
@@ -66,6 +86,32 @@ type CrudRepository[E metadata.Entity, T bun.Tx] interface {
 		columns []string,
 	) ([]E, error)
 
+	FirstOrCreate(
+		ctx context.Context,
+		tx bun.IDB,
+		spec dataset.Specifier,
+		entity *E,
+		columns []string,
+	) (*E, error)
+
+	FirstOrCreateLocked(
+		ctx context.Context,
+		tx bun.IDB,
+		key string,
+		spec dataset.Specifier,
+		entity *E,
+		columns []string,
+	) (*E, error)
+
+	UpdateOrCreate(
+		ctx context.Context,
+		tx bun.IDB,
+		spec dataset.Specifier,
+		values map[string]any,
+		entity *E,
+		columns []string,
+	) (*E, error)
+
 	UpdateOne(
 		ctx context.Context,
 		tx bun.IDB,
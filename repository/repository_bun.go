@@ -3,7 +3,9 @@ package repository
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/aso779/bun-pg-connector"
 
@@ -13,9 +15,43 @@ import (
 	"github.com/uptrace/bun"
 )
 
+// BunCrudRepository is safe for concurrent use once configured: operations only read its fields and Meta,
+// and its shared helpers (StrictMode, SlicePool, ViewRefreshes) synchronize themselves. Fields must not be
+// changed while the repository is in use.
 type BunCrudRepository[E metadata.Entity, T bun.Tx] struct {
 	ConnSet bunpgconnector.BunConnSet
-	Meta    metadata.Meta
+	// Meta is read-only for the repository, use an immutable meta, e.g. from meta.Parser or meta.Freeze,
+	// when it's shared with code that could change it.
+	Meta metadata.Meta
+	// Strict enables input validation, see StrictMode.
+	Strict *StrictMode
+	// Location, when set, is applied to time spec values and scanned timestamps.
+	Location *time.Location
+	// RequireTx makes write operations fail with ErrTxRequired when tx is nil or a pool, see WithAutocommit.
+	// Operations opening their own transaction, e.g. FirstOrCreateLocked, still run.
+	RequireTx bool
+	// Slices, when set, pools entity slices of FindAllPooled results.
+	Slices *SlicePool[E]
+	// Refreshes, when set, tracks materialized view refreshes for FindAllView staleness.
+	Refreshes *ViewRefreshes
+	// NoReturning omits RETURNING clauses for tables not supporting them, e.g. some foreign tables,
+	// generated values are not read back then.
+	NoReturning bool
+	// SoftForeignKeys checks rows referenced by to-one relations exist on CreateOne, CreateAll and UpdateOne,
+	// failing with ErrRelatedNotFound, for schemas without real foreign key constraints.
+	SoftForeignKeys bool
+	// GuardFullTable makes FindAll, Delete, ForceDelete, UpdateOrCreate, UpdateBySpec and their previews
+	// fail with ErrFullTable for nil or empty specs, see AllowFullTable.
+	GuardFullTable bool
+	// Validator, when set, validates entities before writes, in addition to Validatable entities.
+	Validator StructValidator
+	// AfterScan functions are applied in order to entities returned by finders, failing the operation on error.
+	AfterScan []AfterScanFunc[E]
+	// CreateChunkSize, when positive, is the most rows per INSERT of CreateAll, by default rows are
+	// chunked to fit the Postgres limit of 65535 bind parameters. Chunks run in one transaction.
+	CreateChunkSize int
+	// DeleteChunkSize, when positive, is the most primary keys per DELETE of ForceDeleteByPks, 1000 by default.
+	DeleteChunkSize int
 }
 
 // TODO field instead column ?
@@ -28,8 +64,18 @@ func (r BunCrudRepository[E, T]) FindOne(
 ) (*E, error) {
 	var entity E
 
-	if tx == nil {
-		tx = r.ConnSet.ReadPool()
+	if err := r.checkColumns("find one", columns...); err != nil {
+		return nil, fmt.Errorf("find one: %w", err)
+	}
+
+	lock, err := r.rowLock(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("find one: %w", err)
+	}
+
+	tx, err = r.readDB(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("find one: %w", err)
 	}
 
 	query := tx.
@@ -37,17 +83,18 @@ func (r BunCrudRepository[E, T]) FindOne(
 		Model(&entity).
 		Column(columns...)
 
-	if spec != nil && !spec.IsEmpty() {
-		for _, j := range spec.Joins(r.Meta) {
-			query.Join(j.JoinString, j.Args...)
-		}
+	r.applySpec(query, spec)
+	lock.apply(query)
+
+	err = query.Scan(ctx)
 
-		query.Where(spec.Query(r.Meta), spec.Values()...)
+	if err != nil {
+		return nil, fmt.Errorf("find one: %w", dbError(err))
 	}
 
-	err := query.Scan(ctx)
+	r.localize(&entity)
 
-	if err != nil {
+	if err := r.afterScanOne(ctx, &entity); err != nil {
 		return nil, fmt.Errorf("find one: %w", err)
 	}
 
@@ -62,12 +109,22 @@ func (r BunCrudRepository[E, T]) FindOneByPk(
 	columns []string,
 	pk metadata.PrimaryKey,
 ) (*E, error) {
-	spec := dataspec.NewAnd()
+	if len(pk) == 0 {
+		return nil, fmt.Errorf("find one by pk: %w", ErrEmptyValues)
+	}
 
-	for _, v := range pk.Sorted() {
-		for kk, vv := range v {
-			spec.Append(dataspec.NewEqual(kk, vv))
-		}
+	return r.FindOne(ctx, tx, columns, pkSpec(pk))
+}
+
+func (r BunCrudRepository[E, T]) FindOneBy(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	criteria map[string]any,
+) (*E, error) {
+	spec, err := r.criteriaSpec(criteria)
+	if err != nil {
+		return nil, fmt.Errorf("find one by: %w", err)
 	}
 
 	return r.FindOne(ctx, tx, columns, spec)
@@ -83,8 +140,22 @@ func (r BunCrudRepository[E, T]) FindAll(
 ) ([]E, error) {
 	var entities = make([]E, 0)
 
-	if tx == nil {
-		tx = r.ConnSet.ReadPool()
+	if err := r.checkColumns("find all", columns...); err != nil {
+		return entities, fmt.Errorf("find all: %w", err)
+	}
+
+	if err := r.guardFullTable(ctx, spec); err != nil {
+		return entities, fmt.Errorf("find all: %w", err)
+	}
+
+	lock, err := r.rowLock(ctx, tx)
+	if err != nil {
+		return entities, fmt.Errorf("find all: %w", err)
+	}
+
+	tx, err = r.readDB(ctx, tx)
+	if err != nil {
+		return entities, fmt.Errorf("find all: %w", err)
 	}
 
 	query := tx.
@@ -92,22 +163,37 @@ func (r BunCrudRepository[E, T]) FindAll(
 		Model(&entities).
 		Column(columns...)
 
-	if spec != nil && !spec.IsEmpty() {
-		for _, j := range spec.Joins(r.Meta) {
-			query.Join(j.JoinString, j.Args...)
-		}
+	r.applySpec(query, spec)
+	lock.apply(query)
 
-		query.Where(spec.Query(r.Meta), spec.Values()...)
+	err = query.Scan(ctx)
+	if err != nil {
+		return entities, fmt.Errorf("find all: %w", dbError(err))
 	}
 
-	err := query.Scan(ctx)
-	if err != nil {
+	r.localize(entities)
+
+	if err := r.afterScan(ctx, entities); err != nil {
 		return entities, fmt.Errorf("find all: %w", err)
 	}
 
 	return entities, nil
 }
 
+func (r BunCrudRepository[E, T]) FindAllBy(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	criteria map[string]any,
+) ([]E, error) {
+	spec, err := r.criteriaSpec(criteria)
+	if err != nil {
+		return make([]E, 0), fmt.Errorf("find all by: %w", err)
+	}
+
+	return r.FindAll(ctx, tx, columns, spec)
+}
+
 // TODO field instead column ?
 
 func (r BunCrudRepository[E, T]) FindPage(
@@ -118,40 +204,9 @@ func (r BunCrudRepository[E, T]) FindPage(
 	page dataset.Pager,
 	sort dataset.Sorter,
 ) ([]E, error) {
-	var entities = make([]E, 0)
+	res, err := r.findPage(ctx, tx, columns, spec, page, sort, false)
 
-	if tx == nil {
-		tx = r.ConnSet.ReadPool()
-	}
-
-	query := tx.
-		NewSelect().
-		Model(&entities).
-		Column(columns...)
-
-	if spec != nil && !spec.IsEmpty() {
-		for _, j := range spec.Joins(r.Meta) {
-			query.Join(j.JoinString, j.Args...)
-		}
-
-		query.Where(spec.Query(r.Meta), spec.Values()...)
-	}
-
-	if page != nil && !page.IsEmpty() {
-		query.Limit(page.GetSize())
-		query.Offset(page.GetOffset())
-	}
-
-	if sort != nil && !sort.IsEmpty() {
-		query.OrderExpr(sort.OrderBy(r.Meta))
-	}
-
-	err := query.Scan(ctx)
-	if err != nil {
-		return entities, fmt.Errorf("find page: %w", err)
-	}
-
-	return entities, nil
+	return res.Items, err
 }
 
 // TODO field instead column ?
@@ -162,40 +217,13 @@ func (r BunCrudRepository[E, T]) FindAllByPks(
 	columns []string,
 	pks []metadata.PrimaryKey,
 ) ([]E, error) {
-	var (
-		keys        []string
-		values      []any
-		isComposite bool
-		spec        dataset.Specifier
-	)
-
-	for i, pk := range pks {
-		if i == 0 {
-			isComposite = pk.IsComposite()
-			keys = pk.SortedKeys()
-		}
-
-		if isComposite {
-			var valuesGroup []any
-
-			for _, vv := range pk.Sorted() {
-				for _, vvv := range vv {
-					valuesGroup = append(valuesGroup, vvv)
-				}
-			}
-
-			values = append(values, valuesGroup) // nolint:asasalint
-		} else {
-			for _, vv := range pk {
-				values = append(values, vv)
-			}
-		}
+	if len(pks) == 0 {
+		return make([]E, 0), nil
 	}
 
-	if isComposite {
-		spec = dataspec.NewCompositeIn(keys, bun.In(values))
-	} else {
-		spec = dataspec.NewIn(keys[0], bun.In(values))
+	spec, err := pksSpec(pks)
+	if err != nil {
+		return make([]E, 0), fmt.Errorf("find all by pks: %w", err)
 	}
 
 	return r.FindAll(ctx, tx, columns, spec)
@@ -208,30 +236,51 @@ func (r BunCrudRepository[E, T]) Count(
 ) (int, error) {
 	var entity E
 
-	if tx == nil {
-		tx = r.ConnSet.ReadPool()
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("count: %w", err)
 	}
 
 	query := tx.
 		NewSelect().
 		Model(&entity)
 
-	if spec != nil && !spec.IsEmpty() {
-		for _, j := range spec.Joins(r.Meta) {
-			query.Join(j.JoinString, j.Args...)
-		}
-
-		query.Where(spec.Query(r.Meta), spec.Values()...)
-	}
+	r.applySpec(query, spec)
 
 	count, err := query.Count(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("count: %w", err)
+		return 0, fmt.Errorf("count: %w", dbError(err))
 	}
 
 	return count, nil
 }
 
+// Exists reports whether any row matches spec with SELECT EXISTS (SELECT 1 ...).
+func (r BunCrudRepository[E, T]) Exists(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (bool, error) {
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return false, fmt.Errorf("exists: %w", err)
+	}
+
+	query := tx.
+		NewSelect().
+		Model((*E)(nil)).
+		ColumnExpr("1")
+
+	r.applySpec(query, spec)
+
+	exists, err := query.Exists(ctx)
+	if err != nil {
+		return false, fmt.Errorf("exists: %w", dbError(err))
+	}
+
+	return exists, nil
+}
+
 // TODO field instead column ?
 
 func (r BunCrudRepository[E, T]) CreateOne(
@@ -240,19 +289,26 @@ func (r BunCrudRepository[E, T]) CreateOne(
 	entity *E,
 	columns []string,
 ) (*E, error) {
-	if tx == nil {
-		tx = r.ConnSet.WritePool()
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("create one: %w", err)
 	}
 
-	_, err := tx.NewInsert().
-		Model(entity).
-		Returning(strings.Join(columns, ",")).
-		Exec(ctx)
+	if err := r.prepareInsertOne(ctx, tx, entity); err != nil {
+		return nil, fmt.Errorf("create one: %w", err)
+	}
 
+	query, err := r.newInsertOne(tx, entity, columns)
 	if err != nil {
 		return nil, fmt.Errorf("crate one: %w", err)
 	}
 
+	_, err = query.Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("crate one: %w", dbError(err))
+	}
+
 	return entity, nil
 }
 
@@ -264,17 +320,19 @@ func (r BunCrudRepository[E, T]) CreateAll(
 	entities []E,
 	columns []string,
 ) ([]E, error) {
-	if tx == nil {
-		tx = r.ConnSet.WritePool()
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return entities, fmt.Errorf("create all: %w", err)
 	}
 
-	_, err := tx.NewInsert().
-		Model(&entities).
-		Returning(strings.Join(columns, ",")).
-		Exec(ctx)
+	if err := r.prepareInsertAll(ctx, tx, entities); err != nil {
+		return entities, fmt.Errorf("create all: %w", err)
+	}
+
+	err = r.insertChunks(ctx, tx, entities, columns)
 
 	if err != nil {
-		return entities, fmt.Errorf("create one: %w", err)
+		return entities, fmt.Errorf("create all: %w", err)
 	}
 
 	return entities, nil
@@ -289,19 +347,56 @@ func (r BunCrudRepository[E, T]) UpdateOne(
 	columnsToUpdate []string,
 	columns []string,
 ) (*E, error) {
-	if tx == nil {
-		tx = r.ConnSet.WritePool()
+	if err := r.checkColumns("update one", columnsToUpdate...); err != nil {
+		return entity, fmt.Errorf("update one: %w", err)
 	}
 
-	_, err := tx.NewUpdate().
+	if err := r.checkColumns("update one", columns...); err != nil {
+		return entity, fmt.Errorf("update one: %w", err)
+	}
+
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return entity, fmt.Errorf("update one: %w", err)
+	}
+
+	if err := r.normalize(tx, entity); err != nil {
+		return entity, fmt.Errorf("update one: %w", err)
+	}
+
+	if err := r.validate(ctx, *entity); err != nil {
+		return entity, fmt.Errorf("update one: %w", err)
+	}
+
+	if err := r.checkRelated(ctx, tx, []E{*entity}, columnsToUpdate); err != nil {
+		return entity, fmt.Errorf("update one: %w", err)
+	}
+
+	if err := r.setChecksum(tx, entity); err != nil {
+		return entity, fmt.Errorf("update one: %w", err)
+	}
+
+	if cs, ok := any(*entity).(Checksummed); ok && len(columnsToUpdate) > 0 {
+		columnsToUpdate = append(columnsToUpdate[:len(columnsToUpdate):len(columnsToUpdate)], cs.ChecksumColumn())
+	}
+
+	generated := generatedColumns(*entity)
+
+	query := tx.NewUpdate().
 		Model(entity).
-		Column(columnsToUpdate...).
 		WherePK().
-		Returning(strings.Join(columns, ",")).
-		Exec(ctx)
+		Returning(r.returning(strings.Join(withGenerated(columns, generated), ",")))
+
+	if len(columnsToUpdate) > 0 {
+		query.Column(withoutGenerated(columnsToUpdate, generated)...)
+	} else if len(generated) > 0 {
+		query.ExcludeColumn(generated...)
+	}
+
+	_, err = query.Exec(ctx)
 
 	if err != nil {
-		return entity, fmt.Errorf("update one: %w", err)
+		return entity, fmt.Errorf("update one: %w", dbError(err))
 	}
 
 	return entity, nil
@@ -314,20 +409,25 @@ func (r BunCrudRepository[E, T]) ForceDelete(
 ) (int, error) {
 	var entity E
 
-	if tx == nil {
-		tx = r.ConnSet.WritePool()
+	if err := r.guardFullTable(ctx, spec); err != nil {
+		return 0, fmt.Errorf("force delete: %w", err)
+	}
+
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("force delete: %w", err)
 	}
 
 	query := tx.NewDelete().
 		ForceDelete().
 		Model(&entity)
 	if spec != nil && !spec.IsEmpty() {
-		query.Where(spec.Query(r.Meta), spec.Values()...)
+		query.Where(spec.Query(r.specMeta()), r.bindValues(spec.Values())...)
 	}
 
 	res, err := query.Exec(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("force delete: %w", err)
+		return 0, fmt.Errorf("force delete: %w", dbError(err))
 	}
 
 	rows, err := res.RowsAffected()
@@ -342,19 +442,41 @@ func (r BunCrudRepository[E, T]) Delete(
 ) (int, error) {
 	var entity E
 
-	if tx == nil {
-		tx = r.ConnSet.WritePool()
+	if err := r.guardFullTable(ctx, spec); err != nil {
+		return 0, fmt.Errorf("delete: %w", err)
+	}
+
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("delete: %w", err)
+	}
+
+	applied := r.evalSpec(spec)
+
+	if audited := r.auditedDelete(ctx, tx); audited != nil {
+		if applied != nil {
+			audited.Where(applied.query, applied.values...)
+		}
+
+		res, err := audited.Exec(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("delete: %w", dbError(err))
+		}
+
+		rows, err := res.RowsAffected()
+
+		return int(rows), err
 	}
 
 	query := tx.NewDelete().
 		Model(&entity)
-	if spec != nil && !spec.IsEmpty() {
-		query.Where(spec.Query(r.Meta), spec.Values()...)
+	if applied != nil {
+		query.Where(applied.query, applied.values...)
 	}
 
 	res, err := query.Exec(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("delete: %w", err)
+		return 0, fmt.Errorf("delete: %w", dbError(err))
 	}
 
 	rows, err := res.RowsAffected()
@@ -364,21 +486,29 @@ func (r BunCrudRepository[E, T]) Delete(
 
 // TODO field instead column ?
 
+// IsColumnValueUnique reports whether a row has the column value, comparing UniqueExpressions of the entity
+// the way its unique indexes do.
 func (r BunCrudRepository[E, T]) IsColumnValueUnique(
 	ctx context.Context,
 	tx bun.IDB,
 	column string,
 	value any,
 ) (bool, error) {
-	if tx == nil {
-		tx = r.ConnSet.ReadPool()
+	condition, args, err := r.uniqueCondition(column, value)
+	if err != nil {
+		return false, fmt.Errorf("is column value unique: %w", err)
+	}
+
+	tx, err = r.readDB(ctx, tx)
+	if err != nil {
+		return false, fmt.Errorf("is column value unique: %w", err)
 	}
 
 	exists, err := tx.
 		NewSelect().
 		Column("id").
 		Model((*E)(nil)).
-		Where(column+" = ?", value).
+		Where(condition, args...).
 		Exists(ctx)
 	if err != nil {
 		return false, fmt.Errorf("is column value unique: %w", err)
@@ -386,3 +516,85 @@ func (r BunCrudRepository[E, T]) IsColumnValueUnique(
 
 	return exists, nil
 }
+
+// appliedSpec is a spec evaluated once for the query and its description.
+type appliedSpec struct {
+	joins  []metadata.Join
+	query  string
+	values []any
+}
+
+// evalSpec evaluates spec joins, skipping duplicates, condition and bound values, nil for an empty spec.
+func (r BunCrudRepository[E, T]) evalSpec(spec dataset.Specifier) *appliedSpec {
+	if spec == nil || spec.IsEmpty() {
+		return nil
+	}
+
+	joins := spec.Joins(r.specMeta())
+	res := &appliedSpec{
+		joins:  make([]metadata.Join, 0, len(joins)),
+		query:  spec.Query(r.specMeta()),
+		values: r.bindValues(spec.Values()),
+	}
+
+	for _, j := range joins {
+		if !slices.ContainsFunc(res.joins, func(v metadata.Join) bool { return v.JoinString == j.JoinString }) {
+			res.joins = append(res.joins, j)
+		}
+	}
+
+	return res
+}
+
+// applySpec adds spec joins, skipping duplicates, and the spec condition to the select query.
+func (r BunCrudRepository[E, T]) applySpec(query *bun.SelectQuery, spec dataset.Specifier) {
+	r.evalSpec(spec).apply(query)
+}
+
+func (r *appliedSpec) apply(query *bun.SelectQuery) {
+	if r == nil {
+		return
+	}
+
+	for _, j := range r.joins {
+		query.Join(j.JoinString, j.Args...)
+	}
+
+	query.Where(r.query, r.values...)
+}
+
+// joined reports whether the spec joins relations, so rows may repeat per related row.
+func (r *appliedSpec) joined() bool {
+	return r != nil && len(r.joins) > 0
+}
+
+// criteriaSpec builds an AND equality spec from presenter or persistence name criteria, nil values match NULL.
+// Empty criteria fail with ErrEmptyValues instead of matching every row.
+func (r BunCrudRepository[E, T]) criteriaSpec(criteria map[string]any) (dataset.Specifier, error) {
+	if len(criteria) == 0 {
+		return nil, ErrEmptyValues
+	}
+
+	spec := dataspec.NewAnd()
+	presenters := r.Meta.PersistencePresenterMapping()
+
+	for _, k := range sortedKeys(criteria) {
+		field := k
+		if r.Meta.PresenterToPersistence(k) == "" {
+			presenter, ok := presenters[k]
+			if !ok {
+				return nil, fmt.Errorf("%s: %w", k, ErrUnknownField)
+			}
+
+			field = presenter
+		}
+
+		if criteria[k] == nil {
+			spec.Append(dataspec.NewIsNull(field))
+		} else {
+			spec.Append(dataspec.NewEqual(field, criteria[k]))
+		}
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// AfterScanFunc enriches or transforms a scanned entity, e.g. computes derived fields or resolves signed URLs.
+type AfterScanFunc[E any] func(ctx context.Context, entity *E) error
+
+// afterScanOne runs the AfterScan pipeline on entity, functions are applied in the registration order.
+func (r BunCrudRepository[E, T]) afterScanOne(ctx context.Context, entity *E) error {
+	for _, fn := range r.AfterScan {
+		if err := fn(ctx, entity); err != nil {
+			return fmt.Errorf("after scan: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// afterScan runs the AfterScan pipeline on every entity, stopping at the first error.
+func (r BunCrudRepository[E, T]) afterScan(ctx context.Context, entities []E) error {
+	if len(r.AfterScan) == 0 {
+		return nil
+	}
+
+	for i := range entities {
+		if err := r.afterScanOne(ctx, &entities[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
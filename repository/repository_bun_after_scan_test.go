@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_AfterScan(t *testing.T) {
+	t.Parallel()
+
+	upper := func(_ context.Context, e *TestSimpleEnt) error {
+		e.Name = strings.ToUpper(e.Name)
+
+		return nil
+	}
+	suffix := func(_ context.Context, e *TestSimpleEnt) error {
+		e.Name += "!"
+
+		return nil
+	}
+	errFailed := errors.New("failed")
+	failing := func(_ context.Context, e *TestSimpleEnt) error {
+		return errFailed
+	}
+
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b")
+	}
+
+	tests := []struct {
+		name      string
+		afterScan []AfterScanFunc[TestSimpleEnt]
+		find      func(repo *TestSimpleEntBunRepo) ([]TestSimpleEnt, error)
+		expected  []string
+		wantErr   error
+	}{
+		{
+			name:      "find one",
+			afterScan: []AfterScanFunc[TestSimpleEnt]{upper, suffix},
+			find: func(repo *TestSimpleEntBunRepo) ([]TestSimpleEnt, error) {
+				res, err := repo.FindOne(context.Background(), nil, nil, dataspec.NewEqual("id", 1))
+				if err != nil {
+					return nil, err //nolint:wrapcheck
+				}
+
+				return []TestSimpleEnt{*res}, nil
+			},
+			expected: []string{"A!"},
+		},
+		{
+			name:      "find all",
+			afterScan: []AfterScanFunc[TestSimpleEnt]{upper, suffix},
+			find: func(repo *TestSimpleEntBunRepo) ([]TestSimpleEnt, error) {
+				return repo.FindAll(context.Background(), nil, nil, nil) //nolint:wrapcheck
+			},
+			expected: []string{"A!", "B!"},
+		},
+		{
+			name:      "find page",
+			afterScan: []AfterScanFunc[TestSimpleEnt]{suffix, upper},
+			find: func(repo *TestSimpleEntBunRepo) ([]TestSimpleEnt, error) {
+				return repo.FindPage(context.Background(), nil, nil, nil, nil, nil) //nolint:wrapcheck
+			},
+			expected: []string{"A!", "B!"},
+		},
+		{
+			name: "no pipeline",
+			find: func(repo *TestSimpleEntBunRepo) ([]TestSimpleEnt, error) {
+				return repo.FindAll(context.Background(), nil, nil, nil) //nolint:wrapcheck
+			},
+			expected: []string{"a", "b"},
+		},
+		{
+			name:      "failing function",
+			afterScan: []AfterScanFunc[TestSimpleEnt]{upper, failing},
+			find: func(repo *TestSimpleEntBunRepo) ([]TestSimpleEnt, error) {
+				return repo.FindPage(context.Background(), nil, nil, nil, nil, nil) //nolint:wrapcheck
+			},
+			wantErr: errFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+			repo.AfterScan = tt.afterScan
+
+			subject.conn.Mock.ExpectQuery("^SELECT").WillReturnRows(rows())
+
+			res, err := tt.find(repo)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			names := make([]string, len(res))
+			for i, e := range res {
+				names[i] = e.Name
+			}
+
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
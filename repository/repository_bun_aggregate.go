@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// AggregateFunc SQL aggregate function of Aggregate.
+type AggregateFunc string
+
+const (
+	AggregateSum AggregateFunc = "SUM"
+	AggregateMin AggregateFunc = "MIN"
+	AggregateMax AggregateFunc = "MAX"
+	AggregateAvg AggregateFunc = "AVG"
+	// AggregateStddev is the sample standard deviation.
+	AggregateStddev AggregateFunc = "STDDEV"
+	// AggregateVariance is the sample variance.
+	AggregateVariance AggregateFunc = "VARIANCE"
+)
+
+// Ordered-set aggregates of AggregatePercentileCont and AggregatePercentileDisc.
+const (
+	percentileCont = "PERCENTILE_CONT"
+	percentileDisc = "PERCENTILE_DISC"
+)
+
+// AggregatePercentileCont is the continuous percentile of fraction, between 0 and 1, interpolating
+// between values, e.g. 0.95 for p95 latency.
+func AggregatePercentileCont(fraction float64) AggregateFunc {
+	return AggregateFunc(percentileCont + "(" + strconv.FormatFloat(fraction, 'f', -1, 64) + ")")
+}
+
+// AggregatePercentileDisc is the discrete percentile of fraction, between 0 and 1, the first value
+// whose position in the ordering reaches fraction.
+func AggregatePercentileDisc(fraction float64) AggregateFunc {
+	return AggregateFunc(percentileDisc + "(" + strconv.FormatFloat(fraction, 'f', -1, 64) + ")")
+}
+
+// expr returns the float8 expression of fn over a ?TableAlias qualified column argument.
+func (fn AggregateFunc) expr() (string, bool) {
+	switch fn {
+	case AggregateSum, AggregateMin, AggregateMax, AggregateAvg, AggregateStddev, AggregateVariance:
+		return string(fn) + "(?TableAlias.?)::float8", true
+	}
+
+	for _, name := range []string{percentileCont, percentileDisc} {
+		arg, ok := strings.CutPrefix(string(fn), name+"(")
+		if !ok {
+			continue
+		}
+
+		arg, ok = strings.CutSuffix(arg, ")")
+		if !ok {
+			return "", false
+		}
+
+		fraction, err := strconv.ParseFloat(arg, 64)
+		if err != nil || !(fraction >= 0 && fraction <= 1) {
+			return "", false
+		}
+
+		// the formatted fraction is inlined, it can't carry anything but a number
+		return "(" + name + "(" + strconv.FormatFloat(fraction, 'f', -1, 64) + ") WITHIN GROUP (ORDER BY ?TableAlias.?))::float8", true
+	}
+
+	return "", false
+}
+
+// groupValueColumn is the result column of grouped aggregates, it can't clash with entity columns.
+const groupValueColumn = "__value"
+
+// GroupRow aggregate of a group, Key holds the group-by values by presenter name.
+type GroupRow struct {
+	Key   map[string]any
+	Value float64
+}
+
+// Aggregate returns fn over a numeric column of rows matching spec, spec joins are applied as in Count.
+// Column is a presenter or persistence name, an empty set aggregates to zero. Use SumDecimal for exact sums.
+// Stddev and variance of a single row are zero as well.
+func (r BunCrudRepository[E, T]) Aggregate(
+	ctx context.Context,
+	tx bun.IDB,
+	fn AggregateFunc,
+	column string,
+	spec dataset.Specifier,
+) (float64, error) {
+	expr, column, err := r.aggregateExpr(fn, column)
+	if err != nil {
+		return 0, fmt.Errorf("aggregate: %w", err)
+	}
+
+	tx, err = r.readDB(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("aggregate: %w", err)
+	}
+
+	var res sql.NullFloat64
+
+	query := tx.
+		NewSelect().
+		Model((*E)(nil)).
+		ColumnExpr(expr, bun.Ident(column))
+
+	r.applySpec(query, spec)
+
+	err = query.Scan(ctx, &res)
+	if err != nil {
+		return 0, fmt.Errorf("aggregate: %w", dbError(err))
+	}
+
+	return res.Float64, nil
+}
+
+// GroupedCount counts rows matching spec per distinct values of the groupBy fields, ordered by them.
+// Fields are presenter or persistence names.
+func (r BunCrudRepository[E, T]) GroupedCount(
+	ctx context.Context,
+	tx bun.IDB,
+	groupBy []string,
+	spec dataset.Specifier,
+) ([]GroupRow, error) {
+	rows, err := r.grouped(ctx, tx, groupBy, spec, "count(*)::float8")
+	if err != nil {
+		return rows, fmt.Errorf("grouped count: %w", err)
+	}
+
+	return rows, nil
+}
+
+// GroupedAggregate returns fn over a numeric column of rows matching spec per distinct values of the
+// groupBy fields, ordered by them. Groups aggregating to NULL have a zero value.
+func (r BunCrudRepository[E, T]) GroupedAggregate(
+	ctx context.Context,
+	tx bun.IDB,
+	fn AggregateFunc,
+	column string,
+	groupBy []string,
+	spec dataset.Specifier,
+) ([]GroupRow, error) {
+	expr, column, err := r.aggregateExpr(fn, column)
+	if err != nil {
+		return make([]GroupRow, 0), fmt.Errorf("grouped aggregate: %w", err)
+	}
+
+	rows, err := r.grouped(ctx, tx, groupBy, spec, expr, bun.Ident(column))
+	if err != nil {
+		return rows, fmt.Errorf("grouped aggregate: %w", err)
+	}
+
+	return rows, nil
+}
+
+// aggregateExpr checks fn and returns its expression and the persistence name of column.
+func (r BunCrudRepository[E, T]) aggregateExpr(fn AggregateFunc, column string) (string, string, error) {
+	expr, ok := fn.expr()
+	if !ok {
+		return "", "", fmt.Errorf("%s: %w", fn, ErrInvalidValue)
+	}
+
+	column = r.persistenceName(column)
+	if _, ok := r.Meta.PersistencePresenterMapping()[column]; !ok {
+		return "", "", fmt.Errorf("%s: %w", column, ErrUnknownColumn)
+	}
+
+	return expr, column, nil
+}
+
+// grouped selects the expr value per group of the groupBy fields.
+func (r BunCrudRepository[E, T]) grouped(
+	ctx context.Context,
+	tx bun.IDB,
+	groupBy []string,
+	spec dataset.Specifier,
+	expr string,
+	args ...any,
+) ([]GroupRow, error) {
+	var res = make([]GroupRow, 0)
+
+	if len(groupBy) == 0 {
+		return res, fmt.Errorf("group by: %w", ErrEmptyValues)
+	}
+
+	known := r.Meta.PersistencePresenterMapping()
+	columns := make([]string, len(groupBy))
+
+	for i, field := range groupBy {
+		columns[i] = r.persistenceName(field)
+		if _, ok := known[columns[i]]; !ok {
+			return res, fmt.Errorf("%s: %w", field, ErrUnknownColumn)
+		}
+	}
+
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return res, err
+	}
+
+	query := tx.
+		NewSelect().
+		Model((*E)(nil))
+
+	for _, c := range columns {
+		query.
+			ColumnExpr("?TableAlias.?", bun.Ident(c)).
+			GroupExpr("?TableAlias.?", bun.Ident(c)).
+			OrderExpr("?TableAlias.?", bun.Ident(c))
+	}
+
+	query.ColumnExpr(expr+" AS ?", append(args, bun.Ident(groupValueColumn))...)
+
+	r.applySpec(query, spec)
+
+	var rows []map[string]any
+
+	if err := query.Scan(ctx, &rows); err != nil {
+		return res, dbError(err)
+	}
+
+	for _, row := range rows {
+		key := make(map[string]any, len(columns))
+		for _, c := range columns {
+			key[known[c]] = row[c]
+		}
+
+		value, _ := row[groupValueColumn].(float64)
+
+		res = append(res, GroupRow{Key: key, Value: value})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_Aggregate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		fn       AggregateFunc
+		column   string
+		mock     func(set *MockBunConnSet)
+		expected float64
+		err      error
+	}{
+		{
+			name:   "sum",
+			fn:     AggregateSum,
+			column: "total",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT SUM("test_invoices"."total")::float8 FROM "test_invoices" WHERE (test_invoices.total > '10.05')`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(30.5))
+			},
+			expected: 30.5,
+		},
+		{
+			name:   "avg",
+			fn:     AggregateAvg,
+			column: "total",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT AVG("test_invoices"."total")::float8 FROM "test_invoices" WHERE (test_invoices.total > '10.05')`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"avg"}).AddRow(15.25))
+			},
+			expected: 15.25,
+		},
+		{
+			name:   "max of empty set",
+			fn:     AggregateMax,
+			column: "total",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT MAX("test_invoices"."total")::float8 FROM "test_invoices" WHERE (test_invoices.total > '10.05')`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(nil))
+			},
+			expected: 0,
+		},
+		{
+			name:   "stddev",
+			fn:     AggregateStddev,
+			column: "total",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT STDDEV("test_invoices"."total")::float8 FROM "test_invoices" WHERE (test_invoices.total > '10.05')`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"stddev"}).AddRow(2.5))
+			},
+			expected: 2.5,
+		},
+		{
+			name:   "variance",
+			fn:     AggregateVariance,
+			column: "total",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT VARIANCE("test_invoices"."total")::float8 FROM "test_invoices" WHERE (test_invoices.total > '10.05')`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"variance"}).AddRow(6.25))
+			},
+			expected: 6.25,
+		},
+		{
+			name:   "continuous percentile",
+			fn:     AggregatePercentileCont(0.95),
+			column: "total",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT (PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY "test_invoices"."total"))::float8 FROM "test_invoices" WHERE (test_invoices.total > '10.05')`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"percentile_cont"}).AddRow(98.1))
+			},
+			expected: 98.1,
+		},
+		{
+			name:   "discrete median",
+			fn:     AggregatePercentileDisc(0.5),
+			column: "total",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT (PERCENTILE_DISC(0.5) WITHIN GROUP (ORDER BY "test_invoices"."total"))::float8 FROM "test_invoices" WHERE (test_invoices.total > '10.05')`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"percentile_disc"}).AddRow(20))
+			},
+			expected: 20,
+		},
+		{
+			name:   "percentile out of range",
+			fn:     AggregatePercentileCont(1.5),
+			column: "total",
+			mock:   func(conn *MockBunConnSet) {},
+			err:    ErrInvalidValue,
+		},
+		{
+			name:   "percentile of an expression",
+			fn:     AggregateFunc("PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY 1)) --("),
+			column: "total",
+			mock:   func(conn *MockBunConnSet) {},
+			err:    ErrInvalidValue,
+		},
+		{
+			name:   "unknown function",
+			fn:     AggregateFunc("MEDIAN"),
+			column: "total",
+			mock:   func(conn *MockBunConnSet) {},
+			err:    ErrInvalidValue,
+		},
+		{
+			name:   "unknown column",
+			fn:     AggregateMin,
+			column: "amount",
+			mock:   func(conn *MockBunConnSet) {},
+			err:    ErrUnknownColumn,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestInvoiceEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			res, err := repo.Aggregate(
+				context.Background(),
+				nil,
+				tt.fn,
+				tt.column,
+				dataspec.NewGt("total", decimal.RequireFromString("10.05")),
+			)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
+func TestBunCrudRepository_Grouped(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		run      func(repo *TestSimpleEntBunRepo) ([]GroupRow, error)
+		expected []GroupRow
+		err      error
+	}{
+		{
+			name: "grouped count",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."name", count(*)::float8 AS "__value" FROM "test_simple_entities" WHERE (test_simple_entities.id > 1) GROUP BY "test_simple_entities"."name" ORDER BY "test_simple_entities"."name"`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"name", "__value"}).AddRow("a", 2.0).AddRow("b", 3.0))
+			},
+			run: func(repo *TestSimpleEntBunRepo) ([]GroupRow, error) {
+				return repo.GroupedCount(context.Background(), nil, []string{"name"}, dataspec.NewGt("id", 1)) //nolint:wrapcheck
+			},
+			expected: []GroupRow{
+				{Key: map[string]any{"name": "a"}, Value: 2},
+				{Key: map[string]any{"name": "b"}, Value: 3},
+			},
+		},
+		{
+			name: "grouped aggregate by persistence name",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."name", MAX("test_simple_entities"."id")::float8 AS "__value" FROM "test_simple_entities" GROUP BY "test_simple_entities"."name" ORDER BY "test_simple_entities"."name"`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"name", "__value"}).AddRow("a", 7.0).AddRow("b", nil))
+			},
+			run: func(repo *TestSimpleEntBunRepo) ([]GroupRow, error) {
+				return repo.GroupedAggregate(context.Background(), nil, AggregateMax, "id", []string{"name"}, nil) //nolint:wrapcheck
+			},
+			expected: []GroupRow{
+				{Key: map[string]any{"name": "a"}, Value: 7},
+				{Key: map[string]any{"name": "b"}, Value: 0},
+			},
+		},
+		{
+			name: "grouped percentile",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."name", (PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY "test_simple_entities"."id"))::float8 AS "__value" FROM "test_simple_entities" GROUP BY "test_simple_entities"."name" ORDER BY "test_simple_entities"."name"`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"name", "__value"}).AddRow("a", 9.9))
+			},
+			run: func(repo *TestSimpleEntBunRepo) ([]GroupRow, error) {
+				return repo.GroupedAggregate(context.Background(), nil, AggregatePercentileCont(0.99), "id", []string{"name"}, nil) //nolint:wrapcheck
+			},
+			expected: []GroupRow{{Key: map[string]any{"name": "a"}, Value: 9.9}},
+		},
+		{
+			name: "grouped count without fields",
+			mock: func(conn *MockBunConnSet) {},
+			run: func(repo *TestSimpleEntBunRepo) ([]GroupRow, error) {
+				return repo.GroupedCount(context.Background(), nil, nil, nil) //nolint:wrapcheck
+			},
+			expected: []GroupRow{},
+			err:      ErrEmptyValues,
+		},
+		{
+			name: "grouped count by unknown field",
+			mock: func(conn *MockBunConnSet) {},
+			run: func(repo *TestSimpleEntBunRepo) ([]GroupRow, error) {
+				return repo.GroupedCount(context.Background(), nil, []string{"status"}, nil) //nolint:wrapcheck
+			},
+			expected: []GroupRow{},
+			err:      ErrUnknownColumn,
+		},
+		{
+			name: "grouped aggregate with unknown function",
+			mock: func(conn *MockBunConnSet) {},
+			run: func(repo *TestSimpleEntBunRepo) ([]GroupRow, error) {
+				return repo.GroupedAggregate(context.Background(), nil, "MEDIAN", "id", []string{"name"}, nil) //nolint:wrapcheck
+			},
+			expected: []GroupRow{},
+			err:      ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			res, err := tt.run(repo)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
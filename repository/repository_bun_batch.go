@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// ForEachBatchByPk walks rows matching spec in primary key order and calls fn for every batch.
+// Batches are selected by keyset ranges on the primary key, each with its own query, so no long transaction
+// or OFFSET scan is held. Iteration stops on the first fn error.
+func (r BunCrudRepository[E, T]) ForEachBatchByPk(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	batchSize int,
+	fn func([]E) error,
+) error {
+	return r.ForEachBatchByPkAfter(ctx, tx, spec, nil, batchSize, fn)
+}
+
+// ForEachBatchByPkAfter is ForEachBatchByPk starting after the given primary key values,
+// listed in the table primary key order. It's used to resume an interrupted walk.
+func (r BunCrudRepository[E, T]) ForEachBatchByPkAfter(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	after []any,
+	batchSize int,
+	fn func([]E) error,
+) error {
+	if err := r.forEachBatch(ctx, tx, nil, spec, after, batchSize, fn); err != nil {
+		return fmt.Errorf("for each batch by pk: %w", err)
+	}
+
+	return nil
+}
+
+// FindInBatches is ForEachBatchByPk selecting only columns, primary key columns are always selected
+// to page by them. Pass tx to walk all batches in one transaction.
+func (r BunCrudRepository[E, T]) FindInBatches(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+	batchSize int,
+	fn func([]E) error,
+) error {
+	if err := r.checkColumns("find in batches", columns...); err != nil {
+		return fmt.Errorf("find in batches: %w", err)
+	}
+
+	if err := r.forEachBatch(ctx, tx, columns, spec, nil, batchSize, fn); err != nil {
+		return fmt.Errorf("find in batches: %w", err)
+	}
+
+	return nil
+}
+
+func (r BunCrudRepository[E, T]) forEachBatch(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+	after []any,
+	batchSize int,
+	fn func([]E) error,
+) error {
+	if batchSize <= 0 {
+		return ErrInvalidBatchSize
+	}
+
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	table := tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem())
+	if len(table.PKs) == 0 {
+		return ErrNoPrimaryKey
+	}
+
+	keys := make([]string, len(table.PKs))
+	idents := make([]any, len(table.PKs))
+
+	for i, f := range table.PKs {
+		keys[i] = "?TableAlias.?"
+		idents[i] = bun.Ident(f.Name)
+
+		if len(columns) > 0 && !slices.Contains(columns, f.Name) {
+			columns = append(columns[:len(columns):len(columns)], f.Name)
+		}
+	}
+
+	keyset := "(" + strings.Join(keys, ", ") + ") > (?)"
+
+	last := after
+
+	for {
+		var batch []E
+
+		query := tx.
+			NewSelect().
+			Model(&batch).
+			Column(columns...)
+
+		r.applySpec(query, spec)
+
+		if last != nil {
+			query.Where(keyset, append(idents[:len(idents):len(idents)], bun.In(last))...)
+		}
+
+		for _, ident := range idents {
+			query.OrderExpr("?TableAlias.? ASC", ident)
+		}
+
+		if err := query.Limit(batchSize).Scan(ctx); err != nil {
+			return dbError(err)
+		}
+
+		if len(batch) == 0 {
+			return nil
+		}
+
+		r.localize(batch)
+
+		if err := r.afterScan(ctx, batch); err != nil {
+			return err
+		}
+
+		if err := fn(batch); err != nil {
+			return err
+		}
+
+		if len(batch) < batchSize {
+			return nil
+		}
+
+		strct := reflect.ValueOf(&batch[len(batch)-1]).Elem()
+		last = make([]any, len(table.PKs))
+
+		for i, f := range table.PKs {
+			last[i] = f.Value(strct).Interface()
+		}
+	}
+}
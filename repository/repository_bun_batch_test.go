@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_ForEachBatchByPk(t *testing.T) {
+	t.Parallel()
+
+	errStop := errors.New("stop")
+
+	tests := []struct {
+		name      string
+		mock      func(set *MockBunConnSet)
+		batchSize int
+		fn        func(batches *[][]TestComplexEnt) func([]TestComplexEnt) error
+		expected  func(t *testing.T, batches [][]TestComplexEnt, err error)
+	}{
+		{
+			name: "for each batch by pk",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_complex_entities"."first_id", "test_complex_entities"."second_id", "test_complex_entities"."complex_name", "test_complex_entities"."complex_description" FROM "test_complex_entities" ORDER BY "test_complex_entities"."first_id" ASC, "test_complex_entities"."second_id" ASC LIMIT 2`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"first_id", "second_id"}).AddRow(1, 1).AddRow(1, 2))
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_complex_entities"."first_id", "test_complex_entities"."second_id", "test_complex_entities"."complex_name", "test_complex_entities"."complex_description" FROM "test_complex_entities" WHERE (("test_complex_entities"."first_id", "test_complex_entities"."second_id") > (1, 2)) ORDER BY "test_complex_entities"."first_id" ASC, "test_complex_entities"."second_id" ASC LIMIT 2`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"first_id", "second_id"}).AddRow(2, 1))
+			},
+			batchSize: 2,
+			fn: func(batches *[][]TestComplexEnt) func([]TestComplexEnt) error {
+				return func(batch []TestComplexEnt) error {
+					*batches = append(*batches, batch)
+
+					return nil
+				}
+			},
+			expected: func(t *testing.T, batches [][]TestComplexEnt, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Len(t, batches, 2)
+				assert.Equal(t, 2, batches[1][0].FirstID)
+			},
+		},
+		{
+			name: "for each batch by pk stops on error",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^SELECT").
+					WillReturnRows(sqlmock.NewRows([]string{"first_id", "second_id"}).AddRow(1, 1).AddRow(1, 2))
+			},
+			batchSize: 2,
+			fn: func(batches *[][]TestComplexEnt) func([]TestComplexEnt) error {
+				return func(batch []TestComplexEnt) error {
+					return errStop
+				}
+			},
+			expected: func(t *testing.T, batches [][]TestComplexEnt, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, errStop)
+			},
+		},
+		{
+			name:      "for each batch by pk with invalid batch size",
+			mock:      func(conn *MockBunConnSet) {},
+			batchSize: 0,
+			fn: func(batches *[][]TestComplexEnt) func([]TestComplexEnt) error {
+				return nil
+			},
+			expected: func(t *testing.T, batches [][]TestComplexEnt, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrInvalidBatchSize)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestComplexEntRepository(subject.conn)
+			tt.mock(subject.conn)
+
+			var batches [][]TestComplexEnt
+
+			err := repo.ForEachBatchByPk(context.Background(), nil, nil, tt.batchSize, tt.fn(&batches))
+			tt.expected(t, batches, err)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestBunCrudRepository_FindInBatches(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestComplexEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_complex_entities"."complex_name", "test_complex_entities"."first_id", "test_complex_entities"."second_id" FROM "test_complex_entities" ORDER BY "test_complex_entities"."first_id" ASC, "test_complex_entities"."second_id" ASC LIMIT 2`) + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"complex_name", "first_id", "second_id"}).AddRow("a", 1, 1).AddRow("b", 1, 2))
+	subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_complex_entities"."complex_name", "test_complex_entities"."first_id", "test_complex_entities"."second_id" FROM "test_complex_entities" WHERE (("test_complex_entities"."first_id", "test_complex_entities"."second_id") > (1, 2)) ORDER BY "test_complex_entities"."first_id" ASC, "test_complex_entities"."second_id" ASC LIMIT 2`) + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"complex_name", "first_id", "second_id"}))
+
+	var names []string
+
+	err := repo.FindInBatches(context.Background(), nil, []string{"complex_name"}, nil, 2, func(batch []TestComplexEnt) error {
+		for _, e := range batch {
+			names = append(names, e.Name)
+		}
+
+		return nil
+	})
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+}
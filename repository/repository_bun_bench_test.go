@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aso779/crud-repository/sqltest"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/uptrace/bun"
+)
+
+type benchConnSet struct {
+	db *bun.DB
+}
+
+func (r benchConnSet) ReadPool() *bun.DB {
+	return r.db
+}
+
+func (r benchConnSet) WritePool() *bun.DB {
+	return r.db
+}
+
+func newBenchRepository() *TestSimpleEntBunRepo {
+	return NewTestSimpleEntRepository(benchConnSet{db: sqltest.OpenDB()})
+}
+
+func BenchmarkBunCrudRepository_FindPage(b *testing.B) {
+	ctx := context.Background()
+	repo := newBenchRepository()
+	spec := dataspec.NewAnd(
+		dataspec.NewEqual("name", "a"),
+		dataspec.NewIn("id", bun.In([]int{1, 2, 3})),
+	)
+	sort := NewSorter().WithSort("name", "desc")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = repo.FindPage(ctx, nil, []string{"id", "name"}, spec, NewPager(20, 3), sort)
+	}
+}
+
+func BenchmarkBunCrudRepository_CreateAll(b *testing.B) {
+	ctx := context.Background()
+	repo := newBenchRepository()
+	entities := make([]TestSimpleEnt, 100)
+
+	for i := range entities {
+		entities[i] = TestSimpleEnt{ID: i + 1, Name: "name"}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = repo.CreateAll(ctx, nil, entities, nil)
+	}
+}
+
+func BenchmarkBunCrudRepository_FindAllByPks(b *testing.B) {
+	ctx := context.Background()
+	repo := newBenchRepository()
+	pks := make([]metadata.PrimaryKey, 100)
+
+	for i := range pks {
+		pks[i] = metadata.PrimaryKey{"id": i + 1}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = repo.FindAllByPks(ctx, nil, nil, pks)
+	}
+}
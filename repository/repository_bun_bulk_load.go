@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"github.com/uptrace/bun/schema"
+)
+
+// BulkLoad inserts entities with COPY FROM STDIN in csv format, streaming rows instead of building
+// multi-row INSERTs, and returns the number of copied rows. Entities are normalized, validated and
+// checksummed as in CreateAll, generated values are not read back.
+//
+// Zero autoincrement, identity and default columns are left out of COPY to get their defaults, entities
+// setting different columns are copied by separate COPY statements in a transaction.
+//
+// COPY needs a dedicated pgdriver connection of the write pool, BulkLoad fails with ErrCopyNotSupported
+// for other drivers. It runs outside caller transactions and loads all rows or none.
+func (r BunCrudRepository[E, T]) BulkLoad(ctx context.Context, entities []E) (int, error) {
+	if len(entities) == 0 {
+		return 0, nil
+	}
+
+	if r.readOnly() {
+		return 0, fmt.Errorf("bulk load: %w", ErrReadOnlyEntity)
+	}
+
+	pool, err := r.rolePool(ctx, r.ConnSet.WritePool, TargetPrimary)
+	if err != nil {
+		return 0, fmt.Errorf("bulk load: %w", err)
+	}
+
+	db, ok := pool.(*bun.DB)
+	if !ok {
+		return 0, fmt.Errorf("bulk load: %w", ErrCopyNotSupported)
+	}
+
+	if err := r.normalizeAll(db, entities); err != nil {
+		return 0, fmt.Errorf("bulk load: %w", err)
+	}
+
+	if err := r.validate(ctx, entities...); err != nil {
+		return 0, fmt.Errorf("bulk load: %w", err)
+	}
+
+	if err := r.checkRelated(ctx, db, entities, nil); err != nil {
+		return 0, fmt.Errorf("bulk load: %w", err)
+	}
+
+	for i := range entities {
+		if err := r.setChecksum(db, &entities[i]); err != nil {
+			return 0, fmt.Errorf("bulk load: %w", err)
+		}
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("bulk load: %w", err)
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn any) error {
+		if _, ok := driverConn.(*pgdriver.Conn); !ok {
+			return ErrCopyNotSupported
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("bulk load: %w", err)
+	}
+
+	table := db.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem())
+	generated := generatedColumns(*new(E))
+
+	fields := slices.DeleteFunc(slices.Clone(table.Fields), func(f *schema.Field) bool {
+		return slices.Contains(generated, f.Name)
+	})
+
+	groups := copyGroups(fields, entities)
+	if len(groups) == 1 {
+		n, err := copyRows(ctx, db, conn, table, groups[0])
+		if err != nil {
+			return 0, fmt.Errorf("bulk load: %w", err)
+		}
+
+		return n, nil
+	}
+
+	// each column set needs its own COPY, a transaction keeps loading all rows or none
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("bulk load: %w", dbError(err))
+	}
+
+	var total int
+
+	for _, g := range groups {
+		n, err := copyRows(ctx, db, conn, table, g)
+		if err != nil {
+			_ = tx.Rollback()
+
+			return 0, fmt.Errorf("bulk load: %w", err)
+		}
+
+		total += n
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("bulk load: %w", dbError(err))
+	}
+
+	return total, nil
+}
+
+// copyGroup is a run of entities copied with the same columns.
+type copyGroup[E any] struct {
+	fields   []*schema.Field
+	entities []E
+}
+
+// copyGroups groups entities by the columns they set, in the order of their first entity. Zero autoincrement,
+// identity and default columns are left out, COPY stores NULL for them instead of the column default.
+func copyGroups[E any](fields []*schema.Field, entities []E) []copyGroup[E] {
+	var groups []copyGroup[E]
+
+	index := make(map[string]int)
+
+	for i := range entities {
+		strct := reflect.ValueOf(&entities[i]).Elem()
+		key := make([]byte, len(fields))
+
+		for j, f := range fields {
+			key[j] = '1'
+			if (f.AutoIncrement || f.Identity || f.SQLDefault != "") && f.HasZeroValue(strct) {
+				key[j] = '0'
+			}
+		}
+
+		k, ok := index[string(key)]
+		if !ok {
+			set := make([]*schema.Field, 0, len(fields))
+
+			for j, f := range fields {
+				if key[j] == '1' {
+					set = append(set, f)
+				}
+			}
+
+			k = len(groups)
+			index[string(key)] = k
+			groups = append(groups, copyGroup[E]{fields: set})
+		}
+
+		groups[k].entities = append(groups[k].entities, entities[i])
+	}
+
+	return groups
+}
+
+// copyRows copies the entities of a group on conn and returns the number of copied rows.
+func copyRows[E any](ctx context.Context, db *bun.DB, conn bun.Conn, table *schema.Table, g copyGroup[E]) (int, error) {
+	names := make([]string, len(g.fields))
+	for i, f := range g.fields {
+		names[i] = string(f.SQLName)
+	}
+
+	query := db.Formatter().FormatQuery(
+		"COPY ? ("+strings.Join(names, ", ")+") FROM STDIN WITH (FORMAT csv)",
+		table.SQLName,
+	)
+
+	rd, wr := io.Pipe()
+
+	go func() {
+		wr.CloseWithError(writeCopyRows(wr, schema.NewFormatter(db.Dialect()), g.fields, g.entities))
+	}()
+
+	res, err := pgdriver.CopyFrom(ctx, conn, rd, query)
+
+	// unblocks the writer when COPY failed before reading all rows
+	_ = rd.Close()
+
+	if err != nil {
+		return 0, dbError(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	return int(n), nil
+}
+
+// writeCopyRows writes the fields of entities as csv rows.
+func writeCopyRows[E any](w io.Writer, fmter schema.Formatter, fields []*schema.Field, entities []E) error {
+	var b []byte
+
+	for i := range entities {
+		strct := reflect.ValueOf(&entities[i]).Elem()
+		b = b[:0]
+
+		for j, f := range fields {
+			if j > 0 {
+				b = append(b, ',')
+			}
+
+			field, err := copyField(f.AppendValue(fmter, nil, strct))
+			if err != nil {
+				return fmt.Errorf("%s: %w", f.Name, err)
+			}
+
+			b = append(b, field...)
+		}
+
+		b = append(b, '\n')
+
+		if _, err := w.Write(b); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	return nil
+}
+
+// copyField converts a SQL literal to a csv field, NULL to an unquoted empty field.
+func copyField(literal []byte) ([]byte, error) {
+	switch {
+	case string(literal) == "NULL":
+		return nil, nil
+	case len(literal) >= 2 && literal[0] == '\'' && literal[len(literal)-1] == '\'':
+		value := bytes.ReplaceAll(literal[1:len(literal)-1], []byte("''"), []byte("'"))
+		if bytes.Count(value, []byte("'"))*2 != bytes.Count(literal[1:len(literal)-1], []byte("'")) {
+			return nil, fmt.Errorf("literal %s: %w", literal, ErrInvalidValue)
+		}
+
+		quoted := make([]byte, 0, len(value)+2)
+		quoted = append(quoted, '"')
+		quoted = append(quoted, bytes.ReplaceAll(value, []byte(`"`), []byte(`""`))...)
+
+		return append(quoted, '"'), nil
+	case bytes.HasPrefix(literal, []byte("?!(")):
+		// bun appends values failing to encode as ?!(error)
+		return nil, fmt.Errorf("%s: %w", literal[3:len(literal)-1], ErrInvalidValue)
+	case bytes.ContainsAny(literal, `'",`+"\n\r"):
+		return nil, fmt.Errorf("literal %s: %w", literal, ErrInvalidValue)
+	default:
+		return literal, nil
+	}
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/schema"
+)
+
+func TestCopyField(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		literal  string
+		expected string
+		err      error
+	}{
+		{name: "null", literal: "NULL", expected: ""},
+		{name: "number", literal: "42", expected: "42"},
+		{name: "boolean", literal: "TRUE", expected: "TRUE"},
+		{name: "string", literal: "'abc'", expected: `"abc"`},
+		{name: "empty string", literal: "''", expected: `""`},
+		{name: "escaped quotes", literal: `'it''s "x", y'`, expected: `"it's ""x"", y"`},
+		{name: "new line", literal: "'a\nb'", expected: "\"a\nb\""},
+		{name: "cast", literal: "'{}'::jsonb", err: ErrInvalidValue},
+		{name: "expression", literal: "'a' || 'b'", err: ErrInvalidValue},
+		{name: "encoding error", literal: "?!(json: unsupported type)", err: ErrInvalidValue},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := copyField([]byte(tt.literal))
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, string(res))
+		})
+	}
+}
+
+func TestWriteCopyRows(t *testing.T) {
+	t.Parallel()
+
+	dialect := pgdialect.New()
+	table := dialect.Tables().Get(reflect.TypeOf((*TestSimpleEnt)(nil)).Elem())
+
+	var buf bytes.Buffer
+
+	err := writeCopyRows(&buf, schema.NewFormatter(dialect), table.Fields, []TestSimpleEnt{
+		{ID: 1, Name: "plain"},
+		{ID: 2, Name: `quoted "name", with comma`},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1,\"plain\"\n2,\"quoted \"\"name\"\", with comma\"\n", buf.String())
+}
+
+func TestCopyGroups(t *testing.T) {
+	t.Parallel()
+
+	dialect := pgdialect.New()
+	table := dialect.Tables().Get(reflect.TypeOf((*TestInvoiceEnt)(nil)).Elem())
+
+	groups := copyGroups(table.Fields, []TestInvoiceEnt{
+		{Total: decimal.NewFromInt(1)},
+		{ID: 5, Total: decimal.NewFromInt(2)},
+		{Total: decimal.NewFromInt(3)},
+	})
+
+	assert.Len(t, groups, 2)
+	assert.Equal(t, []string{"total"}, fieldNames(groups[0].fields))
+	assert.Equal(t, []string{"id", "total"}, fieldNames(groups[1].fields))
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, writeCopyRows(&buf, schema.NewFormatter(dialect), groups[0].fields, groups[0].entities))
+	assert.Equal(t, "\"1\"\n\"3\"\n", buf.String())
+
+	buf.Reset()
+
+	assert.NoError(t, writeCopyRows(&buf, schema.NewFormatter(dialect), groups[1].fields, groups[1].entities))
+	assert.Equal(t, "5,\"2\"\n", buf.String())
+}
+
+func TestBunCrudRepository_BulkLoad(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestSimpleEntRepository(subject.conn)
+
+	n, err := repo.BulkLoad(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	n, err = repo.BulkLoad(context.Background(), []TestSimpleEnt{{ID: 1, Name: "a"}})
+	assert.ErrorIs(t, err, ErrCopyNotSupported)
+	assert.Equal(t, 0, n)
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Cancellation query interrupted by context cancellation or deadline.
+type Cancellation struct {
+	Query   string
+	Err     error
+	Elapsed time.Duration
+}
+
+// CancellationHook bun query hook observing queries interrupted by their context. Operations pass their
+// context down to the driver, which cancels the running query on the server, e.g. pgdriver sends a cancel
+// request. The hook must be added to the pools with AddQueryHook, it also records the interrupted query
+// for QueryTimeouts.
+type CancellationHook struct {
+	// OnCancel, when set, is called for every interrupted query.
+	OnCancel func(ctx context.Context, c Cancellation)
+}
+
+var _ bun.QueryHook = CancellationHook{}
+
+func (r CancellationHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (r CancellationHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	if !cancelled(ctx, event.Err) {
+		return
+	}
+
+	if rec := cancelRecorderFromContext(ctx); rec != nil {
+		rec.record(event.Query)
+	}
+
+	if r.OnCancel != nil {
+		r.OnCancel(ctx, Cancellation{
+			Query:   event.Query,
+			Err:     event.Err,
+			Elapsed: time.Since(event.StartTime),
+		})
+	}
+}
+
+// cancelled reports whether err comes from the cancellation of ctx, drivers could return their own
+// error for the cancelled query, e.g. sqlstate 57014, instead of the context one.
+func cancelled(ctx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil
+}
+
+// QueryTimeoutError query interrupted by the context deadline, matches ErrQueryTimeout and the driver error.
+type QueryTimeoutError struct {
+	// Query is the interrupted SQL, empty when CancellationHook is not added to the pools.
+	Query string
+	Err   error
+}
+
+func (e *QueryTimeoutError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrQueryTimeout, e.Err)
+}
+
+func (e *QueryTimeoutError) Unwrap() []error {
+	return []error{ErrQueryTimeout, e.Err}
+}
+
+type cancelRecorder struct {
+	query string
+
+	mu sync.Mutex
+}
+
+func (r *cancelRecorder) record(query string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.query = query
+}
+
+func (r *cancelRecorder) last() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.query
+}
+
+type cancelRecorderCtxKey struct{}
+
+func withCancelRecorder(ctx context.Context) (context.Context, *cancelRecorder) {
+	rec := &cancelRecorder{}
+
+	return context.WithValue(ctx, cancelRecorderCtxKey{}, rec), rec
+}
+
+func cancelRecorderFromContext(ctx context.Context) *cancelRecorder {
+	rec, _ := ctx.Value(cancelRecorderCtxKey{}).(*cancelRecorder)
+
+	return rec
+}
+
+// queryTimeout converts err of an operation run past the ctx deadline into QueryTimeoutError.
+func queryTimeout(ctx context.Context, rec *cancelRecorder, err error) error {
+	if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+
+	query := rec.last()
+	if query == "" && !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	return &QueryTimeoutError{Query: query, Err: err}
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestBunCrudRepository_Cancellation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(mock sqlmock.Sqlmock)
+		ctx      func() (context.Context, context.CancelFunc)
+		run      func(ctx context.Context, repo CrudRepository[TestSimpleEnt, bun.Tx]) error
+		timeout  bool
+		query    string
+		canceled int
+	}{
+		{
+			name: "deadline exceeded on read",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT").
+					WillDelayFor(time.Second).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+			run: func(ctx context.Context, repo CrudRepository[TestSimpleEnt, bun.Tx]) error {
+				_, err := repo.FindAll(ctx, nil, nil, dataspec.NewEqual("name", "a"))
+
+				return err //nolint:wrapcheck
+			},
+			timeout:  true,
+			query:    `SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" WHERE (test_simple_entities.name = 'a')`,
+			canceled: 1,
+		},
+		{
+			name: "deadline exceeded on write",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("^DELETE").
+					WillDelayFor(time.Second).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+			run: func(ctx context.Context, repo CrudRepository[TestSimpleEnt, bun.Tx]) error {
+				_, err := repo.Delete(ctx, nil, dataspec.NewEqual("name", "a"))
+
+				return err //nolint:wrapcheck
+			},
+			timeout:  true,
+			query:    `DELETE FROM "test_simple_entities" AS "test_simple_entities" WHERE (test_simple_entities.name = 'a')`,
+			canceled: 1,
+		},
+		{
+			name: "canceled is not a timeout",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT").
+					WillDelayFor(time.Second).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}))
+			},
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(10*time.Millisecond, cancel)
+
+				return ctx, cancel
+			},
+			run: func(ctx context.Context, repo CrudRepository[TestSimpleEnt, bun.Tx]) error {
+				_, err := repo.Count(ctx, nil, nil)
+
+				return err //nolint:wrapcheck
+			},
+			canceled: 1,
+		},
+		{
+			name: "other error is not a cancellation",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT").WillReturnError(errors.New("boom"))
+			},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Minute)
+			},
+			run: func(ctx context.Context, repo CrudRepository[TestSimpleEnt, bun.Tx]) error {
+				_, err := repo.Count(ctx, nil, nil)
+
+				return err //nolint:wrapcheck
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqldb, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+
+			var (
+				mu       sync.Mutex
+				canceled []Cancellation
+			)
+
+			db := bun.NewDB(sqldb, pgdialect.New())
+			db.AddQueryHook(CancellationHook{
+				OnCancel: func(_ context.Context, c Cancellation) {
+					mu.Lock()
+					defer mu.Unlock()
+
+					canceled = append(canceled, c)
+				},
+			})
+
+			repo := NewQueryTimeouts[TestSimpleEnt, bun.Tx](NewTestSimpleEntRepository(hookedConnSet{db: db}))
+			tt.mock(mock)
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			start := time.Now()
+			err = tt.run(ctx, repo)
+
+			assert.Error(t, err)
+			assert.Less(t, time.Since(start), time.Second, "query must be canceled with the context")
+			assert.Equal(t, tt.timeout, errors.Is(err, ErrQueryTimeout))
+			assert.Len(t, canceled, tt.canceled)
+
+			var timeoutErr *QueryTimeoutError
+			if tt.timeout && assert.ErrorAs(t, err, &timeoutErr) {
+				assert.Equal(t, tt.query, timeoutErr.Query)
+			}
+
+			if tt.canceled > 0 {
+				assert.NotEmpty(t, canceled[0].Query)
+				assert.Error(t, canceled[0].Err)
+				assert.Positive(t, canceled[0].Elapsed)
+			}
+		})
+	}
+}
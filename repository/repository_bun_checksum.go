@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// Checksummed entity keeps an md5 hash of the listed persistence columns in ChecksumColumn.
+// The repository recalculates it on writes of entities, e.g. CreateOne, CreateAll, UpdateOne and UpdateAll.
+// Writes of column values, UpdateBySpec, UpdateEachByPk, UpdateOrCreate and Increment, fail with
+// ErrChecksumNotSupported for the checksum columns.
+type Checksummed interface {
+	ChecksumColumn() string
+	ChecksumColumns() []string
+}
+
+// VerifyChecksums returns primary keys of rows matching spec whose stored checksum differs from their content.
+// Rows are hashed as stored, Location and AfterScan don't apply.
+func (r BunCrudRepository[E, T]) VerifyChecksums(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) ([]metadata.PrimaryKey, error) {
+	if _, ok := any(*new(E)).(Checksummed); !ok {
+		return nil, fmt.Errorf("verify checksums: %w", ErrChecksumNotSupported)
+	}
+
+	if err := r.guardFullTable(ctx, spec); err != nil {
+		return nil, fmt.Errorf("verify checksums: %w", err)
+	}
+
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("verify checksums: %w", err)
+	}
+
+	var entities []E
+
+	query := tx.NewSelect().Model(&entities).Column("*")
+	r.applySpec(query, spec)
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("verify checksums: %w", dbError(err))
+	}
+
+	var mismatched []metadata.PrimaryKey
+
+	for i := range entities {
+		stored, actual, err := r.checksum(tx, &entities[i])
+		if err != nil {
+			return nil, fmt.Errorf("verify checksums: %w", err)
+		}
+
+		if stored != actual {
+			mismatched = append(mismatched, entities[i].PrimaryKey())
+		}
+	}
+
+	return mismatched, nil
+}
+
+// setChecksum stores the calculated checksum into the entity, it's a no-op for entities without checksum.
+func (r BunCrudRepository[E, T]) setChecksum(tx bun.IDB, entity *E) error {
+	if _, ok := any(*entity).(Checksummed); !ok {
+		return nil
+	}
+
+	_, actual, err := r.checksum(tx, entity)
+	if err != nil {
+		return err
+	}
+
+	column := any(*entity).(Checksummed).ChecksumColumn() //nolint:forcetypeassert
+	field := tx.Dialect().Tables().Get(reflect.TypeOf(entity).Elem()).LookupField(column)
+	field.Value(reflect.ValueOf(entity).Elem()).SetString(actual)
+
+	return nil
+}
+
+// checkChecksumValues rejects column values of Checksummed entities changing the checksum or its columns
+// with ErrChecksumNotSupported, statements setting values can't recalculate it from the rest of the row.
+// Keys are presenter or persistence names.
+func (r BunCrudRepository[E, T]) checkChecksumValues(values map[string]any) error {
+	cs, ok := any(*new(E)).(Checksummed)
+	if !ok {
+		return nil
+	}
+
+	for _, k := range sortedKeys(values) {
+		column := r.persistenceName(k)
+		if column == cs.ChecksumColumn() || slices.Contains(cs.ChecksumColumns(), column) {
+			return fmt.Errorf("checksum column %s: %w", column, ErrChecksumNotSupported)
+		}
+	}
+
+	return nil
+}
+
+// checksum returns stored and calculated checksums.
+func (r BunCrudRepository[E, T]) checksum(tx bun.IDB, entity *E) (string, string, error) {
+	cs := any(*entity).(Checksummed) //nolint:forcetypeassert
+	table := tx.Dialect().Tables().Get(reflect.TypeOf(entity).Elem())
+	strct := reflect.ValueOf(entity).Elem()
+	fmter := schema.NewFormatter(tx.Dialect())
+
+	target := table.LookupField(cs.ChecksumColumn())
+	if target == nil || target.IndirectType.Kind() != reflect.String {
+		return "", "", fmt.Errorf("checksum column %s: %w", cs.ChecksumColumn(), ErrChecksumNotSupported)
+	}
+
+	var b []byte
+
+	for i, column := range cs.ChecksumColumns() {
+		field := table.LookupField(column)
+		if field == nil {
+			return "", "", fmt.Errorf("checksum column %s: %w", column, ErrUnknownColumn)
+		}
+
+		if i > 0 {
+			b = append(b, '|')
+		}
+
+		b = field.AppendValue(fmter, b, strct)
+	}
+
+	sum := md5.Sum(b) //nolint:gosec
+
+	return target.Value(strct).String(), hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/bun-pg-connector"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type TestChecksumEnt struct {
+	bun.BaseModel `bun:"table:test_checksum_entities,alias:test_checksum_entities"`
+
+	ID    int    `bun:"id,pk" json:"id"`
+	Name  string `bun:"name" json:"name"`
+	Email string `bun:"email" json:"email"`
+	Hash  string `bun:"hash" json:"hash"`
+}
+
+func (r TestChecksumEnt) EntityName() string {
+	return "TestChecksumEnt"
+}
+
+func (r TestChecksumEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+func (r TestChecksumEnt) ChecksumColumn() string {
+	return "hash"
+}
+
+func (r TestChecksumEnt) ChecksumColumns() []string {
+	return []string{"name", "email"}
+}
+
+type TestChecksumEntMeta struct {
+	TestChecksumEnt
+}
+
+func (r TestChecksumEntMeta) Entity() metadata.Entity { return r.TestChecksumEnt }
+
+func (r TestChecksumEntMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func NewTestChecksumEntRepository(
+	connSet bunpgconnector.BunConnSet,
+) BunCrudRepository[TestChecksumEnt, bun.Tx] {
+	return BunCrudRepository[TestChecksumEnt, bun.Tx]{
+		ConnSet: connSet,
+		Meta:    meta.Parser(TestChecksumEntMeta{}),
+	}
+}
+
+const testChecksum = "43c20b280e5d20bddf241a0fb5e3ea4b"
+
+func TestBunCrudRepository_CreateOneWithChecksum(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestChecksumEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectQuery("^INSERT INTO \"test_checksum_entities\" \\(\"id\", \"name\", \"email\", \"hash\"\\) VALUES \\(1, 'John', 'j@x.io', '" + testChecksum + "'\\) RETURNING \\*$").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	res, err := repo.CreateOne(context.Background(), nil, &TestChecksumEnt{ID: 1, Name: "John", Email: "j@x.io"}, []string{"*"})
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, testChecksum, res.Hash)
+}
+
+func TestBunCrudRepository_UpdateOneWithChecksum(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestChecksumEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectQuery("^UPDATE \"test_checksum_entities\" AS \"test_checksum_entities\" SET \"name\" = 'John', \"hash\" = '" + testChecksum + "' WHERE \\(\"test_checksum_entities\"\\.\"id\" = 1\\) RETURNING id$").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	_, err := repo.UpdateOne(
+		context.Background(),
+		nil,
+		&TestChecksumEnt{ID: 1, Name: "John", Email: "j@x.io"},
+		[]string{"name"},
+		[]string{"id"},
+	)
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+}
+
+func TestBunCrudRepository_VerifyChecksums(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		prepare func(repo *BunCrudRepository[TestChecksumEnt, bun.Tx])
+	}{
+		{name: "verify checksums"},
+		{
+			name: "verify checksums with after scan",
+			prepare: func(repo *BunCrudRepository[TestChecksumEnt, bun.Tx]) {
+				repo.AfterScan = []AfterScanFunc[TestChecksumEnt]{
+					func(_ context.Context, entity *TestChecksumEnt) error {
+						entity.Email = "masked"
+
+						return nil
+					},
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestChecksumEntRepository(subject.conn)
+			if tt.prepare != nil {
+				tt.prepare(&repo)
+			}
+
+			subject.conn.Mock.ExpectQuery("^SELECT \\* FROM \"test_checksum_entities\"$").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "hash"}).
+					AddRow(1, "John", "j@x.io", testChecksum).
+					AddRow(2, "John", "tampered@x.io", testChecksum))
+
+			res, err := repo.VerifyChecksums(context.Background(), nil, nil)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.NoError(t, err)
+			assert.Equal(t, []metadata.PrimaryKey{{"id": 2}}, res)
+		})
+	}
+}
+
+func TestBunCrudRepository_VerifyChecksumsNotSupported(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestSimpleEntRepository(subject.conn)
+
+	_, err := repo.VerifyChecksums(context.Background(), nil, nil)
+
+	assert.ErrorIs(t, err, ErrChecksumNotSupported)
+}
+
+func TestBunCrudRepository_ChecksumColumnValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context, repo BunCrudRepository[TestChecksumEnt, bun.Tx]) error
+	}{
+		{
+			name: "update by spec",
+			call: func(ctx context.Context, repo BunCrudRepository[TestChecksumEnt, bun.Tx]) error {
+				_, err := repo.UpdateBySpec(ctx, nil, map[string]any{"name": "Jane"}, dataspec.NewEqual("id", 1))
+
+				return err //nolint:wrapcheck
+			},
+		},
+		{
+			name: "update or create",
+			call: func(ctx context.Context, repo BunCrudRepository[TestChecksumEnt, bun.Tx]) error {
+				_, err := repo.UpdateOrCreate(ctx, nil, dataspec.NewEqual("id", 1), map[string]any{"email": "j@x.io"}, &TestChecksumEnt{ID: 1}, nil)
+
+				return err //nolint:wrapcheck
+			},
+		},
+		{
+			name: "update checksum column",
+			call: func(ctx context.Context, repo BunCrudRepository[TestChecksumEnt, bun.Tx]) error {
+				_, err := repo.UpdateBySpec(ctx, nil, map[string]any{"hash": testChecksum}, dataspec.NewEqual("id", 1))
+
+				return err //nolint:wrapcheck
+			},
+		},
+		{
+			name: "increment",
+			call: func(ctx context.Context, repo BunCrudRepository[TestChecksumEnt, bun.Tx]) error {
+				_, err := repo.Increment(ctx, nil, metadata.PrimaryKey{"id": 1}, "name", 1)
+
+				return err //nolint:wrapcheck
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestChecksumEntRepository(subject.conn)
+
+			err := tt.call(context.Background(), repo)
+
+			assert.ErrorIs(t, err, ErrChecksumNotSupported)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// ClaimBatch locks up to limit rows matching spec with FOR UPDATE SKIP LOCKED and returns them, oldest primary
+// keys first. Rows claimed by other transactions are skipped, so concurrent consumers of a queue table get
+// disjoint batches. The rows stay claimed until tx ends, process and update or delete them in it.
+func (r BunCrudRepository[E, T]) ClaimBatch(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	limit int,
+) ([]E, error) {
+	var entities = make([]E, 0)
+
+	if limit <= 0 {
+		return entities, fmt.Errorf("claim batch: limit %d: %w", limit, ErrInvalidValue)
+	}
+
+	if tx == nil {
+		return entities, fmt.Errorf("claim batch: %w", ErrTxRequired)
+	}
+
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return entities, fmt.Errorf("claim batch: %w", err)
+	}
+
+	query := tx.
+		NewSelect().
+		Model(&entities)
+
+	r.applySpec(query, spec)
+
+	table := tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem())
+	for _, f := range table.PKs {
+		query.OrderExpr("?TableAlias.? ASC", bun.Ident(f.Name))
+	}
+
+	(&rowLock{strength: LockForUpdate, wait: LockSkipLocked}).apply(query)
+
+	if err := query.Limit(limit).Scan(ctx); err != nil {
+		return entities, fmt.Errorf("claim batch: %w", dbError(err))
+	}
+
+	r.localize(entities)
+
+	if err := r.afterScan(ctx, entities); err != nil {
+		return entities, fmt.Errorf("claim batch: %w", err)
+	}
+
+	return entities, nil
+}
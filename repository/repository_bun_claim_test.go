@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+func TestBunCrudRepository_ClaimBatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(conn *MockBunConnSet)
+		limit    int
+		noTx     bool
+		expected []TestSimpleEnt
+		err      error
+	}{
+		{
+			name: "claim batch",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" WHERE (test_simple_entities.name = 'pending') ORDER BY "test_simple_entities"."id" ASC LIMIT 2 FOR UPDATE OF "test_simple_entities" SKIP LOCKED`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "pending").AddRow(3, "pending"))
+			},
+			limit:    2,
+			expected: []TestSimpleEnt{{ID: 1, Name: "pending"}, {ID: 3, Name: "pending"}},
+		},
+		{
+			name:     "invalid limit",
+			mock:     func(conn *MockBunConnSet) {},
+			limit:    0,
+			expected: []TestSimpleEnt{},
+			err:      ErrInvalidValue,
+		},
+		{
+			name:     "without tx",
+			mock:     func(conn *MockBunConnSet) {},
+			limit:    2,
+			noTx:     true,
+			expected: []TestSimpleEnt{},
+			err:      ErrTxRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+
+			subject.conn.Mock.ExpectBegin()
+			tt.mock(subject.conn)
+			subject.conn.Mock.ExpectRollback()
+
+			ctx := context.Background()
+
+			tx, err := subject.conn.WritePool().BeginTx(ctx, nil)
+			assert.NoError(t, err)
+
+			var db bun.IDB = tx
+			if tt.noTx {
+				db = nil
+			}
+
+			res, err := repo.ClaimBatch(ctx, db, dataspec.NewEqual("name", "pending"), tt.limit)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, res)
+
+			assert.NoError(t, tx.Rollback())
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// ConnectionArgs Relay connection arguments. First rows After a cursor page forward, Last rows Before
+// a cursor page backward, exactly one of First and Last is set.
+type ConnectionArgs struct {
+	First  int
+	After  string
+	Last   int
+	Before string
+	// WithTotal counts all rows matching spec into TotalCount.
+	WithTotal bool
+}
+
+// Edge connection row with its opaque cursor.
+type Edge[E any] struct {
+	Node   E
+	Cursor string
+}
+
+// PageInfo Relay page info of a connection.
+type PageInfo struct {
+	HasNextPage     bool
+	HasPreviousPage bool
+	StartCursor     string
+	EndCursor       string
+}
+
+// Connection Relay connection of FindConnection, TotalCount is set with ConnectionArgs.WithTotal.
+type Connection[E any] struct {
+	Edges      []Edge[E]
+	PageInfo   PageInfo
+	TotalCount int
+}
+
+// FindConnection finds a Relay connection of rows matching spec in sort order, for GraphQL backends.
+// Pages are keyset pages, see FindPageAfter, edge cursors are Cursor tokens. Rows are looked up in one
+// direction only, so HasPreviousPage of forward pages and HasNextPage of backward pages are set when
+// paging from a cursor, as rows were there when it was issued.
+func (r BunCrudRepository[E, T]) FindConnection(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+	sort dataset.Sorter,
+	args ConnectionArgs,
+) (Connection[E], error) {
+	conn := Connection[E]{Edges: make([]Edge[E], 0)}
+
+	backward := args.Last > 0
+	if backward == (args.First > 0) || (backward && args.After != "") || (!backward && args.Before != "") {
+		return conn, fmt.Errorf("find connection: %+v: %w", args, ErrInvalidValue)
+	}
+
+	size, token := args.First, args.After
+	if backward {
+		size, token = args.Last, args.Before
+	}
+
+	cursor, err := DecodeCursor(token)
+	if err != nil {
+		return conn, fmt.Errorf("find connection: %w", err)
+	}
+
+	page, err := r.seekPage(ctx, tx, "find connection", columns, spec, cursor, size, sort, backward)
+	if err != nil {
+		return conn, fmt.Errorf("find connection: %w", err)
+	}
+
+	for i, item := range page.items {
+		token, err := page.cursors[i].Encode()
+		if err != nil {
+			return conn, fmt.Errorf("find connection: %w", err)
+		}
+
+		conn.Edges = append(conn.Edges, Edge[E]{Node: item, Cursor: token})
+	}
+
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.StartCursor = conn.Edges[0].Cursor
+		conn.PageInfo.EndCursor = conn.Edges[len(conn.Edges)-1].Cursor
+	}
+
+	if backward {
+		conn.PageInfo.HasPreviousPage, conn.PageInfo.HasNextPage = page.more, !cursor.IsEmpty()
+	} else {
+		conn.PageInfo.HasNextPage, conn.PageInfo.HasPreviousPage = page.more, !cursor.IsEmpty()
+	}
+
+	if args.WithTotal {
+		if conn.TotalCount, err = r.countTotal(ctx, tx, spec); err != nil {
+			return conn, fmt.Errorf("find connection: %w", err)
+		}
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_FindConnection(t *testing.T) {
+	t.Parallel()
+
+	token := func(values ...any) string {
+		desc := make([]bool, len(values))
+		for i := range desc {
+			desc[i] = true
+		}
+
+		res, err := Cursor{Values: values, Desc: desc}.Encode()
+		assert.NoError(t, err)
+
+		return res
+	}
+
+	tests := []struct {
+		name     string
+		mock     func(conn *MockBunConnSet)
+		args     ConnectionArgs
+		expected Connection[TestSimpleEnt]
+		err      error
+	}{
+		{
+			name: "first page with total",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" WHERE (test_simple_entities.name != 'x') ORDER BY "test_simple_entities"."name" DESC, "test_simple_entities"."id" DESC LIMIT 3`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "c").AddRow(2, "b").AddRow(1, "a"))
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT count(*) FROM "test_simple_entities" WHERE (test_simple_entities.name != 'x')`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			},
+			args: ConnectionArgs{First: 2, WithTotal: true},
+			expected: Connection[TestSimpleEnt]{
+				Edges: []Edge[TestSimpleEnt]{
+					{Node: TestSimpleEnt{ID: 3, Name: "c"}, Cursor: token("c", 3)},
+					{Node: TestSimpleEnt{ID: 2, Name: "b"}, Cursor: token("b", 2)},
+				},
+				PageInfo: PageInfo{
+					HasNextPage: true,
+					StartCursor: token("c", 3),
+					EndCursor:   token("b", 2),
+				},
+				TotalCount: 3,
+			},
+		},
+		{
+			name: "next page",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" WHERE (test_simple_entities.name != 'x') AND (("test_simple_entities"."name", "test_simple_entities"."id") < ('b', 2)) ORDER BY "test_simple_entities"."name" DESC, "test_simple_entities"."id" DESC LIMIT 3`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+			},
+			args: ConnectionArgs{First: 2, After: token("b", 2)},
+			expected: Connection[TestSimpleEnt]{
+				Edges: []Edge[TestSimpleEnt]{
+					{Node: TestSimpleEnt{ID: 1, Name: "a"}, Cursor: token("a", 1)},
+				},
+				PageInfo: PageInfo{
+					HasPreviousPage: true,
+					StartCursor:     token("a", 1),
+					EndCursor:       token("a", 1),
+				},
+			},
+		},
+		{
+			name: "last before",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" WHERE (test_simple_entities.name != 'x') AND (("test_simple_entities"."name", "test_simple_entities"."id") > ('a', 1)) ORDER BY "test_simple_entities"."name" ASC, "test_simple_entities"."id" ASC LIMIT 2`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "b").AddRow(3, "c"))
+			},
+			args: ConnectionArgs{Last: 1, Before: token("a", 1)},
+			expected: Connection[TestSimpleEnt]{
+				Edges: []Edge[TestSimpleEnt]{
+					{Node: TestSimpleEnt{ID: 2, Name: "b"}, Cursor: token("b", 2)},
+				},
+				PageInfo: PageInfo{
+					HasNextPage:     true,
+					HasPreviousPage: true,
+					StartCursor:     token("b", 2),
+					EndCursor:       token("b", 2),
+				},
+			},
+		},
+		{
+			name:     "first and last",
+			mock:     func(conn *MockBunConnSet) {},
+			args:     ConnectionArgs{First: 2, Last: 2},
+			expected: Connection[TestSimpleEnt]{Edges: []Edge[TestSimpleEnt]{}},
+			err:      ErrInvalidValue,
+		},
+		{
+			name:     "first before",
+			mock:     func(conn *MockBunConnSet) {},
+			args:     ConnectionArgs{First: 2, Before: token("a", 1)},
+			expected: Connection[TestSimpleEnt]{Edges: []Edge[TestSimpleEnt]{}},
+			err:      ErrInvalidValue,
+		},
+		{
+			name:     "malformed cursor",
+			mock:     func(conn *MockBunConnSet) {},
+			args:     ConnectionArgs{First: 2, After: "%%%"},
+			expected: Connection[TestSimpleEnt]{Edges: []Edge[TestSimpleEnt]{}},
+			err:      ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+			tt.mock(subject.conn)
+
+			res, err := repo.FindConnection(
+				context.Background(), nil, nil, dataspec.NewNotEqual("name", "x"), NewSorter().WithSort("name", "desc"), tt.args,
+			)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, res)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
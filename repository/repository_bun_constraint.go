@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ConstraintColumns entity maps constraint names to the persistence columns they cover, e.g.
+// {"users_lower_email_idx": {"email"}}, for constraints ViolatedFields can't resolve by Postgres default names.
+type ConstraintColumns interface {
+	ConstraintColumns() map[string][]string
+}
+
+// Suffixes of Postgres default constraint names, <table>_<columns>_<suffix>.
+var constraintSuffixes = []string{"_key", "_fkey", "_check", "_excl", "_not_null"}
+
+// ViolatedFields returns presenter names of the fields of a constraint violation err of the repository, e.g.
+// ["email"] for a unique violation of users_email_key, so APIs can report which value conflicts.
+// The constraint is resolved by ConstraintColumns of the entity, then by Postgres default constraint names,
+// and the column of not null violations is used as is. Nil is returned when err isn't a violation or
+// the constraint is unknown.
+func (r BunCrudRepository[E, T]) ViolatedFields(err error) []string {
+	var dbErr *DBError
+	if !errors.As(err, &dbErr) || !isRowViolation(dbErr.Err) {
+		return nil
+	}
+
+	columns := r.constraintColumns(dbErr.Constraint)
+	if columns == nil && dbErr.Column != "" {
+		columns = []string{dbErr.Column}
+	}
+
+	mapping := r.Meta.PersistencePresenterMapping()
+
+	var fields []string
+
+	for _, column := range columns {
+		if field, ok := mapping[column]; ok {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// constraintColumns returns persistence columns of the constraint of the entity table, nil when unknown.
+func (r BunCrudRepository[E, T]) constraintColumns(constraint string) []string {
+	if constraint == "" {
+		return nil
+	}
+
+	if cc, ok := any(*new(E)).(ConstraintColumns); ok {
+		if columns, ok := cc.ConstraintColumns()[constraint]; ok {
+			return columns
+		}
+	}
+
+	// default constraint names don't include the schema
+	table := r.Meta.PersistenceName()
+	if i := strings.LastIndexByte(table, '.'); i >= 0 {
+		table = table[i+1:]
+	}
+
+	if constraint == table+"_pkey" {
+		keys := PrimaryKeyOrder((*new(E)).PrimaryKey())
+		for i, k := range keys {
+			keys[i] = r.persistenceName(k)
+		}
+
+		return keys
+	}
+
+	name, ok := strings.CutPrefix(constraint, table+"_")
+	if !ok {
+		return nil
+	}
+
+	for _, suffix := range constraintSuffixes {
+		if joined, ok := strings.CutSuffix(name, suffix); ok {
+			if columns := r.splitColumns(joined); columns != nil {
+				return columns
+			}
+		}
+	}
+
+	return nil
+}
+
+// splitColumns splits underscore joined persistence columns of a default constraint name, longest known
+// columns first, nil when the name has unknown parts.
+func (r BunCrudRepository[E, T]) splitColumns(joined string) []string {
+	known := sortedKeys(r.Meta.PersistencePresenterMapping())
+	sort.SliceStable(known, func(i, j int) bool { return len(known[i]) > len(known[j]) })
+
+	var columns []string
+
+	for joined != "" {
+		i := slices.IndexFunc(known, func(column string) bool {
+			rest, ok := strings.CutPrefix(joined, column)
+
+			return ok && (rest == "" || rest[0] == '_')
+		})
+		if i < 0 {
+			return nil
+		}
+
+		columns = append(columns, known[i])
+		joined = strings.TrimPrefix(joined[len(known[i]):], "_")
+	}
+
+	return columns
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aso779/crud-repository/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type testPgFieldError struct {
+	fields map[byte]string
+}
+
+func (r testPgFieldError) Error() string {
+	return "ERROR #" + r.fields['C']
+}
+
+func (r testPgFieldError) Field(k byte) string {
+	return r.fields[k]
+}
+
+func TestBunCrudRepository_ViolatedFields(t *testing.T) {
+	t.Parallel()
+
+	violation := func(code, constraint, column string) error {
+		return fmt.Errorf("create one: %w", dbError(testPgFieldError{fields: map[byte]string{
+			'C': code,
+			'n': constraint,
+			'c': column,
+		}}))
+	}
+
+	subject := crudRepositoryShortTestSetUp(t)
+	complexRepo := NewTestComplexEntRepository(subject.conn)
+	accountRepo := BunCrudRepository[TestAccountEnt, bun.Tx]{
+		ConnSet: subject.conn,
+		Meta:    meta.Parser(TestAccountEntMeta{}),
+	}
+
+	tests := []struct {
+		name     string
+		fields   func(err error) []string
+		err      error
+		expected []string
+	}{
+		{
+			name:     "primary key",
+			fields:   complexRepo.ViolatedFields,
+			err:      violation("23505", "test_complex_entities_pkey", ""),
+			expected: []string{"firstId", "secondId"},
+		},
+		{
+			name:     "default unique name",
+			fields:   complexRepo.ViolatedFields,
+			err:      violation("23505", "test_complex_entities_complex_name_complex_description_key", ""),
+			expected: []string{"complexName", "complexDescription"},
+		},
+		{
+			name:     "default foreign key name",
+			fields:   complexRepo.ViolatedFields,
+			err:      violation("23503", "test_complex_entities_second_id_fkey", ""),
+			expected: []string{"secondId"},
+		},
+		{
+			name:     "declared constraint",
+			fields:   accountRepo.ViolatedFields,
+			err:      violation("23505", "test_accounts_lower_email_idx", ""),
+			expected: []string{"email"},
+		},
+		{
+			name:     "not null column",
+			fields:   accountRepo.ViolatedFields,
+			err:      violation("23502", "", "login"),
+			expected: []string{"login"},
+		},
+		{
+			name:   "unknown constraint",
+			fields: complexRepo.ViolatedFields,
+			err:    violation("23505", "complex_uq", ""),
+		},
+		{
+			name:   "unknown column in name",
+			fields: complexRepo.ViolatedFields,
+			err:    violation("23505", "test_complex_entities_other_key", ""),
+		},
+		{
+			name:   "not a violation",
+			fields: complexRepo.ViolatedFields,
+			err:    violation("40001", "", ""),
+		},
+		{
+			name:   "other error",
+			fields: complexRepo.ViolatedFields,
+			err:    errors.New("connection lost"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, tt.fields(tt.err))
+		})
+	}
+}
+
+func TestDbErrorConstraint(t *testing.T) {
+	t.Parallel()
+
+	err := dbError(testPgFieldError{fields: map[byte]string{'C': "23505", 'n': "users_email_key", 'c': ""}})
+
+	var dbErr *DBError
+
+	assert.ErrorAs(t, err, &dbErr)
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Equal(t, "users_email_key", dbErr.Constraint)
+	assert.Empty(t, dbErr.Column)
+}
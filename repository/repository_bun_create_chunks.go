@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/uptrace/bun"
+)
+
+// maxBindParams is the Postgres limit of bind parameters in a statement.
+const maxBindParams = 65535
+
+// createChunkSize returns the rows per INSERT of CreateAll, CreateChunkSize or the most rows
+// fitting maxBindParams.
+func (r BunCrudRepository[E, T]) createChunkSize(tx bun.IDB) int {
+	if r.CreateChunkSize > 0 {
+		return r.CreateChunkSize
+	}
+
+	fields := len(tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem()).Fields)
+
+	return maxBindParams / max(fields, 1)
+}
+
+// insertChunks inserts entities in chunks of createChunkSize rows, all chunks in one transaction,
+// a savepoint when tx is a transaction already. Entities are updated in place with returned values.
+func (r BunCrudRepository[E, T]) insertChunks(
+	ctx context.Context,
+	tx bun.IDB,
+	entities []E,
+	columns []string,
+) error {
+	size := r.createChunkSize(tx)
+	if len(entities) <= size {
+		return r.insertAll(ctx, tx, entities, columns)
+	}
+
+	return tx.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error { //nolint:wrapcheck
+		for from := 0; from < len(entities); from += size {
+			to := min(from+size, len(entities))
+
+			if err := r.insertAll(ctx, tx, entities[from:to:to], columns); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_CreateAllChunks(t *testing.T) {
+	t.Parallel()
+
+	errInsert := errors.New("insert failed")
+
+	tests := []struct {
+		name      string
+		chunkSize int
+		mock      func(conn *MockBunConnSet)
+		expected  []TestSimpleEnt
+		err       error
+	}{
+		{
+			name:      "chunks in one transaction",
+			chunkSize: 2,
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectBegin()
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`INSERT INTO "test_simple_entities" ("id", "name") VALUES (1, 'a'), (2, 'b')  RETURNING id,name`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "A").AddRow(2, "B"))
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`INSERT INTO "test_simple_entities" ("id", "name") VALUES (3, 'c')  RETURNING id,name`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "C"))
+				conn.Mock.ExpectCommit()
+			},
+			expected: []TestSimpleEnt{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}, {ID: 3, Name: "C"}},
+		},
+		{
+			name:      "failed chunk rolls back",
+			chunkSize: 2,
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectBegin()
+				conn.Mock.ExpectQuery("^INSERT").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "A").AddRow(2, "B"))
+				conn.Mock.ExpectQuery("^INSERT").WillReturnError(errInsert)
+				conn.Mock.ExpectRollback()
+			},
+			expected: []TestSimpleEnt{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}, {ID: 3, Name: "c"}},
+			err:      errInsert,
+		},
+		{
+			name: "fits parameter limit",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`INSERT INTO "test_simple_entities" ("id", "name") VALUES (1, 'a'), (2, 'b'), (3, 'c')  RETURNING id,name`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b").AddRow(3, "c"))
+			},
+			expected: []TestSimpleEnt{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+			repo.CreateChunkSize = tt.chunkSize
+
+			tt.mock(subject.conn)
+
+			res, err := repo.CreateAll(
+				context.Background(),
+				nil,
+				[]TestSimpleEnt{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}},
+				[]string{"id", "name"},
+			)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
+func TestBunCrudRepository_CreateChunkSize(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestSimpleEntRepository(subject.conn)
+
+	assert.Equal(t, maxBindParams/2, repo.createChunkSize(subject.conn.WritePool()))
+
+	repo.CreateChunkSize = 100
+	assert.Equal(t, 100, repo.createChunkSize(subject.conn.WritePool()))
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// cursorToken JSON form of a Cursor token.
+type cursorToken struct {
+	Values []any  `json:"v"`
+	Desc   []bool `json:"d"`
+}
+
+// Encode returns the cursor as an opaque URL safe token with its values and directions, e.g. for
+// next_cursor fields of HTTP APIs, an empty string for an empty cursor. Column names aren't encoded.
+// Values are encoded as JSON, times as RFC 3339 strings.
+func (c Cursor) Encode() (string, error) {
+	if c.IsEmpty() {
+		return "", nil
+	}
+
+	data, err := json.Marshal(cursorToken{Values: c.Values, Desc: c.Desc})
+	if err != nil {
+		return "", fmt.Errorf("encode cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor returns the cursor of an Encode token, an empty cursor for an empty token. Integer values
+// are decoded as int64, other numbers as float64, malformed tokens fail with ErrInvalidValue.
+func DecodeCursor(token string) (Cursor, error) {
+	if token == "" {
+		return Cursor{}, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("decode cursor: %w", ErrInvalidValue)
+	}
+
+	var ct cursorToken
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := dec.Decode(&ct); err != nil || len(ct.Values) == 0 || len(ct.Desc) != len(ct.Values) {
+		return Cursor{}, fmt.Errorf("decode cursor: %w", ErrInvalidValue)
+	}
+
+	for i, v := range ct.Values {
+		n, ok := v.(json.Number)
+		if !ok {
+			continue
+		}
+
+		if ct.Values[i], err = n.Int64(); err != nil {
+			if ct.Values[i], err = n.Float64(); err != nil {
+				return Cursor{}, fmt.Errorf("decode cursor: %w", ErrInvalidValue)
+			}
+		}
+	}
+
+	return Cursor{Values: ct.Values, Desc: ct.Desc}, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_Encode(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		cursor   Cursor
+		expected Cursor
+	}{
+		{
+			name:     "integer and string",
+			cursor:   Cursor{Values: []any{"b", 2}, Desc: []bool{true, true}},
+			expected: Cursor{Values: []any{"b", int64(2)}, Desc: []bool{true, true}},
+		},
+		{
+			name:     "float and time",
+			cursor:   Cursor{Values: []any{10.5, at, int64(9007199254740993)}, Desc: []bool{false, true, true}},
+			expected: Cursor{Values: []any{10.5, "2024-05-01T10:30:00Z", int64(9007199254740993)}, Desc: []bool{false, true, true}},
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			token, err := tt.cursor.Encode()
+			assert.NoError(t, err)
+			assert.NotContains(t, token, "=")
+
+			res, err := DecodeCursor(token)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "not base64", token: "%%%"},
+		{name: "not json", token: base64.RawURLEncoding.EncodeToString([]byte("id=2"))},
+		{name: "no values", token: base64.RawURLEncoding.EncodeToString([]byte(`{"v":[],"d":[]}`))},
+		{name: "no directions", token: base64.RawURLEncoding.EncodeToString([]byte(`{"v":[2]}`))},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := DecodeCursor(tt.token)
+
+			assert.ErrorIs(t, err, ErrInvalidValue)
+			assert.True(t, res.IsEmpty())
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// SQLSTATE codes mapped to sentinel errors.
+var sqlStateErrors = map[string]error{
+	"23505": ErrConflict,
+	"23P01": ErrConflict,
+	"23503": ErrForeignKeyViolation,
+	"23502": ErrNotNullViolation,
+	"23514": ErrCheckViolation,
+	"40001": ErrSerializationFailure,
+	"40P01": ErrDeadlock,
+}
+
+// DBError driver error mapped to a repository sentinel, e.g. ErrNotFound or ErrConflict, so callers
+// don't depend on database/sql and driver errors. It matches both the sentinel and the driver error,
+// errors.Is(err, sql.ErrNoRows) keeps working.
+type DBError struct {
+	Kind error
+	Err  error
+	// Constraint is the violated constraint and Column the violated column of pgdriver errors, when reported.
+	Constraint string
+	Column     string
+}
+
+func (e *DBError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DBError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// dbError maps err of a query to DBError, unknown errors are returned as is.
+func dbError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var mapped *DBError
+	if errors.As(err, &mapped) {
+		return err
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return &DBError{Kind: ErrNotFound, Err: err}
+	}
+
+	if kind, ok := sqlStateErrors[sqlState(err)]; ok {
+		mapped = &DBError{Kind: kind, Err: err}
+
+		var pgdriverErr interface{ Field(k byte) string }
+		if errors.As(err, &pgdriverErr) {
+			mapped.Constraint, mapped.Column = pgdriverErr.Field('n'), pgdriverErr.Field('c')
+		}
+
+		return mapped
+	}
+
+	return err
+}
+
+// sqlState returns the SQLSTATE code of pgdriver and pgx errors, empty for other errors.
+func sqlState(err error) string {
+	var pgdriverErr interface{ Field(k byte) string }
+	if errors.As(err, &pgdriverErr) {
+		return pgdriverErr.Field('C')
+	}
+
+	var pgxErr interface{ SQLState() string }
+	if errors.As(err, &pgxErr) {
+		return pgxErr.SQLState()
+	}
+
+	return ""
+}
+
+// isRowViolation reports integrity constraint violations (SQLSTATE class 23).
+func isRowViolation(err error) bool {
+	return strings.HasPrefix(sqlState(err), "23")
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDbError(t *testing.T) {
+	t.Parallel()
+
+	errOther := errors.New("connection lost")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+		mapped   bool
+	}{
+		{name: "nil", err: nil},
+		{name: "no rows", err: sql.ErrNoRows, expected: ErrNotFound, mapped: true},
+		{name: "wrapped no rows", err: fmt.Errorf("scan: %w", sql.ErrNoRows), expected: ErrNotFound, mapped: true},
+		{name: "unique violation", err: testPgError{code: "23505"}, expected: ErrConflict, mapped: true},
+		{name: "exclusion violation", err: testPgError{code: "23P01"}, expected: ErrConflict, mapped: true},
+		{name: "foreign key violation", err: testPgError{code: "23503"}, expected: ErrForeignKeyViolation, mapped: true},
+		{name: "not null violation", err: testPgError{code: "23502"}, expected: ErrNotNullViolation, mapped: true},
+		{name: "check violation", err: testPgError{code: "23514"}, expected: ErrCheckViolation, mapped: true},
+		{name: "serialization failure", err: testPgError{code: "40001"}, expected: ErrSerializationFailure, mapped: true},
+		{name: "deadlock", err: testPgError{code: "40P01"}, expected: ErrDeadlock, mapped: true},
+		{name: "unmapped state", err: testPgError{code: "42P01"}},
+		{name: "other error", err: errOther},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res := dbError(tt.err)
+
+			if !tt.mapped {
+				assert.Equal(t, tt.err, res)
+
+				return
+			}
+
+			var dbErr *DBError
+
+			assert.ErrorAs(t, res, &dbErr)
+			assert.ErrorIs(t, res, tt.expected)
+			assert.ErrorIs(t, res, tt.err)
+			assert.Equal(t, tt.err.Error(), res.Error())
+			assert.Same(t, dbErr, dbError(res))
+		})
+	}
+}
+
+func TestBunCrudRepository_DBError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(mock sqlmock.Sqlmock)
+		run      func(repo *TestSimpleEntBunRepo) error
+		expected error
+	}{
+		{
+			name: "find one not found",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+			run: func(repo *TestSimpleEntBunRepo) error {
+				_, err := repo.FindOne(context.Background(), nil, nil, dataspec.NewEqual("id", 1))
+
+				return err //nolint:wrapcheck
+			},
+			expected: ErrNotFound,
+		},
+		{
+			name: "create one conflict",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("^INSERT").WillReturnError(testPgError{code: "23505"})
+			},
+			run: func(repo *TestSimpleEntBunRepo) error {
+				_, err := repo.CreateOne(context.Background(), nil, &TestSimpleEnt{ID: 1, Name: "a"}, nil)
+
+				return err //nolint:wrapcheck
+			},
+			expected: ErrConflict,
+		},
+		{
+			name: "delete foreign key violation",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("^DELETE").WillReturnError(testPgError{code: "23503"})
+			},
+			run: func(repo *TestSimpleEntBunRepo) error {
+				_, err := repo.Delete(context.Background(), nil, dataspec.NewEqual("id", 1))
+
+				return err //nolint:wrapcheck
+			},
+			expected: ErrForeignKeyViolation,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+			tt.mock(subject.conn.Mock)
+
+			err := tt.run(repo)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
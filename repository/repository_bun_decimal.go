@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/shopspring/decimal"
+	"github.com/uptrace/bun"
+)
+
+// SumDecimal returns the sum of a numeric column over rows matching spec without a float64 round trip.
+// Column is a presenter or persistence name, an empty set sums to zero.
+func (r BunCrudRepository[E, T]) SumDecimal(
+	ctx context.Context,
+	tx bun.IDB,
+	column string,
+	spec dataset.Specifier,
+) (decimal.Decimal, error) {
+	var sum decimal.Decimal
+
+	column = r.persistenceName(column)
+	if _, ok := r.Meta.PersistencePresenterMapping()[column]; !ok {
+		return sum, fmt.Errorf("sum decimal: %s: %w", column, ErrUnknownColumn)
+	}
+
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return sum, fmt.Errorf("sum decimal: %w", err)
+	}
+
+	query := tx.
+		NewSelect().
+		Model((*E)(nil)).
+		ColumnExpr("COALESCE(SUM(?TableAlias.?), 0)::text", bun.Ident(column))
+
+	r.applySpec(query, spec)
+
+	err = query.Scan(ctx, &sum)
+	if err != nil {
+		return sum, fmt.Errorf("sum decimal: %w", dbError(err))
+	}
+
+	return sum, nil
+}
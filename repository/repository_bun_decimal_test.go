@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/bun-pg-connector"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type TestInvoiceEnt struct {
+	bun.BaseModel `bun:"table:test_invoices,alias:test_invoices"`
+
+	ID    int             `bun:"id,pk,autoincrement" json:"id"`
+	Total decimal.Decimal `bun:"total,type:numeric" json:"total"`
+}
+
+func (r TestInvoiceEnt) EntityName() string {
+	return "TestInvoiceEnt"
+}
+
+func (r TestInvoiceEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+type TestInvoiceEntMeta struct {
+	TestInvoiceEnt
+}
+
+func (r TestInvoiceEntMeta) Entity() metadata.Entity { return r.TestInvoiceEnt }
+
+func (r TestInvoiceEntMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func NewTestInvoiceEntRepository(
+	connSet bunpgconnector.BunConnSet,
+) BunCrudRepository[TestInvoiceEnt, bun.Tx] {
+	return BunCrudRepository[TestInvoiceEnt, bun.Tx]{
+		ConnSet: connSet,
+		Meta:    meta.Parser(TestInvoiceEntMeta{}),
+	}
+}
+
+func TestBunCrudRepository_SumDecimal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		column   string
+		mock     func(set *MockBunConnSet)
+		expected string
+		err      error
+	}{
+		{
+			name:   "sum decimal",
+			column: "total",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT COALESCE(SUM("test_invoices"."total"), 0)::text FROM "test_invoices" WHERE (test_invoices.total > '10.05')`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow("12345678901234567890.123456789"))
+			},
+			expected: "12345678901234567890.123456789",
+		},
+		{
+			name:     "sum decimal unknown column",
+			column:   "amount",
+			mock:     func(conn *MockBunConnSet) {},
+			expected: "0",
+			err:      ErrUnknownColumn,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestInvoiceEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			res, err := repo.SumDecimal(
+				context.Background(),
+				nil,
+				tt.column,
+				dataspec.NewGt("total", decimal.RequireFromString("10.05")),
+			)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, res.String())
+		})
+	}
+}
+
+func TestBunCrudRepository_SetValuesDecimal(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestInvoiceEntRepository(subject.conn)
+
+	var entity TestInvoiceEnt
+
+	assert.NoError(t, repo.setValues(subject.conn.WritePool(), &entity, map[string]any{"total": "0.10000000000000000001"}))
+	assert.Equal(t, "0.10000000000000000001", entity.Total.String())
+
+	assert.ErrorIs(t, repo.setValues(subject.conn.WritePool(), &entity, map[string]any{"total": "ten"}), ErrInvalidValue)
+}
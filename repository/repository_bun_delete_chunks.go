@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+// defaultDeleteChunkSize is the primary keys per DELETE of ForceDeleteByPks without DeleteChunkSize.
+const defaultDeleteChunkSize = 1000
+
+// ChunkedDelete rows deleted by ForceDeleteByPks, in total and per chunk in order.
+type ChunkedDelete struct {
+	Total  int
+	Chunks []int
+}
+
+// ForceDeleteByPks deletes rows with the primary keys, also for soft delete entities, in chunks of
+// DeleteChunkSize keys, so long key lists of cleanup jobs don't make giant IN clauses. Every chunk runs in
+// its own transaction on the write pool; when a chunk fails, the result has the chunks deleted before it.
+func (r BunCrudRepository[E, T]) ForceDeleteByPks(
+	ctx context.Context,
+	pks []metadata.PrimaryKey,
+) (ChunkedDelete, error) {
+	var res ChunkedDelete
+
+	if len(pks) == 0 {
+		return res, nil
+	}
+
+	keys := PrimaryKeyOrder(pks[0])
+	for i, pk := range pks {
+		if !slices.Equal(keys, PrimaryKeyOrder(pk)) {
+			return res, fmt.Errorf(
+				"force delete by pks: primary key %d %v, expected %v: %w", i, PrimaryKeyOrder(pk), keys, ErrInconsistentColumns,
+			)
+		}
+	}
+
+	if r.readOnly() {
+		return res, fmt.Errorf("force delete by pks: %w", ErrReadOnlyEntity)
+	}
+
+	db, err := r.rolePool(ctx, r.ConnSet.WritePool, TargetPrimary)
+	if err != nil {
+		return res, fmt.Errorf("force delete by pks: %w", err)
+	}
+
+	size := r.DeleteChunkSize
+	if size <= 0 {
+		size = defaultDeleteChunkSize
+	}
+
+	for from := 0; from < len(pks); from += size {
+		chunk := pks[from:min(from+size, len(pks))]
+
+		spec, err := pksSpec(chunk)
+		if err != nil {
+			return res, fmt.Errorf("force delete by pks: %w", err)
+		}
+
+		var deleted int
+
+		err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			deleted, err = r.ForceDelete(ctx, tx, spec)
+
+			return err
+		})
+		if err != nil {
+			return res, fmt.Errorf("force delete by pks: chunk %d: %w", len(res.Chunks), err)
+		}
+
+		res.Chunks = append(res.Chunks, deleted)
+		res.Total += deleted
+	}
+
+	return res, nil
+}
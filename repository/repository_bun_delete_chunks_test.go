@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_ForceDeleteByPks(t *testing.T) {
+	t.Parallel()
+
+	errDelete := errors.New("delete failed")
+
+	tests := []struct {
+		name     string
+		pks      []metadata.PrimaryKey
+		mock     func(conn *MockBunConnSet)
+		expected ChunkedDelete
+		err      error
+	}{
+		{
+			name: "chunk per transaction",
+			pks:  []metadata.PrimaryKey{{"id": 1}, {"id": 2}, {"id": 3}},
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectBegin()
+				conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`DELETE FROM "test_simple_entities" AS "test_simple_entities" WHERE (test_simple_entities.id IN (1, 2))`) + "$").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				conn.Mock.ExpectCommit()
+				conn.Mock.ExpectBegin()
+				conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`DELETE FROM "test_simple_entities" AS "test_simple_entities" WHERE (test_simple_entities.id IN (3))`) + "$").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				conn.Mock.ExpectCommit()
+			},
+			expected: ChunkedDelete{Total: 2, Chunks: []int{2, 0}},
+		},
+		{
+			name: "failed chunk keeps deleted chunks",
+			pks:  []metadata.PrimaryKey{{"id": 1}, {"id": 2}, {"id": 3}},
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectBegin()
+				conn.Mock.ExpectExec("^DELETE").WillReturnResult(sqlmock.NewResult(0, 2))
+				conn.Mock.ExpectCommit()
+				conn.Mock.ExpectBegin()
+				conn.Mock.ExpectExec("^DELETE").WillReturnError(errDelete)
+				conn.Mock.ExpectRollback()
+			},
+			expected: ChunkedDelete{Total: 2, Chunks: []int{2}},
+			err:      errDelete,
+		},
+		{
+			name:     "inconsistent keys",
+			pks:      []metadata.PrimaryKey{{"id": 1}, {"id": 2}, {"name": "c"}},
+			mock:     func(conn *MockBunConnSet) {},
+			expected: ChunkedDelete{},
+			err:      ErrInconsistentColumns,
+		},
+		{
+			name:     "no keys",
+			mock:     func(conn *MockBunConnSet) {},
+			expected: ChunkedDelete{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+			repo.DeleteChunkSize = 2
+
+			tt.mock(subject.conn)
+
+			res, err := repo.ForceDeleteByPks(context.Background(), tt.pks)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, res)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+// DeleteByPk deletes the row with the primary key, soft deleting it for soft delete entities.
+func (r BunCrudRepository[E, T]) DeleteByPk(
+	ctx context.Context,
+	tx bun.IDB,
+	pk metadata.PrimaryKey,
+) (int, error) {
+	if len(pk) == 0 {
+		return 0, fmt.Errorf("delete by pk: %w", ErrEmptyValues)
+	}
+
+	return r.Delete(ctx, tx, pkSpec(pk))
+}
+
+// ForceDeleteByPk deletes the row with the primary key, also for soft delete entities.
+func (r BunCrudRepository[E, T]) ForceDeleteByPk(
+	ctx context.Context,
+	tx bun.IDB,
+	pk metadata.PrimaryKey,
+) (int, error) {
+	if len(pk) == 0 {
+		return 0, fmt.Errorf("force delete by pk: %w", ErrEmptyValues)
+	}
+
+	return r.ForceDelete(ctx, tx, pkSpec(pk))
+}
+
+// DeleteByPks deletes rows with the primary keys, soft deleting them for soft delete entities.
+func (r BunCrudRepository[E, T]) DeleteByPks(
+	ctx context.Context,
+	tx bun.IDB,
+	pks []metadata.PrimaryKey,
+) (int, error) {
+	if len(pks) == 0 {
+		return 0, nil
+	}
+
+	spec, err := pksSpec(pks)
+	if err != nil {
+		return 0, fmt.Errorf("delete by pks: %w", err)
+	}
+
+	return r.Delete(ctx, tx, spec)
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_DeleteByPk(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		call     func(ctx context.Context, repo *TestComplexEntBunRepo) (int, error)
+		expected func(t *testing.T, res int, err error)
+	}{
+		{
+			name: "delete by pk",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`DELETE FROM "test_complex_entities" AS "test_complex_entities" WHERE ((test_complex_entities.first_id = 1 AND test_complex_entities.second_id = 2))`) + "$").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(ctx context.Context, repo *TestComplexEntBunRepo) (int, error) {
+				return repo.DeleteByPk(ctx, nil, metadata.PrimaryKey{"firstId": 1, "secondId": 2})
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 1, res)
+			},
+		},
+		{
+			name: "force delete by pk",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`DELETE FROM "test_complex_entities" AS "test_complex_entities" WHERE ((test_complex_entities.first_id = 1 AND test_complex_entities.second_id = 2))`) + "$").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(ctx context.Context, repo *TestComplexEntBunRepo) (int, error) {
+				return repo.ForceDeleteByPk(ctx, nil, metadata.PrimaryKey{"firstId": 1, "secondId": 2})
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 1, res)
+			},
+		},
+		{
+			name: "delete by pks",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`DELETE FROM "test_complex_entities" AS "test_complex_entities" WHERE ((test_complex_entities.first_id,test_complex_entities.second_id) IN ((1, 2), (3, 4)))`) + "$").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			call: func(ctx context.Context, repo *TestComplexEntBunRepo) (int, error) {
+				return repo.DeleteByPks(ctx, nil, []metadata.PrimaryKey{
+					{"firstId": 1, "secondId": 2},
+					{"firstId": 3, "secondId": 4},
+				})
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 2, res)
+			},
+		},
+		{
+			name: "delete by empty pk",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo *TestComplexEntBunRepo) (int, error) {
+				return repo.DeleteByPk(ctx, nil, metadata.PrimaryKey{})
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrEmptyValues)
+				assert.Equal(t, 0, res)
+			},
+		},
+		{
+			name: "force delete by empty pk",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo *TestComplexEntBunRepo) (int, error) {
+				return repo.ForceDeleteByPk(ctx, nil, nil)
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrEmptyValues)
+				assert.Equal(t, 0, res)
+			},
+		},
+		{
+			name: "delete by pks without pks",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo *TestComplexEntBunRepo) (int, error) {
+				return repo.DeleteByPks(ctx, nil, nil)
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 0, res)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestComplexEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			res, err := tt.call(context.Background(), repo)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+
+			tt.expected(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// Diff compares rows of the left spec with rows of the right spec of a repository of the same shape,
+// e.g. a staging table, by key columns with EXCEPT. Added are left rows with keys missing on the right,
+// removed are right rows with keys missing on the left and changed are left rows whose keys exist on the right
+// with different values. All columns must support equality, json columns should be jsonb.
+func (r BunCrudRepository[E, T]) Diff(
+	ctx context.Context,
+	tx bun.IDB,
+	leftSpec dataset.Specifier,
+	right BunCrudRepository[E, T],
+	rightSpec dataset.Specifier,
+	keyColumns []string,
+) (added, removed, changed []E, err error) {
+	added, removed, changed = make([]E, 0), make([]E, 0), make([]E, 0)
+
+	if len(keyColumns) == 0 {
+		return added, removed, changed, fmt.Errorf("diff: key columns: %w", ErrEmptyValues)
+	}
+
+	tx, err = r.readDB(ctx, tx)
+	if err != nil {
+		return added, removed, changed, fmt.Errorf("diff: %w", err)
+	}
+
+	table := tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem())
+
+	keys := make([]bun.Ident, 0, len(keyColumns))
+	for _, k := range keyColumns {
+		column := r.persistenceName(k)
+		if table.LookupField(column) == nil {
+			return added, removed, changed, fmt.Errorf("diff %s: %w", k, ErrUnknownColumn)
+		}
+
+		keys = append(keys, bun.Ident(column))
+	}
+
+	columns := make([]bun.Ident, 0, len(table.Fields))
+	for _, f := range table.Fields {
+		columns = append(columns, bun.Ident(f.Name))
+	}
+
+	left := func(columns []bun.Ident) *bun.SelectQuery { return r.selectTable(tx, leftSpec, columns) }
+	rightSide := func(columns []bun.Ident) *bun.SelectQuery { return right.selectTable(tx, rightSpec, columns) }
+
+	queries := []struct {
+		dest  *[]E
+		query *bun.SelectQuery
+	}{
+		{
+			dest: &added,
+			query: tx.NewSelect().
+				TableExpr("(?) AS _diff", left(columns)).
+				Where("(?) IN (?)", bun.In(keys), left(keys).Except(rightSide(keys))),
+		},
+		{
+			dest: &removed,
+			query: tx.NewSelect().
+				TableExpr("(?) AS _diff", rightSide(columns)).
+				Where("(?) IN (?)", bun.In(keys), rightSide(keys).Except(left(keys))),
+		},
+		{
+			dest: &changed,
+			query: tx.NewSelect().
+				TableExpr("(?) AS _diff", left(columns).Except(rightSide(columns))).
+				Where("(?) IN (?)", bun.In(keys), rightSide(keys)),
+		},
+	}
+
+	for _, q := range queries {
+		if err = q.query.Scan(ctx, q.dest); err != nil {
+			return added, removed, changed, fmt.Errorf("diff: %w", dbError(err))
+		}
+
+		r.localize(*q.dest)
+	}
+
+	return added, removed, changed, nil
+}
+
+// selectTable selects columns of the repository table aliased the way specs qualify it, matching the spec.
+func (r BunCrudRepository[E, T]) selectTable(tx bun.IDB, spec dataset.Specifier, columns []bun.Ident) *bun.SelectQuery {
+	name := bun.Ident(r.specMeta().PersistenceName())
+	query := tx.NewSelect().TableExpr("? AS ?", bun.Ident(r.Meta.PersistenceName()), name)
+
+	for _, c := range columns {
+		query.ColumnExpr("?.?", name, c)
+	}
+
+	r.applySpec(query, spec)
+
+	return query
+}
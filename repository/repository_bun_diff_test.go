@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+// stagingMeta points a repository at a staging table of the same shape.
+type stagingMeta struct {
+	metadata.Meta
+}
+
+func (r stagingMeta) PersistenceName() string { return "staging_" + r.Meta.PersistenceName() }
+
+func TestBunCrudRepository_Diff(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestSimpleEntRepository(subject.conn)
+	staging := repo.BunCrudRepository
+	staging.Meta = stagingMeta{Meta: repo.Meta}
+
+	expect := func(query string, rows *sqlmock.Rows) {
+		subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(query) + "$").WillReturnRows(rows)
+	}
+
+	expect(`SELECT * FROM (SELECT "staging_test_simple_entities"."id", "staging_test_simple_entities"."name" FROM "staging_test_simple_entities" AS "staging_test_simple_entities" WHERE (staging_test_simple_entities.id > 0)) AS _diff WHERE (("id") IN ((SELECT "staging_test_simple_entities"."id" FROM "staging_test_simple_entities" AS "staging_test_simple_entities" WHERE (staging_test_simple_entities.id > 0)) EXCEPT (SELECT "test_simple_entities"."id" FROM "test_simple_entities" AS "test_simple_entities")))`,
+		sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "new"))
+	expect(`SELECT * FROM (SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" AS "test_simple_entities") AS _diff WHERE (("id") IN ((SELECT "test_simple_entities"."id" FROM "test_simple_entities" AS "test_simple_entities") EXCEPT (SELECT "staging_test_simple_entities"."id" FROM "staging_test_simple_entities" AS "staging_test_simple_entities" WHERE (staging_test_simple_entities.id > 0))))`,
+		sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "gone"))
+	expect(`SELECT * FROM ((SELECT "staging_test_simple_entities"."id", "staging_test_simple_entities"."name" FROM "staging_test_simple_entities" AS "staging_test_simple_entities" WHERE (staging_test_simple_entities.id > 0)) EXCEPT (SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" AS "test_simple_entities")) AS _diff WHERE (("id") IN (SELECT "test_simple_entities"."id" FROM "test_simple_entities" AS "test_simple_entities"))`,
+		sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "renamed"))
+
+	added, removed, changed, err := staging.Diff(
+		context.Background(), nil, dataspec.NewGt("id", 0), repo.BunCrudRepository, nil, []string{"id"},
+	)
+
+	assert.NoError(t, err)
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.Equal(t, []TestSimpleEnt{{ID: 3, Name: "new"}}, added)
+	assert.Equal(t, []TestSimpleEnt{{ID: 1, Name: "gone"}}, removed)
+	assert.Equal(t, []TestSimpleEnt{{ID: 2, Name: "renamed"}}, changed)
+}
+
+func TestBunCrudRepository_DiffKeyColumns(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestSimpleEntRepository(subject.conn)
+
+	_, _, _, err := repo.Diff(context.Background(), nil, nil, repo.BunCrudRepository, nil, nil)
+	assert.ErrorIs(t, err, ErrEmptyValues)
+
+	_, _, _, err = repo.Diff(context.Background(), nil, nil, repo.BunCrudRepository, nil, []string{"unknown"})
+	assert.ErrorIs(t, err, ErrUnknownColumn)
+}
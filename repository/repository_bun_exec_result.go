@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Target where an operation was routed.
+type Target string
+
+const (
+	// TargetTx caller provided transaction or connection.
+	TargetTx Target = "tx"
+	// TargetPrimary write pool.
+	TargetPrimary Target = "primary"
+	// TargetReplica read pool.
+	TargetReplica Target = "replica"
+	// TargetRole pool of the context role, see WithRole.
+	TargetRole Target = "role"
+)
+
+// ExecResult execution details of repository operations run with a context from WithExecResult.
+// Target and Role are set by the repository. Queries, Duration and RowsAffected are accumulated
+// by ExecResultHook, which must be added to the pools with AddQueryHook.
+type ExecResult struct {
+	Target       Target
+	Role         Role
+	Queries      int
+	Duration     time.Duration
+	RowsAffected int64
+
+	mu sync.Mutex
+}
+
+type execResultCtxKey struct{}
+
+// WithExecResult makes operations run with the returned context report into res.
+// Reuse res across operations to accumulate.
+func WithExecResult(ctx context.Context, res *ExecResult) context.Context {
+	return context.WithValue(ctx, execResultCtxKey{}, res)
+}
+
+func execResultFromContext(ctx context.Context) *ExecResult {
+	res, _ := ctx.Value(execResultCtxKey{}).(*ExecResult)
+
+	return res
+}
+
+// routed records the routing target once per result, nested calls of composite operations keep the first one.
+func routed(ctx context.Context, target Target, role Role) {
+	res := execResultFromContext(ctx)
+	if res == nil {
+		return
+	}
+
+	res.mu.Lock()
+	defer res.mu.Unlock()
+
+	if res.Target == "" {
+		res.Target = target
+		res.Role = role
+	}
+}
+
+// ExecResultHook bun query hook accumulating query count, duration and affected rows into the context ExecResult.
+type ExecResultHook struct{}
+
+var _ bun.QueryHook = ExecResultHook{}
+
+func (r ExecResultHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (r ExecResultHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	res := execResultFromContext(ctx)
+	if res == nil {
+		return
+	}
+
+	var rows int64
+	if event.Result != nil {
+		rows, _ = event.Result.RowsAffected()
+	}
+
+	res.mu.Lock()
+	defer res.mu.Unlock()
+
+	res.Queries++
+	res.Duration += time.Since(event.StartTime)
+	res.RowsAffected += rows
+}
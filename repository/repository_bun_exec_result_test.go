@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+type hookedConnSet struct {
+	db *bun.DB
+}
+
+func (r hookedConnSet) ReadPool() *bun.DB {
+	return r.db
+}
+
+func (r hookedConnSet) WritePool() *bun.DB {
+	return r.db
+}
+
+func TestBunCrudRepository_ExecResult(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mock    func(mock sqlmock.Sqlmock)
+		run     func(ctx context.Context, repo *TestSimpleEntBunRepo) error
+		target  Target
+		queries int
+		rows    int64
+	}{
+		{
+			name: "exec result of read",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b"))
+			},
+			run: func(ctx context.Context, repo *TestSimpleEntBunRepo) error {
+				_, err := repo.FindAll(ctx, nil, nil, nil)
+
+				return err //nolint:wrapcheck
+			},
+			target:  TargetReplica,
+			queries: 1,
+			rows:    2,
+		},
+		{
+			name: "exec result of write",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("^DELETE").WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			run: func(ctx context.Context, repo *TestSimpleEntBunRepo) error {
+				_, err := repo.Delete(ctx, nil, dataspec.NewEqual("name", "a"))
+
+				return err //nolint:wrapcheck
+			},
+			target:  TargetPrimary,
+			queries: 1,
+			rows:    3,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqldb, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+
+			db := bun.NewDB(sqldb, pgdialect.New())
+			db.AddQueryHook(ExecResultHook{})
+
+			repo := NewTestSimpleEntRepository(hookedConnSet{db: db})
+			tt.mock(mock)
+
+			var res ExecResult
+
+			assert.NoError(t, tt.run(WithExecResult(context.Background(), &res), repo))
+			assert.NoError(t, mock.ExpectationsWereMet())
+			assert.Equal(t, tt.target, res.Target)
+			assert.Equal(t, tt.queries, res.Queries)
+			assert.Equal(t, tt.rows, res.RowsAffected)
+			assert.Positive(t, res.Duration)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+// Pair row of two joined entities.
+type Pair[L, R any] struct {
+	Left  L `bun:"embed:left__"`
+	Right R `bun:"embed:right__"`
+}
+
+// FindAllWith joins the declared relation of the repository entity and scans both sides of every row
+// of rows matching spec in one query, without eager loading. R is the related entity of the relation,
+// to many relations return a pair per related row.
+func FindAllWith[E metadata.Entity, T bun.Tx, R any](
+	ctx context.Context,
+	repo BunCrudRepository[E, T],
+	tx bun.IDB,
+	spec dataset.Specifier,
+	relation string,
+) ([]Pair[E, R], error) {
+	var pairs = make([]Pair[E, R], 0)
+
+	rel, ok := repo.Meta.Relations()[relation]
+	if !ok {
+		return pairs, fmt.Errorf("find all with: %s: %w", relation, ErrUnknownRelation)
+	}
+
+	if err := repo.guardFullTable(ctx, spec); err != nil {
+		return pairs, fmt.Errorf("find all with: %w", err)
+	}
+
+	tx, err := repo.readDB(ctx, tx)
+	if err != nil {
+		return pairs, fmt.Errorf("find all with: %w", err)
+	}
+
+	query := tx.
+		NewSelect().
+		Model((*E)(nil))
+
+	for _, c := range sortedKeys(repo.Meta.PersistencePresenterMapping()) {
+		query.ColumnExpr("?TableAlias.? AS ?", bun.Ident(c), bun.Ident("left__"+c))
+	}
+
+	for _, c := range sortedKeys(rel.GetMeta().PersistencePresenterMapping()) {
+		query.ColumnExpr("?.? AS ?", bun.Ident(rel.Table()), bun.Ident(c), bun.Ident("right__"+c))
+	}
+
+	joins := rel.Join()
+	for _, j := range joins {
+		query.Join(j.JoinString, j.Args...)
+	}
+
+	if applied := repo.evalSpec(spec); applied != nil {
+		applied.joins = slices.DeleteFunc(applied.joins, func(j metadata.Join) bool {
+			return slices.ContainsFunc(joins, func(v metadata.Join) bool { return v.JoinString == j.JoinString })
+		})
+		applied.apply(query)
+	}
+
+	if err := query.Scan(ctx, &pairs); err != nil {
+		return pairs, fmt.Errorf("find all with: %w", dbError(err))
+	}
+
+	repo.localize(pairs)
+
+	for i := range pairs {
+		if err := repo.afterScanOne(ctx, &pairs[i].Left); err != nil {
+			return pairs, fmt.Errorf("find all with: %w", err)
+		}
+	}
+
+	return pairs, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+func TestFindAllWith(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		relation string
+		spec     dataset.Specifier
+		expected func(t *testing.T, res []Pair[TestCategoryEnt, TestItemEnt], err error)
+	}{
+		{
+			name: "find all with to one relation",
+			mock: func(conn *MockBunConnSet) {
+				rows := sqlmock.NewRows([]string{"left__id", "left__main_item_id", "left__name", "right__id", "right__name"}).
+					AddRow(1, 10, "category", 10, "item")
+
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_categories"."id" AS "left__id", "test_categories"."main_item_id" AS "left__main_item_id", "test_categories"."name" AS "left__name", "test_items"."id" AS "right__id", "test_items"."name" AS "right__name" FROM "test_categories" INNER JOIN test_items ON main_item_id = test_items.id WHERE ("test_items"."name" = 'item')`) + "$").
+					WillReturnRows(rows)
+			},
+			relation: "MainItem",
+			spec:     dataspec.NewEqual("Category.MainItem.name", "item"),
+			expected: func(t *testing.T, res []Pair[TestCategoryEnt, TestItemEnt], err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, []Pair[TestCategoryEnt, TestItemEnt]{
+					{
+						Left:  TestCategoryEnt{ID: 1, Name: "category", MainItemID: 10},
+						Right: TestItemEnt{ID: 10, Name: "item"},
+					},
+				}, res)
+			},
+		},
+		{
+			name: "find all with to many relation",
+			mock: func(conn *MockBunConnSet) {
+				rows := sqlmock.NewRows([]string{"left__id", "left__main_item_id", "left__name", "right__id", "right__name"}).
+					AddRow(1, 10, "category", 10, "a").
+					AddRow(1, 10, "category", 11, "b")
+
+				conn.Mock.ExpectQuery(regexp.QuoteMeta(`FROM "test_categories" INNER JOIN test_category_items ON test_category_items.category_id = test_categories.id INNER JOIN test_items ON item_id = test_items.id`) + "$").
+					WillReturnRows(rows)
+			},
+			relation: "Items",
+			expected: func(t *testing.T, res []Pair[TestCategoryEnt, TestItemEnt], err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				if assert.Len(t, res, 2) {
+					assert.Equal(t, "b", res[1].Right.Name)
+					assert.Equal(t, 1, res[1].Left.ID)
+				}
+			},
+		},
+		{
+			name:     "find all with unknown relation",
+			mock:     func(conn *MockBunConnSet) {},
+			relation: "Unknown",
+			expected: func(t *testing.T, res []Pair[TestCategoryEnt, TestItemEnt], err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrUnknownRelation)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestCategoryEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			res, err := FindAllWith[TestCategoryEnt, bun.Tx, TestItemEnt](context.Background(), repo.BunCrudRepository, nil, tt.spec, tt.relation)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+
+			tt.expected(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+)
+
+// returning is the RETURNING list of write queries, bun omits the clause for "NULL".
+func (r BunCrudRepository[E, T]) returning(columns string) string {
+	if r.NoReturning {
+		return "NULL"
+	}
+
+	return columns
+}
+
+// specMeta is the meta specs and sorts are rendered with, columns of schema qualified tables
+// are qualified by the table alias, see meta.Aliased.
+func (r BunCrudRepository[E, T]) specMeta() metadata.Meta {
+	if a, ok := r.Meta.(meta.Aliased); ok && a.Alias() != r.Meta.PersistenceName() {
+		return aliasMeta{Meta: r.Meta, alias: a.Alias()}
+	}
+
+	return r.Meta
+}
+
+// aliasMeta is a meta naming the table by its alias.
+type aliasMeta struct {
+	metadata.Meta
+
+	alias string
+}
+
+func (r aliasMeta) PersistenceName() string {
+	return r.alias
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type TestRemoteOrderEnt struct {
+	bun.BaseModel `bun:"table:remote.orders,alias:orders"`
+
+	ID     int    `bun:"id,pk" json:"id"`
+	Status string `bun:"status" json:"status"`
+}
+
+func (r TestRemoteOrderEnt) EntityName() string {
+	return "TestRemoteOrderEnt"
+}
+
+func (r TestRemoteOrderEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+type TestRemoteOrderEntMeta struct {
+	TestRemoteOrderEnt
+}
+
+func (r TestRemoteOrderEntMeta) Entity() metadata.Entity { return r.TestRemoteOrderEnt }
+
+func (r TestRemoteOrderEntMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func TestBunCrudRepository_ForeignTable(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := BunCrudRepository[TestRemoteOrderEnt, bun.Tx]{
+		ConnSet:     subject.conn,
+		Meta:        meta.Parser(TestRemoteOrderEntMeta{}),
+		NoReturning: true,
+	}
+
+	subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "orders"."id", "orders"."status" FROM "remote"."orders" AS "orders" WHERE (orders.status = 'new') ORDER BY id ASC LIMIT 10 OFFSET 10`) + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "new"))
+	subject.conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`INSERT INTO "remote"."orders" ("id", "status") VALUES (2, 'new')`) + "$").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res, err := repo.FindPage(
+		context.Background(),
+		nil,
+		[]string{"id", "status"},
+		dataspec.NewEqual("status", "new"),
+		NewPager(10, 1),
+		NewSorter().WithSort("id", "asc"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []TestRemoteOrderEnt{{ID: 1, Status: "new"}}, res)
+
+	_, err = repo.CreateOne(context.Background(), nil, &TestRemoteOrderEnt{ID: 2, Status: "new"}, nil)
+	assert.NoError(t, err)
+
+	_, err = repo.UpdateOrCreate(
+		context.Background(), nil, dataspec.NewEqual("id", 2), map[string]any{"status": "paid"}, &TestRemoteOrderEnt{}, nil,
+	)
+	assert.ErrorIs(t, err, ErrReturningRequired)
+	assert.Equal(t, "remote.orders_y2024m01", repo.PartitionName(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)))
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+)
+
+type fullTableCtxKey struct{}
+
+// AllowFullTable allows whole table operations of a GuardFullTable repository to run with a nil or empty spec.
+func AllowFullTable(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fullTableCtxKey{}, true)
+}
+
+// guardFullTable rejects whole table operations unless the call allows them.
+func (r BunCrudRepository[E, T]) guardFullTable(ctx context.Context, spec dataset.Specifier) error {
+	if !r.GuardFullTable || spec != nil && !spec.IsEmpty() {
+		return nil
+	}
+
+	if allowed, _ := ctx.Value(fullTableCtxKey{}).(bool); allowed {
+		return nil
+	}
+
+	return ErrFullTable
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_GuardFullTable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		call     func(ctx context.Context, repo *TestSimpleEntBunRepo) error
+		expected error
+	}{
+		{
+			name: "find all without spec",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo) error {
+				_, err := repo.FindAll(ctx, nil, nil, nil)
+
+				return err
+			},
+			expected: ErrFullTable,
+		},
+		{
+			name: "delete with empty spec",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo) error {
+				_, err := repo.Delete(ctx, nil, dataspec.NewAnd())
+
+				return err
+			},
+			expected: ErrFullTable,
+		},
+		{
+			name: "force delete without spec",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo) error {
+				_, err := repo.ForceDelete(ctx, nil, nil)
+
+				return err
+			},
+			expected: ErrFullTable,
+		},
+		{
+			name: "update or create without spec",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo) error {
+				_, err := repo.UpdateOrCreate(ctx, nil, nil, map[string]any{"name": "a"}, &TestSimpleEnt{}, nil)
+
+				return err
+			},
+			expected: ErrFullTable,
+		},
+		{
+			name: "delete with spec",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^DELETE FROM \"test_simple_entities\" AS \"test_simple_entities\" WHERE \\(test_simple_entities.id = 1\\)$").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo) error {
+				_, err := repo.Delete(ctx, nil, dataspec.NewEqual("id", 1))
+
+				return err
+			},
+		},
+		{
+			name: "find all allowing full table",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^SELECT \"test_simple_entities\".\"id\", \"test_simple_entities\".\"name\" FROM \"test_simple_entities\"$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+			},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo) error {
+				_, err := repo.FindAll(AllowFullTable(ctx), nil, nil, nil)
+
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+			repo.GuardFullTable = true
+
+			tt.mock(subject.conn)
+
+			err := tt.call(context.Background(), repo)
+
+			assert.ErrorIs(t, err, tt.expected)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
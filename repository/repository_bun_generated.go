@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"slices"
+)
+
+// Generated entity lists DB-generated persistence columns (generated, identity) which the repository
+// never writes and always returns after inserts and updates.
+type Generated interface {
+	GeneratedColumns() []string
+}
+
+func generatedColumns(entity any) []string {
+	if g, ok := entity.(Generated); ok {
+		return g.GeneratedColumns()
+	}
+
+	return nil
+}
+
+// withGenerated appends generated columns to an explicit returning column list.
+func withGenerated(columns []string, generated []string) []string {
+	if len(generated) == 0 || len(columns) == 0 || slices.Contains(columns, "*") {
+		return columns
+	}
+
+	result := slices.Clip(columns)
+
+	for _, c := range generated {
+		if !slices.Contains(result, c) {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+// withoutGenerated removes generated columns from the update column list.
+func withoutGenerated(columns []string, generated []string) []string {
+	if len(generated) == 0 {
+		return columns
+	}
+
+	result := make([]string, 0, len(columns))
+
+	for _, c := range columns {
+		if !slices.Contains(generated, c) {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
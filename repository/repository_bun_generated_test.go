@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/bun-pg-connector"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type TestGeneratedEnt struct {
+	bun.BaseModel `bun:"table:test_generated_entities,alias:test_generated_entities"`
+
+	ID       int    `bun:"id,pk" json:"id"`
+	Name     string `bun:"name" json:"name"`
+	SearchBy string `bun:"search_by" json:"searchBy"`
+}
+
+func (r TestGeneratedEnt) EntityName() string {
+	return "TestGeneratedEnt"
+}
+
+func (r TestGeneratedEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+func (r TestGeneratedEnt) GeneratedColumns() []string {
+	return []string{"search_by"}
+}
+
+type TestGeneratedEntMeta struct {
+	TestGeneratedEnt
+}
+
+func (r TestGeneratedEntMeta) Entity() metadata.Entity { return r.TestGeneratedEnt }
+
+func (r TestGeneratedEntMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func NewTestGeneratedEntRepository(
+	connSet bunpgconnector.BunConnSet,
+) BunCrudRepository[TestGeneratedEnt, bun.Tx] {
+	return BunCrudRepository[TestGeneratedEnt, bun.Tx]{
+		ConnSet: connSet,
+		Meta:    meta.Parser(TestGeneratedEntMeta{}),
+	}
+}
+
+func TestBunCrudRepository_CreateOneWithGenerated(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestGeneratedEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`INSERT INTO "test_generated_entities" ("id", "name") VALUES (1, 'John') RETURNING id,search_by`) + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "search_by"}).AddRow(1, "john"))
+
+	res, err := repo.CreateOne(context.Background(), nil, &TestGeneratedEnt{ID: 1, Name: "John"}, []string{"id"})
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, "john", res.SearchBy)
+}
+
+func TestBunCrudRepository_CreateAllWithGenerated(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestGeneratedEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`INSERT INTO "test_generated_entities" ("id", "name") VALUES (1, 'John'), (2, 'Ann')  RETURNING *`) + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "search_by"}).AddRow(1, "john").AddRow(2, "ann"))
+
+	_, err := repo.CreateAll(context.Background(), nil, []TestGeneratedEnt{{ID: 1, Name: "John"}, {ID: 2, Name: "Ann"}}, []string{"*"})
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+}
+
+func TestBunCrudRepository_UpdateOneWithGenerated(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		query           string
+		columnsToUpdate []string
+	}{
+		{
+			name:            "generated column skipped",
+			query:           `UPDATE "test_generated_entities" AS "test_generated_entities" SET "name" = 'John' WHERE ("test_generated_entities"."id" = 1) RETURNING id,search_by`,
+			columnsToUpdate: []string{"name", "search_by"},
+		},
+		{
+			name:            "generated column excluded from full update",
+			query:           `UPDATE "test_generated_entities" AS "test_generated_entities" SET "name" = 'John' WHERE ("test_generated_entities"."id" = 1) RETURNING id,search_by`,
+			columnsToUpdate: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestGeneratedEntRepository(subject.conn)
+
+			subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(tt.query) + "$").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "search_by"}).AddRow(1, "john"))
+
+			_, err := repo.UpdateOne(
+				context.Background(),
+				nil,
+				&TestGeneratedEnt{ID: 1, Name: "John", SearchBy: "stale"},
+				tt.columnsToUpdate,
+				[]string{"id"},
+			)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// CreateAllIgnoreConflicts inserts entities with ON CONFLICT DO NOTHING, skipping rows conflicting on
+// conflictColumns, or on any unique constraint when none are given. Inserted rows are detected through
+// RETURNING and matched to the input by conflict columns, primary key columns when none are given, so these
+// must identify input rows. Returning columns are read back into inserted entities only.
+// It returns the entities and whether each of them was inserted.
+func (r BunCrudRepository[E, T]) CreateAllIgnoreConflicts(
+	ctx context.Context,
+	tx bun.IDB,
+	entities []E,
+	conflictColumns []string,
+	columns []string,
+) ([]E, []bool, error) {
+	if r.NoReturning {
+		return entities, nil, fmt.Errorf("create all ignore conflicts: %w", ErrReturningRequired)
+	}
+
+	if len(entities) == 0 {
+		return entities, nil, nil
+	}
+
+	if err := r.checkColumns("create all ignore conflicts", conflictColumns...); err != nil {
+		return entities, nil, fmt.Errorf("create all ignore conflicts: %w", err)
+	}
+
+	if err := r.checkColumns("create all ignore conflicts", columns...); err != nil {
+		return entities, nil, fmt.Errorf("create all ignore conflicts: %w", err)
+	}
+
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return entities, nil, fmt.Errorf("create all ignore conflicts: %w", err)
+	}
+
+	if err := r.normalizeAll(tx, entities); err != nil {
+		return entities, nil, fmt.Errorf("create all ignore conflicts: %w", err)
+	}
+
+	if err := r.validate(ctx, entities...); err != nil {
+		return entities, nil, fmt.Errorf("create all ignore conflicts: %w", err)
+	}
+
+	if err := r.checkRelated(ctx, tx, entities, nil); err != nil {
+		return entities, nil, fmt.Errorf("create all ignore conflicts: %w", err)
+	}
+
+	for i := range entities {
+		if err := r.setChecksum(tx, &entities[i]); err != nil {
+			return entities, nil, fmt.Errorf("create all ignore conflicts: %w", err)
+		}
+	}
+
+	table := tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem())
+
+	keys, err := conflictKeyFields(table, conflictColumns)
+	if err != nil {
+		return entities, nil, fmt.Errorf("create all ignore conflicts: %w", err)
+	}
+
+	generated := generatedColumns(*new(E))
+	returning := withGenerated(columns, generated)
+
+	if !slices.Contains(returning, "*") {
+		returning = slices.Clip(returning)
+
+		for _, f := range keys {
+			if !slices.Contains(returning, f.Name) {
+				returning = append(returning, f.Name)
+			}
+		}
+	}
+
+	target := make([]bun.Ident, len(conflictColumns))
+	for i, c := range conflictColumns {
+		target[i] = bun.Ident(c)
+	}
+
+	groups, overrides := r.insertGroups(tx, entities)
+	if len(groups) <= 1 {
+		groups = [][]int{nil}
+	}
+
+	var rows []map[string]any
+
+	for i, group := range groups {
+		chunk := entities
+		if group != nil {
+			chunk = make([]E, 0, len(group))
+			for _, idx := range group {
+				chunk = append(chunk, entities[idx])
+			}
+		}
+
+		query := tx.NewInsert().
+			Model(&chunk).
+			Returning(strings.Join(returning, ","))
+
+		if len(target) > 0 {
+			query.On("CONFLICT (?) DO NOTHING", bun.In(target))
+		} else {
+			query.On("CONFLICT DO NOTHING")
+		}
+
+		if len(generated) > 0 {
+			query.ExcludeColumn(generated...)
+		}
+
+		if len(overrides) > i {
+			applyInsertOverrides(query, overrides[i])
+		}
+
+		var res []map[string]any
+
+		if _, err := query.Exec(ctx, &res); err != nil {
+			return entities, nil, fmt.Errorf("create all ignore conflicts: %w", dbError(err))
+		}
+
+		rows = append(rows, res...)
+	}
+
+	inserted, err := r.matchInserted(tx, table, keys, entities, rows)
+	if err != nil {
+		return entities, nil, fmt.Errorf("create all ignore conflicts: %w", err)
+	}
+
+	return entities, inserted, nil
+}
+
+// matchInserted scans returned rows into the input entities with the same key and flags them inserted.
+func (r BunCrudRepository[E, T]) matchInserted(
+	tx bun.IDB,
+	table *schema.Table,
+	keys []*schema.Field,
+	entities []E,
+	rows []map[string]any,
+) ([]bool, error) {
+	fmter := schema.NewFormatter(tx.Dialect())
+	key := func(strct reflect.Value) string {
+		var b []byte
+		for _, f := range keys {
+			b = f.AppendValue(fmter, b, strct)
+			b = append(b, ',')
+		}
+
+		return string(b)
+	}
+
+	byKey := make(map[string]int, len(entities))
+	for i := range entities {
+		byKey[key(reflect.ValueOf(&entities[i]).Elem())] = i
+	}
+
+	inserted := make([]bool, len(entities))
+
+	for _, row := range rows {
+		var returned E
+
+		strct := reflect.ValueOf(&returned).Elem()
+
+		for _, f := range keys {
+			if err := f.ScanValue(strct, row[f.Name]); err != nil {
+				return nil, err //nolint:wrapcheck
+			}
+		}
+
+		idx, ok := byKey[key(strct)]
+		if !ok {
+			continue
+		}
+
+		inserted[idx] = true
+		target := reflect.ValueOf(&entities[idx]).Elem()
+
+		for column, v := range row {
+			if f := table.LookupField(column); f != nil {
+				if err := f.ScanValue(target, v); err != nil {
+					return nil, err //nolint:wrapcheck
+				}
+			}
+		}
+	}
+
+	return inserted, nil
+}
+
+// conflictKeyFields resolves conflict columns, defaulting to primary key columns.
+func conflictKeyFields(table *schema.Table, conflictColumns []string) ([]*schema.Field, error) {
+	if len(conflictColumns) == 0 {
+		return table.PKs, nil
+	}
+
+	fields := make([]*schema.Field, 0, len(conflictColumns))
+
+	for _, c := range conflictColumns {
+		f := table.LookupField(c)
+		if f == nil {
+			return nil, fmt.Errorf("%s: %w", c, ErrUnknownColumn)
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_CreateAllIgnoreConflicts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		mock            func(set *MockBunConnSet)
+		conflictColumns []string
+		columns         []string
+		expected        func(t *testing.T, res []TestSimpleEnt, inserted []bool, err error)
+	}{
+		{
+			name: "create all ignore conflicts on primary key",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`INSERT INTO "test_simple_entities" AS "test_simple_entities" ("id", "name") VALUES (1, 'a'), (2, 'b'), (3, 'c') ON CONFLICT DO NOTHING RETURNING *`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "A").AddRow(3, "C"))
+			},
+			columns: []string{"*"},
+			expected: func(t *testing.T, res []TestSimpleEnt, inserted []bool, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, []bool{true, false, true}, inserted)
+				assert.Equal(t, []TestSimpleEnt{{ID: 1, Name: "A"}, {ID: 2, Name: "b"}, {ID: 3, Name: "C"}}, res)
+			},
+		},
+		{
+			name: "create all ignore conflicts on columns",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`INSERT INTO "test_simple_entities" AS "test_simple_entities" ("id", "name") VALUES (1, 'a'), (2, 'b'), (3, 'c') ON CONFLICT ("name") DO NOTHING RETURNING id,name`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "b"))
+			},
+			conflictColumns: []string{"name"},
+			columns:         []string{"id"},
+			expected: func(t *testing.T, res []TestSimpleEnt, inserted []bool, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, []bool{false, true, false}, inserted)
+			},
+		},
+		{
+			name:            "create all ignore conflicts on unknown column",
+			mock:            func(conn *MockBunConnSet) {},
+			conflictColumns: []string{"unknown"},
+			expected: func(t *testing.T, res []TestSimpleEnt, inserted []bool, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrUnknownColumn)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			entities := []TestSimpleEnt{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}}
+			res, inserted, err := repo.CreateAllIgnoreConflicts(context.Background(), nil, entities, tt.conflictColumns, tt.columns)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+
+			tt.expected(t, res, inserted, err)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+// Increment adds delta to a numeric column of the row with the primary key in a single
+// UPDATE ... SET column = column + delta, so concurrent increments don't lose updates as read-modify-write
+// through UpdateOne does. Column is a presenter or persistence name, it returns the number of updated rows.
+func (r BunCrudRepository[E, T]) Increment(
+	ctx context.Context,
+	tx bun.IDB,
+	pk metadata.PrimaryKey,
+	column string,
+	delta int64,
+) (int, error) {
+	rows, err := r.increment(ctx, tx, pk, column, delta)
+	if err != nil {
+		return 0, fmt.Errorf("increment: %w", err)
+	}
+
+	return rows, nil
+}
+
+// Decrement subtracts delta from a numeric column of the row with the primary key, see Increment.
+func (r BunCrudRepository[E, T]) Decrement(
+	ctx context.Context,
+	tx bun.IDB,
+	pk metadata.PrimaryKey,
+	column string,
+	delta int64,
+) (int, error) {
+	rows, err := r.increment(ctx, tx, pk, column, -delta)
+	if err != nil {
+		return 0, fmt.Errorf("decrement: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r BunCrudRepository[E, T]) increment(
+	ctx context.Context,
+	tx bun.IDB,
+	pk metadata.PrimaryKey,
+	column string,
+	delta int64,
+) (int, error) {
+	if len(pk) == 0 {
+		return 0, fmt.Errorf("primary key: %w", ErrEmptyValues)
+	}
+
+	column = r.persistenceName(column)
+	if _, ok := r.Meta.PersistencePresenterMapping()[column]; !ok {
+		return 0, fmt.Errorf("%s: %w", column, ErrUnknownColumn)
+	}
+
+	if err := r.checkChecksumValues(map[string]any{column: delta}); err != nil {
+		return 0, err
+	}
+
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	spec := pkSpec(pk)
+
+	res, err := tx.NewUpdate().
+		Model((*E)(nil)).
+		Set("? = ?TableAlias.? + ?", bun.Ident(column), bun.Ident(column), delta).
+		Where(spec.Query(r.specMeta()), r.bindValues(spec.Values())...).
+		Exec(ctx)
+	if err != nil {
+		return 0, dbError(err)
+	}
+
+	rows, err := res.RowsAffected()
+
+	return int(rows), err //nolint:wrapcheck
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+func TestBunCrudRepository_Increment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		run      func(repo BunCrudRepository[TestInvoiceEnt, bun.Tx]) (int, error)
+		mock     func(conn *MockBunConnSet)
+		expected int
+		err      error
+	}{
+		{
+			name: "increment",
+			run: func(repo BunCrudRepository[TestInvoiceEnt, bun.Tx]) (int, error) {
+				return repo.Increment(context.Background(), nil, metadata.PrimaryKey{"id": 1}, "total", 5) //nolint:wrapcheck
+			},
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`UPDATE "test_invoices" AS "test_invoices" SET "total" = "test_invoices"."total" + 5 WHERE ((test_invoices.id = 1))`) + "$").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expected: 1,
+		},
+		{
+			name: "decrement",
+			run: func(repo BunCrudRepository[TestInvoiceEnt, bun.Tx]) (int, error) {
+				return repo.Decrement(context.Background(), nil, metadata.PrimaryKey{"id": 2}, "total", 3) //nolint:wrapcheck
+			},
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`UPDATE "test_invoices" AS "test_invoices" SET "total" = "test_invoices"."total" + -3 WHERE ((test_invoices.id = 2))`) + "$").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expected: 1,
+		},
+		{
+			name: "missing row",
+			run: func(repo BunCrudRepository[TestInvoiceEnt, bun.Tx]) (int, error) {
+				return repo.Increment(context.Background(), nil, metadata.PrimaryKey{"id": 3}, "total", 1) //nolint:wrapcheck
+			},
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^UPDATE").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expected: 0,
+		},
+		{
+			name: "check violation",
+			run: func(repo BunCrudRepository[TestInvoiceEnt, bun.Tx]) (int, error) {
+				return repo.Decrement(context.Background(), nil, metadata.PrimaryKey{"id": 1}, "total", 100) //nolint:wrapcheck
+			},
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^UPDATE").WillReturnError(testPgError{code: "23514"})
+			},
+			err: ErrCheckViolation,
+		},
+		{
+			name: "unknown column",
+			run: func(repo BunCrudRepository[TestInvoiceEnt, bun.Tx]) (int, error) {
+				return repo.Increment(context.Background(), nil, metadata.PrimaryKey{"id": 1}, "stock", 1) //nolint:wrapcheck
+			},
+			mock: func(conn *MockBunConnSet) {},
+			err:  ErrUnknownColumn,
+		},
+		{
+			name: "empty primary key",
+			run: func(repo BunCrudRepository[TestInvoiceEnt, bun.Tx]) (int, error) {
+				return repo.Increment(context.Background(), nil, metadata.PrimaryKey{}, "total", 1) //nolint:wrapcheck
+			},
+			mock: func(conn *MockBunConnSet) {},
+			err:  ErrEmptyValues,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestInvoiceEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			res, err := tt.run(repo)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
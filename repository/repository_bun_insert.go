@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+type InsertBehavior int
+
+const (
+	// InsertZero inserts zero values verbatim.
+	InsertZero InsertBehavior = iota
+	// DefaultOnZero inserts DEFAULT instead of zero values, so serial, identity and default columns apply.
+	DefaultOnZero
+	// NullOnZero inserts NULL instead of zero values.
+	NullOnZero
+)
+
+// ZeroValueInserter entity declares insert behavior of zero values per persistence column.
+type ZeroValueInserter interface {
+	InsertBehaviors() map[string]InsertBehavior
+}
+
+// prepareInsertAll runs the pre-write steps of multi-row inserts: normalization, validation,
+// soft foreign key checks and checksums.
+func (r BunCrudRepository[E, T]) prepareInsertAll(ctx context.Context, tx bun.IDB, entities []E) error {
+	if err := r.normalizeAll(tx, entities); err != nil {
+		return err
+	}
+
+	if err := r.validate(ctx, entities...); err != nil {
+		return err
+	}
+
+	if err := r.checkRelated(ctx, tx, entities, nil); err != nil {
+		return err
+	}
+
+	if _, ok := any(*new(E)).(Checksummed); ok {
+		for i := range entities {
+			if err := r.setChecksum(tx, &entities[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// prepareInsertOne runs the pre-write steps of single entity inserts: normalization, validation and
+// soft foreign key checks.
+func (r BunCrudRepository[E, T]) prepareInsertOne(ctx context.Context, tx bun.IDB, entity *E) error {
+	if err := r.normalize(tx, entity); err != nil {
+		return err
+	}
+
+	if err := r.validate(ctx, *entity); err != nil {
+		return err
+	}
+
+	return r.checkRelated(ctx, tx, []E{*entity}, nil)
+}
+
+// newInsertOne builds the single entity insert with checksum, generated columns and zero value behaviors applied.
+func (r BunCrudRepository[E, T]) newInsertOne(tx bun.IDB, entity *E, columns []string) (*bun.InsertQuery, error) {
+	if err := r.checkColumns("insert", columns...); err != nil {
+		return nil, err
+	}
+
+	if err := r.setChecksum(tx, entity); err != nil {
+		return nil, err
+	}
+
+	generated := generatedColumns(*entity)
+
+	query := tx.NewInsert().
+		Model(entity).
+		Returning(r.returning(strings.Join(withGenerated(columns, generated), ",")))
+
+	if len(generated) > 0 {
+		query.ExcludeColumn(generated...)
+	}
+
+	applyInsertOverrides(query, r.insertOverrides(tx, entity))
+
+	return query, nil
+}
+
+// insertOverrides returns value expressions replacing zero values of the entity by column.
+func (r BunCrudRepository[E, T]) insertOverrides(tx bun.IDB, entity *E) map[string]string {
+	zi, ok := any(*entity).(ZeroValueInserter)
+	if !ok {
+		return nil
+	}
+
+	table := tx.Dialect().Tables().Get(reflect.TypeOf(entity).Elem())
+	strct := reflect.ValueOf(entity).Elem()
+	overrides := make(map[string]string)
+
+	for column, behavior := range zi.InsertBehaviors() {
+		field := table.LookupField(column)
+		if field == nil || !field.HasZeroValue(strct) {
+			continue
+		}
+
+		switch behavior {
+		case DefaultOnZero:
+			overrides[column] = "DEFAULT"
+		case NullOnZero:
+			overrides[column] = "NULL"
+		case InsertZero:
+		}
+	}
+
+	return overrides
+}
+
+func applyInsertOverrides(query *bun.InsertQuery, overrides map[string]string) {
+	for column, expr := range overrides {
+		query.Value(column, expr)
+	}
+}
+
+// insertGroups splits entities by their insert overrides, since overrides apply to every row of a query.
+func (r BunCrudRepository[E, T]) insertGroups(tx bun.IDB, entities []E) ([][]int, []map[string]string) {
+	// entities without zero value behaviors share a single group
+	if _, ok := any(*new(E)).(ZeroValueInserter); !ok {
+		return nil, nil
+	}
+
+	var (
+		groups    [][]int
+		overrides []map[string]string
+		byKey     = make(map[string]int)
+	)
+
+	for i := range entities {
+		o := r.insertOverrides(tx, &entities[i])
+
+		keys := make([]string, 0, len(o))
+		for k, v := range o {
+			keys = append(keys, k+"="+v)
+		}
+
+		sort.Strings(keys)
+
+		key := strings.Join(keys, ",")
+
+		idx, ok := byKey[key]
+		if !ok {
+			idx = len(groups)
+			byKey[key] = idx
+			groups = append(groups, nil)
+			overrides = append(overrides, o)
+		}
+
+		groups[idx] = append(groups[idx], i)
+	}
+
+	return groups, overrides
+}
+
+// insertAll inserts entities with one query per group of equal insert overrides, keeping input order.
+func (r BunCrudRepository[E, T]) insertAll(
+	ctx context.Context,
+	tx bun.IDB,
+	entities []E,
+	columns []string,
+) error {
+	return r.insertAllInto(ctx, tx, "", entities, columns)
+}
+
+// insertAllInto is insertAll into the given table, e.g. a partition, the entity table when empty.
+func (r BunCrudRepository[E, T]) insertAllInto(
+	ctx context.Context,
+	tx bun.IDB,
+	table string,
+	entities []E,
+	columns []string,
+) error {
+	if err := r.checkColumns("insert", columns...); err != nil {
+		return err
+	}
+
+	groups, overrides := r.insertGroups(tx, entities)
+	generated := generatedColumns(*new(E))
+	returning := r.returning(strings.Join(withGenerated(columns, generated), ","))
+
+	if len(groups) <= 1 {
+		query := tx.NewInsert().
+			Model(&entities).
+			Returning(returning)
+
+		if table != "" {
+			query.ModelTableExpr("? AS ?TableAlias", bun.Ident(table))
+		}
+
+		if len(generated) > 0 {
+			query.ExcludeColumn(generated...)
+		}
+
+		if len(overrides) == 1 {
+			applyInsertOverrides(query, overrides[0])
+		}
+
+		_, err := query.Exec(ctx)
+
+		return dbError(err)
+	}
+
+	for i, group := range groups {
+		chunk := make([]E, 0, len(group))
+		for _, idx := range group {
+			chunk = append(chunk, entities[idx])
+		}
+
+		query := tx.NewInsert().
+			Model(&chunk).
+			Returning(returning)
+
+		if table != "" {
+			query.ModelTableExpr("? AS ?TableAlias", bun.Ident(table))
+		}
+
+		if len(generated) > 0 {
+			query.ExcludeColumn(generated...)
+		}
+
+		applyInsertOverrides(query, overrides[i])
+
+		if _, err := query.Exec(ctx); err != nil {
+			return dbError(err)
+		}
+
+		for j, idx := range group {
+			entities[idx] = chunk[j]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/bun-pg-connector"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type TestSerialEnt struct {
+	bun.BaseModel `bun:"table:test_serial_entities,alias:test_serial_entities"`
+
+	ID   int    `bun:"id,pk" json:"id"`
+	Name string `bun:"name" json:"name"`
+	Note string `bun:"note" json:"note"`
+}
+
+func (r TestSerialEnt) EntityName() string {
+	return "TestSerialEnt"
+}
+
+func (r TestSerialEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+func (r TestSerialEnt) InsertBehaviors() map[string]InsertBehavior {
+	return map[string]InsertBehavior{
+		"id":   DefaultOnZero,
+		"note": NullOnZero,
+	}
+}
+
+type TestSerialEntMeta struct {
+	TestSerialEnt
+}
+
+func (r TestSerialEntMeta) Entity() metadata.Entity { return r.TestSerialEnt }
+
+func (r TestSerialEntMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func NewTestSerialEntRepository(
+	connSet bunpgconnector.BunConnSet,
+) BunCrudRepository[TestSerialEnt, bun.Tx] {
+	return BunCrudRepository[TestSerialEnt, bun.Tx]{
+		ConnSet: connSet,
+		Meta:    meta.Parser(TestSerialEntMeta{}),
+	}
+}
+
+func TestBunCrudRepository_CreateOneWithInsertBehaviors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		query  string
+		entity *TestSerialEnt
+	}{
+		{
+			name:   "zero values replaced",
+			query:  `INSERT INTO "test_serial_entities" ("id", "name", "note") VALUES (DEFAULT, 'John', NULL) RETURNING *`,
+			entity: &TestSerialEnt{Name: "John"},
+		},
+		{
+			name:   "non zero values kept",
+			query:  `INSERT INTO "test_serial_entities" ("id", "name", "note") VALUES (5, 'John', 'vip') RETURNING *`,
+			entity: &TestSerialEnt{ID: 5, Name: "John", Note: "vip"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSerialEntRepository(subject.conn)
+
+			subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(tt.query) + "$").
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+			res, err := repo.CreateOne(context.Background(), nil, tt.entity, []string{"*"})
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.NoError(t, err)
+			assert.Equal(t, 5, res.ID)
+		})
+	}
+}
+
+func TestBunCrudRepository_CreateAllWithInsertBehaviors(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestSerialEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "test_serial_entities" ("id", "name", "note") VALUES (DEFAULT, 'a', NULL), (DEFAULT, 'c', NULL)  RETURNING id`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11))
+	subject.conn.Mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "test_serial_entities" ("id", "name", "note") VALUES (7, 'b', NULL) RETURNING id`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	res, err := repo.CreateAll(
+		context.Background(),
+		nil,
+		[]TestSerialEnt{{Name: "a"}, {ID: 7, Name: "b"}, {Name: "c"}},
+		[]string{"id"},
+	)
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{10, 7, 11}, []int{res[0].ID, res[1].ID, res[2].ID})
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/crud-repository/interval"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/crud-repository/spec"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type TestLeaseEnt struct {
+	bun.BaseModel `bun:"table:test_leases,alias:test_leases"`
+
+	ID        int               `bun:"id,pk,autoincrement" json:"id"`
+	TTL       interval.Duration `bun:"ttl,type:interval" json:"ttl"`
+	ExpiresAt time.Time         `bun:"expires_at" json:"expiresAt"`
+}
+
+func (r TestLeaseEnt) EntityName() string {
+	return "TestLeaseEnt"
+}
+
+func (r TestLeaseEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+type TestLeaseEntMeta struct {
+	TestLeaseEnt
+}
+
+func (r TestLeaseEntMeta) Entity() metadata.Entity { return r.TestLeaseEnt }
+
+func (r TestLeaseEntMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func TestBunCrudRepository_Interval(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := BunCrudRepository[TestLeaseEnt, bun.Tx]{
+		ConnSet: subject.conn,
+		Meta:    meta.Parser(TestLeaseEntMeta{}),
+	}
+
+	subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT * FROM "test_leases" WHERE ((test_leases.ttl < '3600000000 microseconds' AND test_leases.expires_at < (now() + '-60000000 microseconds'::interval)))`) + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ttl"}).AddRow(1, "1 day 00:30:00"))
+
+	res, err := repo.FindAll(context.Background(), nil, []string{"*"}, dataspec.NewAnd(
+		dataspec.NewLt("ttl", time.Hour),
+		spec.NewRelativeToNow("expiresAt", spec.OpLess, -time.Minute),
+	))
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, 24*time.Hour+30*time.Minute, res[0].TTL.Std())
+}
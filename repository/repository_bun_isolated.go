@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+// RowError failed row of CreateAllIsolated, Index is the position in the input entities.
+type RowError struct {
+	Index int
+	Err   error
+}
+
+// CreateAllIsolated inserts entities in chunks under savepoints. A chunk failing with a row level constraint
+// violation is rolled back to its savepoint and bisected until the failing rows are isolated, the other rows are
+// inserted. It returns the inserted entities and errors of the failed rows. Other errors abort the whole insert.
+// Without tx the insert runs in its own transaction, committed when only row level violations occurred.
+func (r BunCrudRepository[E, T]) CreateAllIsolated(
+	ctx context.Context,
+	tx bun.IDB,
+	entities []E,
+	columns []string,
+	chunkSize int,
+) ([]E, []RowError, error) {
+	if chunkSize <= 0 {
+		return nil, nil, fmt.Errorf("create all isolated: %w", ErrInvalidBatchSize)
+	}
+
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create all isolated: %w", err)
+	}
+
+	if err := r.normalizeAll(tx, entities); err != nil {
+		return nil, nil, fmt.Errorf("create all isolated: %w", err)
+	}
+
+	if err := r.validate(ctx, entities...); err != nil {
+		return nil, nil, fmt.Errorf("create all isolated: %w", err)
+	}
+
+	if err := r.checkRelated(ctx, tx, entities, nil); err != nil {
+		return nil, nil, fmt.Errorf("create all isolated: %w", err)
+	}
+
+	for i := range entities {
+		if err := r.setChecksum(tx, &entities[i]); err != nil {
+			return nil, nil, fmt.Errorf("create all isolated: %w", err)
+		}
+	}
+
+	var failed []RowError
+
+	err = tx.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		failed = nil
+
+		for from := 0; from < len(entities); from += chunkSize {
+			to := min(from+chunkSize, len(entities))
+
+			if err := r.insertIsolated(ctx, tx, entities, from, to, columns, &failed); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("create all isolated: %w", err)
+	}
+
+	created := make([]E, 0, len(entities)-len(failed))
+	next := 0
+
+	for i, v := range entities {
+		if next < len(failed) && failed[next].Index == i {
+			next++
+
+			continue
+		}
+
+		created = append(created, v)
+	}
+
+	return created, failed, nil
+}
+
+// insertIsolated inserts entities[from:to] under a savepoint, bisecting the range on row level violations.
+func (r BunCrudRepository[E, T]) insertIsolated(
+	ctx context.Context,
+	tx bun.Tx,
+	entities []E,
+	from, to int,
+	columns []string,
+	failed *[]RowError,
+) error {
+	err := tx.RunInTx(ctx, nil, func(ctx context.Context, sp bun.Tx) error {
+		return r.insertAll(ctx, sp, entities[from:to], columns)
+	})
+	if err == nil || !isRowViolation(err) {
+		return err //nolint:wrapcheck
+	}
+
+	if to-from == 1 {
+		*failed = append(*failed, RowError{Index: from, Err: err})
+
+		return nil
+	}
+
+	mid := from + (to-from)/2
+
+	if err := r.insertIsolated(ctx, tx, entities, from, mid, columns, failed); err != nil {
+		return err
+	}
+
+	return r.insertIsolated(ctx, tx, entities, mid, to, columns, failed)
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPgError struct {
+	code string
+}
+
+func (r testPgError) Error() string {
+	return "SQLSTATE " + r.code
+}
+
+func (r testPgError) SQLState() string {
+	return r.code
+}
+
+func TestBunCrudRepository_CreateAllIsolated(t *testing.T) {
+	t.Parallel()
+
+	var (
+		errUnique = testPgError{code: "23505"}
+		errDB     = errors.New("connection lost")
+	)
+
+	insert := func(values string) string {
+		return "^" + regexp.QuoteMeta(`INSERT INTO "test_simple_entities" ("id", "name") VALUES `+values+` RETURNING *`) + "$"
+	}
+
+	savepoint := func(conn *MockBunConnSet, values string, err error) {
+		conn.Mock.ExpectExec("^SAVEPOINT ").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		if err != nil {
+			conn.Mock.ExpectQuery(insert(values)).WillReturnError(err)
+			conn.Mock.ExpectExec("^ROLLBACK TO SAVEPOINT ").WillReturnResult(sqlmock.NewResult(0, 0))
+
+			return
+		}
+
+		conn.Mock.ExpectQuery(insert(values)).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+		conn.Mock.ExpectExec("^RELEASE SAVEPOINT ").WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		expected func(t *testing.T, created []TestSimpleEnt, failed []RowError, err error)
+	}{
+		{
+			name: "create all isolated bisects failing chunk",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectBegin()
+				savepoint(conn, "(1, 'a'), (2, 'b'), (3, 'c')", errUnique)
+				savepoint(conn, "(1, 'a')", nil)
+				savepoint(conn, "(2, 'b'), (3, 'c')", errUnique)
+				savepoint(conn, "(2, 'b')", errUnique)
+				savepoint(conn, "(3, 'c')", nil)
+				savepoint(conn, "(4, 'd')", nil)
+				conn.Mock.ExpectCommit()
+			},
+			expected: func(t *testing.T, created []TestSimpleEnt, failed []RowError, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, []TestSimpleEnt{{ID: 1, Name: "a"}, {ID: 3, Name: "c"}, {ID: 4, Name: "d"}}, created)
+				assert.Len(t, failed, 1)
+				assert.Equal(t, 1, failed[0].Index)
+				assert.ErrorIs(t, failed[0].Err, errUnique)
+			},
+		},
+		{
+			name: "create all isolated aborts on other errors",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectBegin()
+				savepoint(conn, "(1, 'a'), (2, 'b'), (3, 'c')", errDB)
+				conn.Mock.ExpectRollback()
+			},
+			expected: func(t *testing.T, created []TestSimpleEnt, failed []RowError, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, errDB)
+				assert.Empty(t, created)
+				assert.Empty(t, failed)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			entities := []TestSimpleEnt{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}, {ID: 4, Name: "d"}}
+			created, failed, err := repo.CreateAllIsolated(context.Background(), nil, entities, []string{"*"}, 3)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+
+			tt.expected(t, created, failed, err)
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// Cursor keyset position of the last row of a page, values of the sort columns then of the primary key,
+// and whether each is ordered descending. An empty cursor starts at the first page.
+type Cursor struct {
+	Values []any
+	// Desc, when set, must match the sort of the page, so a cursor isn't used with another sort.
+	Desc []bool
+}
+
+// IsEmpty reports whether the cursor is the start of the first page.
+func (c Cursor) IsEmpty() bool {
+	return len(c.Values) == 0
+}
+
+// KeysetResult page rows of FindPageAfter, Next is the cursor of the following page, empty on the last page.
+type KeysetResult[E any] struct {
+	Items []E
+	Next  Cursor
+}
+
+// keysetColumn column of the keyset, ordered descending when desc is set.
+type keysetColumn struct {
+	name string
+	desc bool
+}
+
+// FindPageAfter finds up to size rows matching spec after cursor in sort order, seeking by the sort columns
+// and the primary key instead of skipping rows with OFFSET, so deep pages cost as much as the first one.
+// The primary key breaks ties of sort, composite keys included. Sort columns must not be NULL, rows with
+// NULL keys aren't found after a cursor, and raw sorters aren't supported. Cursors of another sort fail
+// with ErrInvalidValue.
+func (r BunCrudRepository[E, T]) FindPageAfter(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+	cursor Cursor,
+	size int,
+	sort dataset.Sorter,
+) (KeysetResult[E], error) {
+	page, err := r.seekPage(ctx, tx, "find page after", columns, spec, cursor, size, sort, false)
+	if err != nil {
+		return KeysetResult[E]{Items: page.items}, fmt.Errorf("find page after: %w", err)
+	}
+
+	res := KeysetResult[E]{Items: page.items}
+	if page.more {
+		res.Next = page.cursors[len(page.cursors)-1]
+	}
+
+	return res, nil
+}
+
+// keysetPage rows of a keyset seek in sort order with their cursors, more is set when rows follow them
+// in the seek direction.
+type keysetPage[E any] struct {
+	items   []E
+	cursors []Cursor
+	more    bool
+}
+
+// seekPage finds up to size rows matching spec after cursor in sort order, or before it when backward is set.
+func (r BunCrudRepository[E, T]) seekPage(
+	ctx context.Context,
+	tx bun.IDB,
+	op string,
+	columns []string,
+	spec dataset.Specifier,
+	cursor Cursor,
+	size int,
+	sort dataset.Sorter,
+	backward bool,
+) (keysetPage[E], error) {
+	page := keysetPage[E]{items: make([]E, 0)}
+
+	if size <= 0 {
+		return page, fmt.Errorf("size %d: %w", size, ErrInvalidValue)
+	}
+
+	if err := r.checkColumns(op, columns...); err != nil {
+		return page, err
+	}
+
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return page, err
+	}
+
+	table := tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem())
+
+	keyset, err := r.keyset(table, sort)
+	if err != nil {
+		return page, err
+	}
+
+	if err := checkCursor(cursor, keyset); err != nil {
+		return page, err
+	}
+
+	for _, k := range keyset {
+		if len(columns) > 0 && !slices.Contains(columns, k.name) {
+			columns = append(columns[:len(columns):len(columns)], k.name)
+		}
+	}
+
+	// seeking backward is seeking forward in the reverse order
+	seekset := keyset
+	if backward {
+		seekset = make([]keysetColumn, len(keyset))
+		for i, k := range keyset {
+			seekset[i] = keysetColumn{name: k.name, desc: !k.desc}
+		}
+	}
+
+	var items []E
+
+	query := tx.
+		NewSelect().
+		Model(&items).
+		Column(columns...)
+
+	r.applySpec(query, spec)
+
+	if !cursor.IsEmpty() {
+		seek, args := keysetSeek(seekset, r.bindValues(cursor.Values))
+		query.Where(seek, args...)
+	}
+
+	for _, k := range seekset {
+		if k.desc {
+			query.OrderExpr("?TableAlias.? DESC", bun.Ident(k.name))
+		} else {
+			query.OrderExpr("?TableAlias.? ASC", bun.Ident(k.name))
+		}
+	}
+
+	if err := query.Limit(size + 1).Scan(ctx); err != nil {
+		return page, dbError(err)
+	}
+
+	if len(items) > size {
+		items = items[:size]
+		page.more = true
+	}
+
+	if backward {
+		slices.Reverse(items)
+	}
+
+	page.items = items
+	page.cursors = make([]Cursor, len(items))
+
+	for i := range items {
+		page.cursors[i] = keysetCursor(table, keyset, &items[i])
+	}
+
+	r.localize(page.items)
+
+	if err := r.afterScan(ctx, page.items); err != nil {
+		return page, err
+	}
+
+	return page, nil
+}
+
+// keyset returns the sort columns followed by the primary key columns not sorted by, the primary key
+// in the direction of the last sort column.
+func (r BunCrudRepository[E, T]) keyset(table *schema.Table, sort dataset.Sorter) ([]keysetColumn, error) {
+	if len(table.PKs) == 0 {
+		return nil, ErrNoPrimaryKey
+	}
+
+	var keyset []keysetColumn
+
+	if sort != nil && !sort.IsEmpty() {
+		if _, raw := sort.(RawSorter); raw {
+			return nil, fmt.Errorf("raw sorter: %w", ErrInvalidValue)
+		}
+
+		orderBy := sort.OrderBy(r.specMeta())
+		known := r.Meta.PersistencePresenterMapping()
+
+		for _, item := range strings.Split(orderBy, ",") {
+			k, ok := keysetItem(item)
+			if !ok || table.LookupField(k.name) == nil || !validOrderItem(item, r.specMeta().PersistenceName(), known) {
+				return nil, fmt.Errorf("order %s: %w", orderBy, ErrInvalidValue)
+			}
+
+			keyset = append(keyset, k)
+		}
+	}
+
+	desc := len(keyset) > 0 && keyset[len(keyset)-1].desc
+
+	for _, f := range table.PKs {
+		if !slices.ContainsFunc(keyset, func(k keysetColumn) bool { return k.name == f.Name }) {
+			keyset = append(keyset, keysetColumn{name: f.Name, desc: desc})
+		}
+	}
+
+	return keyset, nil
+}
+
+// checkCursor checks a non-empty cursor has a value for every keyset column, and the keyset directions
+// when it has them.
+func checkCursor(cursor Cursor, keyset []keysetColumn) error {
+	if cursor.IsEmpty() {
+		return nil
+	}
+
+	if len(cursor.Values) != len(keyset) {
+		return fmt.Errorf("cursor of %d values for %d keys: %w", len(cursor.Values), len(keyset), ErrInvalidValue)
+	}
+
+	if cursor.Desc == nil {
+		return nil
+	}
+
+	if !slices.Equal(cursor.Desc, keysetDesc(keyset)) {
+		return fmt.Errorf("cursor of another sort: %w", ErrInvalidValue)
+	}
+
+	return nil
+}
+
+// keysetDesc returns whether each keyset column is ordered descending.
+func keysetDesc(keyset []keysetColumn) []bool {
+	desc := make([]bool, len(keyset))
+	for i, k := range keyset {
+		desc[i] = k.desc
+	}
+
+	return desc
+}
+
+// keysetItem parses a column [ASC|DESC] order item, NULLS orderings aren't keyset columns.
+func keysetItem(item string) (keysetColumn, bool) {
+	tokens := strings.Fields(item)
+	if len(tokens) == 0 || len(tokens) > 2 {
+		return keysetColumn{}, false
+	}
+
+	column := strings.ReplaceAll(tokens[0], `"`, "")
+	if _, name, ok := strings.Cut(column, "."); ok {
+		column = name
+	}
+
+	if len(tokens) == 1 {
+		return keysetColumn{name: column}, true
+	}
+
+	switch strings.ToUpper(tokens[1]) {
+	case "ASC":
+		return keysetColumn{name: column}, true
+	case "DESC":
+		return keysetColumn{name: column, desc: true}, true
+	default:
+		return keysetColumn{}, false
+	}
+}
+
+// keysetSeek returns the condition of rows after values in keyset order, a row comparison when all columns
+// are ordered the same way, so an index on them is used, otherwise its expansion by column.
+func keysetSeek(keyset []keysetColumn, values []any) (string, []any) {
+	idents := make([]any, len(keyset))
+	keys := make([]string, len(keyset))
+
+	for i, k := range keyset {
+		idents[i] = bun.Ident(k.name)
+		keys[i] = "?TableAlias.?"
+	}
+
+	uniform := !slices.ContainsFunc(keyset, func(k keysetColumn) bool { return k.desc != keyset[0].desc })
+	if uniform {
+		op := ">"
+		if keyset[0].desc {
+			op = "<"
+		}
+
+		return "(" + strings.Join(keys, ", ") + ") " + op + " (?)", append(idents, bun.In(values))
+	}
+
+	var (
+		terms []string
+		args  []any
+	)
+
+	for i, k := range keyset {
+		var term []string
+
+		for j := 0; j < i; j++ {
+			term = append(term, "?TableAlias.? = ?")
+			args = append(args, idents[j], values[j])
+		}
+
+		op := ">"
+		if k.desc {
+			op = "<"
+		}
+
+		term = append(term, "?TableAlias.? "+op+" ?")
+		args = append(args, idents[i], values[i])
+
+		terms = append(terms, "("+strings.Join(term, " AND ")+")")
+	}
+
+	return "(" + strings.Join(terms, " OR ") + ")", args
+}
+
+// keysetCursor returns the keyset values of entity.
+func keysetCursor[E any](table *schema.Table, keyset []keysetColumn, entity *E) Cursor {
+	v := reflect.ValueOf(entity).Elem()
+	cursor := Cursor{Values: make([]any, len(keyset)), Desc: keysetDesc(keyset)}
+
+	for i, k := range keyset {
+		cursor.Values[i] = table.LookupField(k.name).Value(v).Interface()
+	}
+
+	return cursor
+}
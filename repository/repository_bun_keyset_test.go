@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_FindPageAfter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(conn *MockBunConnSet)
+		cursor   Cursor
+		size     int
+		sort     dataset.Sorter
+		expected KeysetResult[TestSimpleEnt]
+		err      error
+	}{
+		{
+			name: "first page",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" WHERE (test_simple_entities.name != 'x') ORDER BY "test_simple_entities"."id" ASC LIMIT 3`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b").AddRow(3, "c"))
+			},
+			size: 2,
+			expected: KeysetResult[TestSimpleEnt]{
+				Items: []TestSimpleEnt{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}},
+				Next:  Cursor{Values: []any{2}, Desc: []bool{false}},
+			},
+		},
+		{
+			name: "last page",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" WHERE (test_simple_entities.name != 'x') AND (("test_simple_entities"."id") > (2)) ORDER BY "test_simple_entities"."id" ASC LIMIT 3`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "c"))
+			},
+			cursor: Cursor{Values: []any{2}},
+			size:   2,
+			expected: KeysetResult[TestSimpleEnt]{
+				Items: []TestSimpleEnt{{ID: 3, Name: "c"}},
+			},
+		},
+		{
+			name: "sorted descending",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" WHERE (test_simple_entities.name != 'x') AND (("test_simple_entities"."name", "test_simple_entities"."id") < ('b', 2)) ORDER BY "test_simple_entities"."name" DESC, "test_simple_entities"."id" DESC LIMIT 2`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(5, "a").AddRow(1, "a"))
+			},
+			cursor: Cursor{Values: []any{"b", 2}, Desc: []bool{true, true}},
+			size:   1,
+			sort:   NewSorter().WithSort("name", "desc"),
+			expected: KeysetResult[TestSimpleEnt]{
+				Items: []TestSimpleEnt{{ID: 5, Name: "a"}},
+				Next:  Cursor{Values: []any{"a", 5}, Desc: []bool{true, true}},
+			},
+		},
+		{
+			name:     "cursor of other keyset",
+			mock:     func(conn *MockBunConnSet) {},
+			cursor:   Cursor{Values: []any{"b", 2}},
+			size:     2,
+			expected: KeysetResult[TestSimpleEnt]{Items: []TestSimpleEnt{}},
+			err:      ErrInvalidValue,
+		},
+		{
+			name:     "cursor of other direction",
+			mock:     func(conn *MockBunConnSet) {},
+			cursor:   Cursor{Values: []any{"b", 2}, Desc: []bool{false, false}},
+			size:     2,
+			sort:     NewSorter().WithSort("name", "desc"),
+			expected: KeysetResult[TestSimpleEnt]{Items: []TestSimpleEnt{}},
+			err:      ErrInvalidValue,
+		},
+		{
+			name:     "invalid size",
+			mock:     func(conn *MockBunConnSet) {},
+			expected: KeysetResult[TestSimpleEnt]{Items: []TestSimpleEnt{}},
+			err:      ErrInvalidValue,
+		},
+		{
+			name:     "nulls ordering",
+			mock:     func(conn *MockBunConnSet) {},
+			size:     2,
+			sort:     NewSorter().WithSort("name", "asc nulls last"),
+			expected: KeysetResult[TestSimpleEnt]{Items: []TestSimpleEnt{}},
+			err:      ErrInvalidValue,
+		},
+		{
+			name:     "raw sorter",
+			mock:     func(conn *MockBunConnSet) {},
+			size:     2,
+			sort:     RawSort(Safe("lower(name)")),
+			expected: KeysetResult[TestSimpleEnt]{Items: []TestSimpleEnt{}},
+			err:      ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+			tt.mock(subject.conn)
+
+			res, err := repo.FindPageAfter(context.Background(), nil, nil, dataspec.NewNotEqual("name", "x"), tt.cursor, tt.size, tt.sort)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, res)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// testOrderedSort orders by the items in order, unlike Sort.
+type testOrderedSort []string
+
+func (r testOrderedSort) OrderBy(_ metadata.Meta) string {
+	return strings.Join(r, ",")
+}
+
+func (r testOrderedSort) IsEmpty() bool {
+	return len(r) == 0
+}
+
+func TestBunCrudRepository_FindPageAfterCompositeKey(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestComplexEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_complex_entities"."complex_name", "test_complex_entities"."first_id", "test_complex_entities"."second_id" FROM "test_complex_entities" WHERE ((("test_complex_entities"."complex_name" < 'b') OR ("test_complex_entities"."complex_name" = 'b' AND "test_complex_entities"."first_id" > 1) OR ("test_complex_entities"."complex_name" = 'b' AND "test_complex_entities"."first_id" = 1 AND "test_complex_entities"."second_id" > 2))) ORDER BY "test_complex_entities"."complex_name" DESC, "test_complex_entities"."first_id" ASC, "test_complex_entities"."second_id" ASC LIMIT 2`) + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"complex_name", "first_id", "second_id"}).AddRow("b", 1, 3).AddRow("a", 0, 1))
+
+	sort := testOrderedSort{"complex_name DESC", "first_id ASC"}
+
+	res, err := repo.FindPageAfter(context.Background(), nil, []string{"complex_name"}, nil, Cursor{Values: []any{"b", 1, 2}}, 1, sort)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []TestComplexEnt{{FirstID: 1, SecondID: 3, Name: "b"}}, res.Items)
+	assert.Equal(t, Cursor{Values: []any{"b", 1, 3}, Desc: []bool{true, false, false}}, res.Next)
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+}
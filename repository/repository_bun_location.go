@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aso779/crud-repository/interval"
+	"github.com/uptrace/bun"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// bindValues prepares spec and update values for binding: time.Duration values become intervals and,
+// when Location is set, times are rendered in it with an explicit offset, so timestamptz columns
+// compare the same instant and timestamp columns compare the location wall clock.
+func (r BunCrudRepository[E, T]) bindValues(values []any) []any {
+	var res []any
+
+	for i, v := range values {
+		bound, ok := r.convertValue(v)
+		if !ok {
+			continue
+		}
+
+		// copy on first conversion, values are returned as is when nothing converts
+		if res == nil {
+			res = slices.Clone(values)
+		}
+
+		res[i] = bound
+	}
+
+	if res == nil {
+		return values
+	}
+
+	return res
+}
+
+func (r BunCrudRepository[E, T]) bindValue(v any) any {
+	if bound, ok := r.convertValue(v); ok {
+		return bound
+	}
+
+	return v
+}
+
+// convertValue returns the bound form of v and whether it differs from v.
+func (r BunCrudRepository[E, T]) convertValue(v any) (any, bool) {
+	if d, ok := v.(time.Duration); ok {
+		return interval.Duration(d), true
+	}
+
+	if r.Location == nil {
+		return nil, false
+	}
+
+	switch tv := v.(type) {
+	case time.Time:
+		return r.formatTime(tv), true
+	case *time.Time:
+		if tv != nil {
+			return r.formatTime(*tv), true
+		}
+	case bun.NullTime:
+		if !tv.IsZero() {
+			return r.formatTime(tv.Time), true
+		}
+	}
+
+	return nil, false
+}
+
+func (r BunCrudRepository[E, T]) formatTime(t time.Time) string {
+	return t.In(r.Location).Format("2006-01-02 15:04:05.999999-07:00")
+}
+
+// localize converts scanned time fields of dest, including nested relations, to the repository location.
+// Fields tagged type:timestamp hold a wall clock and are reinterpreted in the location instead.
+func (r BunCrudRepository[E, T]) localize(dest any) {
+	if r.Location == nil {
+		return
+	}
+
+	localizeValue(reflect.ValueOf(dest), r.Location, false)
+}
+
+func localizeValue(v reflect.Value, loc *time.Location, wallClock bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			localizeValue(v.Elem(), loc, wallClock)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			localizeValue(v.Index(i), loc, wallClock)
+		}
+	case reflect.Struct:
+		if !v.CanSet() {
+			return
+		}
+
+		if v.Type() == timeType {
+			t := v.Interface().(time.Time)
+
+			switch {
+			case t.IsZero():
+			case wallClock:
+				v.Set(reflect.ValueOf(time.Date(
+					t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc,
+				)))
+			default:
+				v.Set(reflect.ValueOf(t.In(loc)))
+			}
+
+			return
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Type().Field(i); f.IsExported() {
+				localizeValue(v.Field(i), loc, wallClock || isWallClock(f.Tag.Get("bun")))
+			}
+		}
+	default:
+	}
+}
+
+// isWallClock reports whether the bun tag declares a timestamp without time zone column.
+func isWallClock(tag string) bool {
+	for _, opt := range strings.Split(tag, ",") {
+		switch strings.ToLower(strings.TrimSpace(opt)) {
+		case "type:timestamp", "type:timestamp without time zone":
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/crud-repository/spec"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_Location(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("UTC+3", 3*3600)
+
+	tests := []struct {
+		name     string
+		location *time.Location
+		spec     dataset.Specifier
+		query    string
+		expected time.Time
+	}{
+		{
+			name:     "without location",
+			spec:     dataspec.NewGte("validFrom", testDate(3)),
+			query:    `SELECT * FROM "test_prices" WHERE (test_prices.valid_from >= '2024-03-01 00:00:00+00:00')`,
+			expected: testDate(2),
+		},
+		{
+			name:     "spec and scanned values",
+			location: loc,
+			spec:     dataspec.NewGte("validFrom", testDate(3)),
+			query:    `SELECT * FROM "test_prices" WHERE (test_prices.valid_from >= '2024-03-01 03:00:00+03:00')`,
+			expected: testDate(2).In(loc),
+		},
+		{
+			name:     "at time zone",
+			location: loc,
+			spec:     spec.NewAtTimeZone("validFrom", loc, spec.OpLess, testDate(3)),
+			query:    `SELECT * FROM "test_prices" WHERE ((test_prices.valid_from AT TIME ZONE 'UTC+3') < '2024-03-01 03:00:00')`,
+			expected: testDate(2).In(loc),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestPriceEntRepository(subject.conn)
+			repo.Location = tt.location
+
+			subject.conn.Mock.ExpectQuery(regexp.QuoteMeta(tt.query)).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "valid_from"}).AddRow(1, testDate(2)))
+
+			res, err := repo.FindAll(context.Background(), nil, []string{"*"}, tt.spec)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.NoError(t, err)
+			assert.Len(t, res, 1)
+			assert.Equal(t, tt.expected.String(), res[0].ValidFrom.String())
+			assert.True(t, res[0].ValidTo.IsZero())
+		})
+	}
+}
+
+func TestLocalizeValue(t *testing.T) {
+	t.Parallel()
+
+	type event struct {
+		At       time.Time  `bun:"at"`
+		Local    time.Time  `bun:"local,type:timestamp"`
+		Nullable *time.Time `bun:"nullable"`
+		Zero     time.Time  `bun:"zero"`
+	}
+
+	loc := time.FixedZone("UTC+3", 3*3600)
+	at := testDate(3)
+	events := []event{{At: at, Local: at, Nullable: &at}}
+
+	localizeValue(reflect.ValueOf(events), loc, false)
+
+	assert.Equal(t, "2024-03-01 03:00:00 +0300 UTC+3", events[0].At.String())
+	assert.Equal(t, "2024-03-01 00:00:00 +0300 UTC+3", events[0].Local.String())
+	assert.Equal(t, "2024-03-01 03:00:00 +0300 UTC+3", events[0].Nullable.String())
+	assert.True(t, events[0].Zero.IsZero())
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+// LockStrength row level lock of locking reads.
+type LockStrength string
+
+const (
+	LockForUpdate      LockStrength = "UPDATE"
+	LockForNoKeyUpdate LockStrength = "NO KEY UPDATE"
+	LockForShare       LockStrength = "SHARE"
+	LockForKeyShare    LockStrength = "KEY SHARE"
+)
+
+// LockWait behavior of locking reads on rows locked by other transactions.
+type LockWait string
+
+const (
+	// LockWaitBlock waits for the other transactions to release the rows.
+	LockWaitBlock LockWait = ""
+	// LockNoWait fails the read when a row is locked.
+	LockNoWait LockWait = "NOWAIT"
+	// LockSkipLocked skips locked rows, e.g. for job queues.
+	LockSkipLocked LockWait = "SKIP LOCKED"
+)
+
+type lockCtxKey struct{}
+
+// rowLock lock of the context.
+type rowLock struct {
+	strength LockStrength
+	wait     LockWait
+}
+
+// WithLock makes FindOne, FindOneByPk and FindAll lock the rows they read until the end of the transaction,
+// e.g. WithLock(ctx, LockForUpdate, LockNoWait). Only rows of the repository table are locked, not joined ones.
+// Locking reads need a transaction, they fail with ErrTxRequired for a nil tx.
+func WithLock(ctx context.Context, strength LockStrength, wait LockWait) context.Context {
+	return context.WithValue(ctx, lockCtxKey{}, rowLock{strength: strength, wait: wait})
+}
+
+// Locking reports whether reads of the context lock rows, see WithLock. Decorators serving reads from caches
+// must read through to the database then.
+func Locking(ctx context.Context) bool {
+	_, ok := ctx.Value(lockCtxKey{}).(rowLock)
+
+	return ok
+}
+
+// rowLock returns the lock of the context, nil when the call doesn't lock.
+func (r BunCrudRepository[E, T]) rowLock(ctx context.Context, tx bun.IDB) (*rowLock, error) {
+	lock, ok := ctx.Value(lockCtxKey{}).(rowLock)
+	if !ok {
+		return nil, nil
+	}
+
+	switch lock.strength {
+	case LockForUpdate, LockForNoKeyUpdate, LockForShare, LockForKeyShare:
+	default:
+		return nil, fmt.Errorf("lock %s: %w", lock.strength, ErrInvalidValue)
+	}
+
+	switch lock.wait {
+	case LockWaitBlock, LockNoWait, LockSkipLocked:
+	default:
+		return nil, fmt.Errorf("lock %s: %w", lock.wait, ErrInvalidValue)
+	}
+
+	if tx == nil {
+		return nil, fmt.Errorf("lock: %w", ErrTxRequired)
+	}
+
+	return &lock, nil
+}
+
+// apply adds the locking clause to the query.
+func (l *rowLock) apply(query *bun.SelectQuery) {
+	if l == nil {
+		return
+	}
+
+	clause := string(l.strength) + " OF ?TableAlias"
+	if l.wait != LockWaitBlock {
+		clause += " " + string(l.wait)
+	}
+
+	query.For(clause)
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+func TestBunCrudRepository_Lock(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		mock func(conn *MockBunConnSet)
+		call func(ctx context.Context, repo *TestSimpleEntBunRepo, tx bun.IDB) error
+		err  error
+	}{
+		{
+			name: "find one for update",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" WHERE ((test_simple_entities.id = 1)) FOR UPDATE OF "test_simple_entities"`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+			},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo, tx bun.IDB) error {
+				_, err := repo.FindOneByPk(WithLock(ctx, LockForUpdate, LockWaitBlock), tx, nil, metadata.PrimaryKey{"id": 1})
+
+				return err //nolint:wrapcheck
+			},
+		},
+		{
+			name: "find one for share nowait",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" WHERE (test_simple_entities.name = 'a') FOR SHARE OF "test_simple_entities" NOWAIT`) + "$").
+					WillReturnError(testPgError{code: "55P03"})
+			},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo, tx bun.IDB) error {
+				_, err := repo.FindOne(WithLock(ctx, LockForShare, LockNoWait), tx, nil, dataspec.NewEqual("name", "a"))
+
+				return err //nolint:wrapcheck
+			},
+			err: testPgError{code: "55P03"},
+		},
+		{
+			name: "find all skip locked",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" WHERE (test_simple_entities.name = 'a') FOR NO KEY UPDATE OF "test_simple_entities" SKIP LOCKED`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "a"))
+			},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo, tx bun.IDB) error {
+				_, err := repo.FindAll(WithLock(ctx, LockForNoKeyUpdate, LockSkipLocked), tx, nil, dataspec.NewEqual("name", "a"))
+
+				return err //nolint:wrapcheck
+			},
+		},
+		{
+			name: "lock without tx",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo, _ bun.IDB) error {
+				_, err := repo.FindAll(WithLock(ctx, LockForUpdate, LockWaitBlock), nil, nil, dataspec.NewEqual("name", "a"))
+
+				return err //nolint:wrapcheck
+			},
+			err: ErrTxRequired,
+		},
+		{
+			name: "unknown lock strength",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo, tx bun.IDB) error {
+				_, err := repo.FindOne(WithLock(ctx, LockStrength("EXCLUSIVE"), LockWaitBlock), tx, nil, dataspec.NewEqual("name", "a"))
+
+				return err //nolint:wrapcheck
+			},
+			err: ErrInvalidValue,
+		},
+		{
+			name: "unknown lock wait",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo, tx bun.IDB) error {
+				_, err := repo.FindOne(WithLock(ctx, LockForUpdate, LockWait("WAIT 5")), tx, nil, dataspec.NewEqual("name", "a"))
+
+				return err //nolint:wrapcheck
+			},
+			err: ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+
+			subject.conn.Mock.ExpectBegin()
+			tt.mock(subject.conn)
+			subject.conn.Mock.ExpectRollback()
+
+			ctx := context.Background()
+
+			tx, err := subject.conn.WritePool().BeginTx(ctx, nil)
+			assert.NoError(t, err)
+
+			err = tt.call(ctx, repo, tx)
+			assert.ErrorIs(t, err, tt.err)
+
+			assert.NoError(t, tx.Rollback())
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLocking(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, Locking(context.Background()))
+	assert.True(t, Locking(WithLock(context.Background(), LockForUpdate, LockNoWait)))
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/uptrace/bun"
+)
+
+// Normalizer canonicalizes a string column value before it is written.
+type Normalizer func(string) (string, error)
+
+// Normalized entity declares normalizers per persistence column of string fields, applied in order on
+// inserts and updates, including values of UpdateBySpec, UpdateEachByPk and UpdateOrCreate.
+type Normalized interface {
+	Normalizers() map[string][]Normalizer
+}
+
+// Trim removes leading and trailing white space.
+func Trim(v string) (string, error) {
+	return strings.TrimSpace(v), nil
+}
+
+// Lower lowercases the value, e.g. emails.
+func Lower(v string) (string, error) {
+	return strings.ToLower(v), nil
+}
+
+// E164 formats a phone number as +<country code><number>, dropping spaces, dots, dashes and parentheses.
+// Numbers must be in international format, starting with + or 00. Empty values are kept.
+func E164(v string) (string, error) {
+	if v == "" {
+		return v, nil
+	}
+
+	digits := make([]rune, 0, len(v))
+	international := false
+
+	for i, c := range strings.TrimSpace(v) {
+		switch {
+		case c == '+' && i == 0:
+			international = true
+		case unicode.IsDigit(c):
+			digits = append(digits, c)
+		case strings.ContainsRune(" .-()", c):
+		default:
+			return v, fmt.Errorf("phone %q: %w", v, ErrInvalidValue)
+		}
+	}
+
+	if !international && len(digits) > 2 && digits[0] == '0' && digits[1] == '0' {
+		international = true
+		digits = digits[2:]
+	}
+
+	if !international || len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return v, fmt.Errorf("phone %q: %w", v, ErrInvalidValue)
+	}
+
+	return "+" + string(digits), nil
+}
+
+// normalize applies normalizers of Normalized entities to their fields.
+func (r BunCrudRepository[E, T]) normalize(tx bun.IDB, entities ...*E) error {
+	n, ok := any(*new(E)).(Normalized)
+	if !ok {
+		return nil
+	}
+
+	table := tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem())
+
+	for column, normalizers := range n.Normalizers() {
+		field := table.LookupField(column)
+		if field == nil {
+			return fmt.Errorf("normalize %s: %w", column, ErrUnknownColumn)
+		}
+
+		for _, entity := range entities {
+			v := field.Value(reflect.ValueOf(entity).Elem())
+			if v.Kind() == reflect.Pointer {
+				if v.IsNil() {
+					continue
+				}
+
+				v = v.Elem()
+			}
+
+			if v.Kind() != reflect.String {
+				return fmt.Errorf("normalize %s: %w", column, ErrInvalidValue)
+			}
+
+			s, err := applyNormalizers(v.String(), normalizers)
+			if err != nil {
+				return fmt.Errorf("normalize %s: %w", column, err)
+			}
+
+			v.SetString(s)
+		}
+	}
+
+	return nil
+}
+
+// normalizeValues returns values with string values of normalized columns normalized.
+// Keys are presenter or persistence names.
+func (r BunCrudRepository[E, T]) normalizeValues(values map[string]any) (map[string]any, error) {
+	n, ok := any(*new(E)).(Normalized)
+	if !ok {
+		return values, nil
+	}
+
+	normalizers := n.Normalizers()
+	res := make(map[string]any, len(values))
+
+	for k, v := range values {
+		res[k] = v
+
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+
+		if column := r.persistenceName(k); len(normalizers[column]) > 0 {
+			normalized, err := applyNormalizers(s, normalizers[column])
+			if err != nil {
+				return nil, fmt.Errorf("normalize %s: %w", column, err)
+			}
+
+			res[k] = normalized
+		}
+	}
+
+	return res, nil
+}
+
+func applyNormalizers(v string, normalizers []Normalizer) (string, error) {
+	for _, fn := range normalizers {
+		var err error
+
+		if v, err = fn(v); err != nil {
+			return v, err
+		}
+	}
+
+	return v, nil
+}
+
+// normalizeAll normalizes entities of a slice in place.
+func (r BunCrudRepository[E, T]) normalizeAll(tx bun.IDB, entities []E) error {
+	ptrs := make([]*E, len(entities))
+	for i := range entities {
+		ptrs[i] = &entities[i]
+	}
+
+	return r.normalize(tx, ptrs...)
+}
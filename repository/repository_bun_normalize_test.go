@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/bun-pg-connector"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type TestNormalizedEnt struct {
+	bun.BaseModel `bun:"table:test_normalized_entities,alias:test_normalized_entities"`
+
+	ID    int     `bun:"id,pk" json:"id"`
+	Email string  `bun:"email" json:"email"`
+	Phone *string `bun:"phone" json:"phone"`
+}
+
+func (r TestNormalizedEnt) EntityName() string {
+	return "TestNormalizedEnt"
+}
+
+func (r TestNormalizedEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+func (r TestNormalizedEnt) Normalizers() map[string][]Normalizer {
+	return map[string][]Normalizer{
+		"email": {Trim, Lower},
+		"phone": {E164},
+	}
+}
+
+type TestNormalizedEntMeta struct {
+	TestNormalizedEnt
+}
+
+func (r TestNormalizedEntMeta) Entity() metadata.Entity { return r.TestNormalizedEnt }
+
+func (r TestNormalizedEntMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func NewTestNormalizedEntRepository(
+	connSet bunpgconnector.BunConnSet,
+) BunCrudRepository[TestNormalizedEnt, bun.Tx] {
+	return BunCrudRepository[TestNormalizedEnt, bun.Tx]{
+		ConnSet: connSet,
+		Meta:    meta.Parser(TestNormalizedEntMeta{}),
+	}
+}
+
+func TestE164(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		wantErr  error
+	}{
+		{name: "formatted", value: "+1 (415) 555-2671", expected: "+14155552671"},
+		{name: "international prefix", value: "0044 20 7946 0958", expected: "+442079460958"},
+		{name: "empty", value: "", expected: ""},
+		{name: "national", value: "415 555 2671", wantErr: ErrInvalidValue},
+		{name: "letters", value: "+1 415 CALL NOW", wantErr: ErrInvalidValue},
+		{name: "too long", value: "+1234567890123456", wantErr: ErrInvalidValue},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := E164(tt.value)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.expected, res)
+			}
+		})
+	}
+}
+
+func TestBunCrudRepository_Normalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		call     func(ctx context.Context, repo BunCrudRepository[TestNormalizedEnt, bun.Tx]) error
+		expected error
+	}{
+		{
+			name: "create one normalizes fields",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`INSERT INTO "test_normalized_entities" ("id", "email", "phone") VALUES (1, 'john@x.io', '+14155552671') RETURNING *`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+			call: func(ctx context.Context, repo BunCrudRepository[TestNormalizedEnt, bun.Tx]) error {
+				phone := "+1 415 555 2671"
+				_, err := repo.CreateOne(ctx, nil, &TestNormalizedEnt{ID: 1, Email: " John@X.io ", Phone: &phone}, []string{"*"})
+
+				return err
+			},
+		},
+		{
+			name: "create all with invalid phone",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo BunCrudRepository[TestNormalizedEnt, bun.Tx]) error {
+				phone := "555"
+				_, err := repo.CreateAll(ctx, nil, []TestNormalizedEnt{{ID: 1}, {ID: 2, Phone: &phone}}, nil)
+
+				return err
+			},
+			expected: ErrInvalidValue,
+		},
+		{
+			name: "update by spec normalizes values",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`UPDATE "test_normalized_entities" AS "test_normalized_entities" SET "email" = 'john@x.io' WHERE (test_normalized_entities.id = 1)`) + "$").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(ctx context.Context, repo BunCrudRepository[TestNormalizedEnt, bun.Tx]) error {
+				_, err := repo.UpdateBySpec(ctx, nil, map[string]any{"email": "JOHN@x.io"}, dataspec.NewEqual("id", 1))
+
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestNormalizedEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			err := tt.call(context.Background(), repo)
+
+			assert.ErrorIs(t, err, tt.expected)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
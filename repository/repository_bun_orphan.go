@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aso779/crud-repository/entrel"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// FindOrphans returns rows whose to-one relation columns point at missing rows of the related table.
+func (r BunCrudRepository[E, T]) FindOrphans(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	relation string,
+	page dataset.Pager,
+) ([]E, error) {
+	var entities = make([]E, 0)
+
+	if err := r.checkColumns("find orphans", columns...); err != nil {
+		return entities, fmt.Errorf("find orphans: %w", err)
+	}
+
+	join, conditions, err := r.orphanConditions(relation)
+	if err != nil {
+		return entities, fmt.Errorf("find orphans: %w", err)
+	}
+
+	tx, err = r.readDB(ctx, tx)
+	if err != nil {
+		return entities, fmt.Errorf("find orphans: %w", err)
+	}
+
+	query := tx.
+		NewSelect().
+		Model(&entities).
+		Join(join)
+
+	for _, c := range columns {
+		if c == "*" {
+			query.ColumnExpr("?TableAlias.*")
+		} else {
+			query.Column(c)
+		}
+	}
+
+	for _, c := range conditions {
+		query.Where(c)
+	}
+
+	if page != nil && !page.IsEmpty() {
+		query.Limit(page.GetSize())
+		query.Offset(page.GetOffset())
+	}
+
+	err = query.Scan(ctx)
+	if err != nil {
+		return entities, fmt.Errorf("find orphans: %w", dbError(err))
+	}
+
+	r.localize(entities)
+
+	if err := r.afterScan(ctx, entities); err != nil {
+		return entities, fmt.Errorf("find orphans: %w", err)
+	}
+
+	return entities, nil
+}
+
+// DeleteOrphans force deletes orphan rows of the relation in batches of batchSize rows.
+func (r BunCrudRepository[E, T]) DeleteOrphans(
+	ctx context.Context,
+	tx bun.IDB,
+	relation string,
+	batchSize int,
+) (int, error) {
+	var entity E
+
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("delete orphans: %w", ErrInvalidBatchSize)
+	}
+
+	join, conditions, err := r.orphanConditions(relation)
+	if err != nil {
+		return 0, fmt.Errorf("delete orphans: %w", err)
+	}
+
+	tx, err = r.writeDB(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("delete orphans: %w", err)
+	}
+
+	var total int
+
+	for {
+		sub := tx.NewSelect().
+			Model(&entity).
+			ColumnExpr("?TableAlias.ctid").
+			Join(join).
+			Limit(batchSize)
+
+		for _, c := range conditions {
+			sub.Where(c)
+		}
+
+		res, err := tx.NewDelete().
+			ForceDelete().
+			Model(&entity).
+			Where("ctid IN (?)", sub).
+			Exec(ctx)
+		if err != nil {
+			return total, fmt.Errorf("delete orphans: %w", dbError(err))
+		}
+
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return total, fmt.Errorf("delete orphans: %w", err)
+		}
+
+		total += int(rows)
+
+		if int(rows) < batchSize {
+			return total, nil
+		}
+	}
+}
+
+func (r BunCrudRepository[E, T]) orphanConditions(relation string) (string, []string, error) {
+	rel, ok := r.Meta.Relations()[relation]
+	if !ok {
+		return "", nil, fmt.Errorf("%s: %w", relation, ErrUnknownRelation)
+	}
+
+	toOne, ok := rel.(entrel.ToOne)
+	if !ok || len(toOne.JoinColumns) == 0 {
+		return "", nil, fmt.Errorf("%s: %w", relation, ErrUnsupportedRelation)
+	}
+
+	var (
+		on         = make([]string, 0, len(toOne.JoinColumns))
+		conditions = make([]string, 0, len(toOne.JoinColumns)+1)
+	)
+
+	for _, v := range toOne.JoinColumns {
+		local := qualify(r.specMeta().PersistenceName(), v.Name)
+
+		on = append(on, local+" = "+orphanRelated(v.ReferencedName))
+		conditions = append(conditions, local+" IS NOT NULL")
+	}
+
+	conditions = append(conditions, orphanRelated(toOne.JoinColumns[0].ReferencedName)+" IS NULL")
+
+	join := fmt.Sprintf("LEFT JOIN %s AS %s ON %s", toOne.JoinTable, orphanAlias, strings.Join(on, " AND "))
+
+	return join, conditions, nil
+}
+
+// orphanAlias aliases the related table, so self-referencing relations don't join the table under its own name.
+const orphanAlias = "orphan_related"
+
+// orphanRelated qualifies a referenced column of the related table by orphanAlias.
+func orphanRelated(column string) string {
+	return orphanAlias + "." + column[strings.LastIndex(column, ".")+1:]
+}
+
+func qualify(table, column string) string {
+	if strings.Contains(column, ".") {
+		return column
+	}
+
+	return table + "." + column
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/crud-repository/entrel"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_FindOrphans(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		relation string
+		page     dataset.Pager
+		expected func(t *testing.T, res []TestCategoryEnt, err error)
+	}{
+		{
+			name: "find orphans by to one relation",
+			mock: func(conn *MockBunConnSet) {
+				rows := sqlmock.NewRows([]string{"id", "name", "main_item_id"}).
+					AddRow(1, "testName1", 10)
+
+				conn.Mock.ExpectQuery("^SELECT \"test_categories\"\\.\\* FROM \"test_categories\" LEFT JOIN test_items AS orphan_related ON test_categories\\.main_item_id = orphan_related\\.id WHERE \\(test_categories\\.main_item_id IS NOT NULL\\) AND \\(orphan_related\\.id IS NULL\\) LIMIT 5$").
+					WillReturnRows(rows)
+			},
+			relation: "MainItem",
+			page:     NewPager(5, 0),
+			expected: func(t *testing.T, res []TestCategoryEnt, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 1, len(res))
+			},
+		},
+		{
+			name:     "find orphans by to many relation",
+			mock:     func(conn *MockBunConnSet) {},
+			relation: "Items",
+			page:     NewPager(5, 0),
+			expected: func(t *testing.T, res []TestCategoryEnt, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrUnsupportedRelation)
+			},
+		},
+		{
+			name:     "find orphans by unknown relation",
+			mock:     func(conn *MockBunConnSet) {},
+			relation: "Unknown",
+			page:     NewPager(5, 0),
+			expected: func(t *testing.T, res []TestCategoryEnt, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrUnknownRelation)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestCategoryEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			res, err := repo.FindOrphans(context.Background(), nil, []string{"*"}, tt.relation, tt.page)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+
+			tt.expected(t, res, err)
+		})
+	}
+}
+
+type testParentCategoryMeta struct {
+	metadata.Meta
+}
+
+func (r testParentCategoryMeta) Relations() map[string]metadata.Relation {
+	return map[string]metadata.Relation{
+		"Parent": entrel.ToOne{
+			JoinTable:   "test_categories",
+			JoinColumns: []entrel.JoinColumn{{Name: "parent_id", ReferencedName: "id"}},
+		},
+	}
+}
+
+func TestBunCrudRepository_FindOrphansSelfReference(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestCategoryEntRepository(subject.conn)
+	repo.Meta = testParentCategoryMeta{Meta: repo.Meta}
+
+	subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_categories".* FROM "test_categories" LEFT JOIN test_categories AS orphan_related ON test_categories.parent_id = orphan_related.id WHERE (test_categories.parent_id IS NOT NULL) AND (orphan_related.id IS NULL)`) + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "child"))
+
+	res, err := repo.FindOrphans(context.Background(), nil, []string{"*"}, "Parent", nil)
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+}
+
+func TestBunCrudRepository_DeleteOrphansError(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestCategoryEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectExec("^DELETE FROM \"test_categories\"").WillReturnError(testPgError{code: "23503"})
+
+	_, err := repo.DeleteOrphans(context.Background(), nil, "MainItem", 2)
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.ErrorIs(t, err, ErrForeignKeyViolation)
+}
+
+func TestBunCrudRepository_DeleteOrphans(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestCategoryEntRepository(subject.conn)
+
+	query := "^DELETE FROM \"test_categories\" AS \"test_categories\" WHERE \\(ctid IN \\(SELECT \"test_categories\"\\.ctid FROM \"test_categories\" LEFT JOIN test_items AS orphan_related ON test_categories\\.main_item_id = orphan_related\\.id WHERE \\(test_categories\\.main_item_id IS NOT NULL\\) AND \\(orphan_related\\.id IS NULL\\) LIMIT 2\\)\\)$"
+
+	subject.conn.Mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 2))
+	subject.conn.Mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res, err := repo.DeleteOrphans(context.Background(), nil, "MainItem", 2)
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, res)
+}
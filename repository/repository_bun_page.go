@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// AppliedQuery effective joins, filter, order and paging of a query after metadata translation,
+// with values inlined the way they are sent to the database.
+type AppliedQuery struct {
+	Joins   []string
+	Filter  string
+	OrderBy string
+	Limit   int
+	Offset  int
+}
+
+// PageResult page rows with the query that produced them. Total, Page, Size and HasNext are set by FindPageWithTotal.
+type PageResult[E any] struct {
+	Items   []E
+	Applied AppliedQuery
+	Total   int
+	Page    int
+	Size    int
+	HasNext bool
+}
+
+// FindPageApplied is FindPage also describing the applied query, e.g. to echo effective filters in API responses.
+func (r BunCrudRepository[E, T]) FindPageApplied(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+	page dataset.Pager,
+	sort dataset.Sorter,
+) (PageResult[E], error) {
+	return r.findPage(ctx, tx, columns, spec, page, sort, true)
+}
+
+// FindPageWithTotal is FindPage also counting all rows matching spec. Rows are counted by distinct primary key
+// when spec joins relations, so joined rows aren't counted twice.
+func (r BunCrudRepository[E, T]) FindPageWithTotal(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+	page dataset.Pager,
+	sort dataset.Sorter,
+) (PageResult[E], error) {
+	res, err := r.findPage(ctx, tx, columns, spec, page, sort, false)
+	if err != nil {
+		return res, fmt.Errorf("find page with total: %w", err)
+	}
+
+	res.Total, err = r.countTotal(ctx, tx, spec)
+	if err != nil {
+		return res, fmt.Errorf("find page with total: %w", err)
+	}
+
+	res.setPage(page)
+
+	return res, nil
+}
+
+// countTotal counts rows matching spec, by distinct primary key when spec joins relations.
+func (r BunCrudRepository[E, T]) countTotal(ctx context.Context, tx bun.IDB, spec dataset.Specifier) (int, error) {
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int
+
+	applied := r.evalSpec(spec)
+	query := tx.NewSelect().Model((*E)(nil))
+
+	query.ColumnExpr(r.countExpr(tx, applied))
+
+	applied.apply(query)
+
+	if err := query.Scan(ctx, &total); err != nil {
+		return 0, dbError(err)
+	}
+
+	return total, nil
+}
+
+// countExpr counts distinct primary keys when spec joins relations, rows of entities without primary key,
+// e.g. read-only views, are counted as joined.
+func (r BunCrudRepository[E, T]) countExpr(tx bun.IDB, applied *appliedSpec) string {
+	if applied.joined() && len(tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem()).PKs) > 0 {
+		return "count(DISTINCT (?TablePKs))"
+	}
+
+	return "count(*)"
+}
+
+// setPage sets the page fields of a result with Total.
+func (r *PageResult[E]) setPage(page dataset.Pager) {
+	if page != nil && !page.IsEmpty() {
+		r.Page = page.GetNumber()
+		r.Size = page.GetSize()
+		r.HasNext = page.GetOffset()+len(r.Items) < r.Total
+	}
+}
+
+// findPage runs the page query, formatting the applied filter only when describe is set.
+func (r BunCrudRepository[E, T]) findPage(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+	page dataset.Pager,
+	sort dataset.Sorter,
+	describe bool,
+) (PageResult[E], error) {
+	res := PageResult[E]{Items: make([]E, 0)}
+
+	if err := r.checkColumns("find page", columns...); err != nil {
+		return res, fmt.Errorf("find page: %w", err)
+	}
+
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return res, fmt.Errorf("find page: %w", err)
+	}
+
+	query := tx.
+		NewSelect().
+		Model(&res.Items).
+		Column(columns...)
+
+	applied := r.evalSpec(spec)
+	applied.apply(query)
+
+	if err := r.applyPage(query, page, sort, &res.Applied); err != nil {
+		return res, fmt.Errorf("find page: %w", err)
+	}
+
+	if describe {
+		applied.describe(tx, &res.Applied)
+	}
+
+	err = query.Scan(ctx)
+	if err != nil {
+		return res, fmt.Errorf("find page: %w", dbError(err))
+	}
+
+	r.localize(res.Items)
+
+	if err := r.afterScan(ctx, res.Items); err != nil {
+		return res, fmt.Errorf("find page: %w", err)
+	}
+
+	return res, nil
+}
+
+// applyPage adds paging and order to the query, recording them in applied.
+func (r BunCrudRepository[E, T]) applyPage(
+	query *bun.SelectQuery,
+	page dataset.Pager,
+	sort dataset.Sorter,
+	applied *AppliedQuery,
+) error {
+	if page != nil && !page.IsEmpty() {
+		query.Limit(page.GetSize())
+		query.Offset(page.GetOffset())
+
+		applied.Limit = page.GetSize()
+		applied.Offset = page.GetOffset()
+	}
+
+	if sort != nil && !sort.IsEmpty() {
+		orderBy := sort.OrderBy(r.specMeta())
+		if _, raw := sort.(RawSorter); !raw {
+			if err := r.checkOrder("find page", orderBy); err != nil {
+				return err
+			}
+		}
+
+		query.OrderExpr(orderBy)
+
+		applied.OrderBy = orderBy
+	}
+
+	return nil
+}
+
+// describe formats spec joins and filter the same way apply adds them to queries.
+func (r *appliedSpec) describe(tx bun.IDB, applied *AppliedQuery) {
+	if r == nil {
+		return
+	}
+
+	fmter := schema.NewFormatter(tx.Dialect())
+
+	for _, j := range r.joins {
+		applied.Joins = append(applied.Joins, fmter.FormatQuery(j.JoinString, j.Args...))
+	}
+
+	applied.Filter = fmter.FormatQuery(r.query, r.values...)
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_FindPageApplied(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestSimpleEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectQuery("^SELECT .* FROM \"test_simple_entities\" WHERE \\(test_simple_entities.name = 'a'\\) ORDER BY name DESC LIMIT 5 OFFSET 5$").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(6, "a"))
+
+	res, err := repo.FindPageApplied(
+		context.Background(),
+		nil,
+		nil,
+		dataspec.NewEqual("name", "a"),
+		NewPager(5, 1),
+		NewSorter().WithSort("name", "desc"),
+	)
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Len(t, res.Items, 1)
+	assert.Equal(t, AppliedQuery{
+		Filter:  "test_simple_entities.name = 'a'",
+		OrderBy: "name DESC",
+		Limit:   5,
+		Offset:  5,
+	}, res.Applied)
+}
+
+func TestBunCrudRepository_FindPageWithTotal(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestSimpleEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectQuery("^SELECT .* FROM \"test_simple_entities\" WHERE \\(test_simple_entities.name = 'a'\\) LIMIT 2 OFFSET 2$").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "a").AddRow(4, "a"))
+	subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT count(*) FROM "test_simple_entities" WHERE (test_simple_entities.name = 'a')`) + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+	res, err := repo.FindPageWithTotal(context.Background(), nil, nil, dataspec.NewEqual("name", "a"), NewPager(2, 1), nil)
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Len(t, res.Items, 2)
+	assert.Equal(t, 5, res.Total)
+	assert.Equal(t, 1, res.Page)
+	assert.Equal(t, 2, res.Size)
+	assert.True(t, res.HasNext)
+}
+
+func TestBunCrudRepository_FindPageWithTotalWithRelations(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestCategoryEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectQuery("^SELECT \\* FROM \"test_categories\" INNER JOIN test_category_items .* LIMIT 5$").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+	subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT count(DISTINCT ("test_categories"."id")) FROM "test_categories" INNER JOIN test_category_items ON test_category_items.category_id = test_categories.id INNER JOIN test_items ON item_id = test_items.id WHERE ("test_items"."name" = 'John')`) + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	res, err := repo.FindPageWithTotal(context.Background(), nil, []string{"*"}, dataspec.NewEqual("Category.Items.name", "John"), NewPager(5, 0), nil)
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res.Total)
+	assert.False(t, res.HasNext)
+}
+
+func TestBunCrudRepository_FindPageWithTotalError(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestSimpleEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectQuery("^SELECT .* FROM \"test_simple_entities\"").WillReturnError(context.DeadlineExceeded)
+
+	_, err := repo.FindPageWithTotal(context.Background(), nil, nil, nil, NewPager(2, 1), nil)
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "find page with total: ")
+}
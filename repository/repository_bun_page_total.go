@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// TotalMode how FindPageWithTotalMode counts the rows matching spec.
+type TotalMode int
+
+const (
+	// TotalSeparate counts with a second query, as FindPageWithTotal does.
+	TotalSeparate TotalMode = iota
+	// TotalWindow counts with count(*) OVER () in the page query. Specs joining relations are counted
+	// as with TotalCTE, since window functions can't count distinct primary keys.
+	TotalWindow
+	// TotalCTE evaluates spec once into a filtered CTE, selecting the page and counting from it
+	// in one query. Sorters must order by entity columns, joined columns are not selected by the CTE.
+	TotalCTE
+)
+
+// pageTotalColumn is the total column of single query counts, it can't clash with entity columns.
+const pageTotalColumn = "__total"
+
+// pageFilteredTable is the CTE of TotalCTE.
+const pageFilteredTable = "filtered"
+
+// pageRow page row with the total of single query counts.
+type pageRow[E any] struct {
+	Item  E   `bun:"embed:"`
+	Total int `bun:"__total"`
+}
+
+// FindPageWithTotalMode is FindPageWithTotal counting rows as selected by mode, TotalWindow and TotalCTE
+// evaluate heavy specs once instead of twice. A page past the last row has no row to carry the total,
+// it's counted with a second query then.
+func (r BunCrudRepository[E, T]) FindPageWithTotalMode(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+	page dataset.Pager,
+	sort dataset.Sorter,
+	mode TotalMode,
+) (PageResult[E], error) {
+	switch mode {
+	case TotalSeparate:
+		return r.FindPageWithTotal(ctx, tx, columns, spec, page, sort)
+	case TotalWindow, TotalCTE:
+	default:
+		return PageResult[E]{Items: make([]E, 0)}, fmt.Errorf("find page with total: mode %d: %w", mode, ErrInvalidValue)
+	}
+
+	res := PageResult[E]{Items: make([]E, 0)}
+
+	if err := r.checkColumns("find page", columns...); err != nil {
+		return res, fmt.Errorf("find page with total: %w", err)
+	}
+
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return res, fmt.Errorf("find page with total: %w", err)
+	}
+
+	applied := r.evalSpec(spec)
+	query := tx.NewSelect().Model((*E)(nil))
+
+	if len(columns) > 0 {
+		query.Column(columns...)
+	} else {
+		query.ColumnExpr("?TableAlias.*")
+	}
+
+	if mode == TotalWindow && !applied.joined() {
+		query.ColumnExpr("count(*) OVER () AS ?", bun.Ident(pageTotalColumn))
+		applied.apply(query)
+	} else {
+		filtered := tx.NewSelect().Model((*E)(nil)).ColumnExpr("?TableAlias.*")
+		applied.apply(filtered)
+
+		query.
+			With(pageFilteredTable, filtered).
+			ModelTableExpr("? AS ?TableAlias", bun.Ident(pageFilteredTable)).
+			ColumnExpr("(SELECT "+r.countExpr(tx, applied)+" FROM ? AS ?TableAlias) AS ?", bun.Ident(pageFilteredTable), bun.Ident(pageTotalColumn))
+	}
+
+	if err := r.applyPage(query, page, sort, &res.Applied); err != nil {
+		return res, fmt.Errorf("find page with total: %w", err)
+	}
+
+	var rows []pageRow[E]
+
+	if err := query.Scan(ctx, &rows); err != nil {
+		return res, fmt.Errorf("find page with total: %w", dbError(err))
+	}
+
+	for _, row := range rows {
+		res.Items = append(res.Items, row.Item)
+		res.Total = row.Total
+	}
+
+	if len(rows) == 0 && res.Applied.Offset > 0 {
+		res.Total, err = r.countTotal(ctx, tx, spec)
+		if err != nil {
+			return res, fmt.Errorf("find page with total: %w", err)
+		}
+	}
+
+	r.localize(res.Items)
+
+	if err := r.afterScan(ctx, res.Items); err != nil {
+		return res, fmt.Errorf("find page with total: %w", err)
+	}
+
+	res.setPage(page)
+
+	return res, nil
+}
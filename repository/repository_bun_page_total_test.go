@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_FindPageWithTotalMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mode     TotalMode
+		page     int
+		mock     func(conn *MockBunConnSet)
+		expected PageResult[TestSimpleEnt]
+		err      error
+	}{
+		{
+			name: "separate",
+			mode: TotalSeparate,
+			page: 1,
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" WHERE (test_simple_entities.name = 'a') ORDER BY id DESC LIMIT 2 OFFSET 2`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "a").AddRow(2, "a"))
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT count(*) FROM "test_simple_entities" WHERE (test_simple_entities.name = 'a')`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+			},
+			expected: PageResult[TestSimpleEnt]{
+				Items:   []TestSimpleEnt{{ID: 3, Name: "a"}, {ID: 2, Name: "a"}},
+				Applied: AppliedQuery{OrderBy: "id DESC", Limit: 2, Offset: 2},
+				Total:   5,
+				Page:    1,
+				Size:    2,
+				HasNext: true,
+			},
+		},
+		{
+			name: "window",
+			mode: TotalWindow,
+			page: 1,
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities".*, count(*) OVER () AS "__total" FROM "test_simple_entities" WHERE (test_simple_entities.name = 'a') ORDER BY id DESC LIMIT 2 OFFSET 2`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "__total"}).AddRow(3, "a", 5).AddRow(2, "a", 5))
+			},
+			expected: PageResult[TestSimpleEnt]{
+				Items:   []TestSimpleEnt{{ID: 3, Name: "a"}, {ID: 2, Name: "a"}},
+				Applied: AppliedQuery{OrderBy: "id DESC", Limit: 2, Offset: 2},
+				Total:   5,
+				Page:    1,
+				Size:    2,
+				HasNext: true,
+			},
+		},
+		{
+			name: "cte",
+			mode: TotalCTE,
+			page: 2,
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`WITH "filtered" AS (SELECT "test_simple_entities".* FROM "test_simple_entities" WHERE (test_simple_entities.name = 'a')) SELECT "test_simple_entities".*, (SELECT count(*) FROM "filtered" AS "test_simple_entities") AS "__total" FROM "filtered" AS "test_simple_entities" ORDER BY id DESC LIMIT 2 OFFSET 4`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "__total"}).AddRow(1, "a", 5))
+			},
+			expected: PageResult[TestSimpleEnt]{
+				Items:   []TestSimpleEnt{{ID: 1, Name: "a"}},
+				Applied: AppliedQuery{OrderBy: "id DESC", Limit: 2, Offset: 4},
+				Total:   5,
+				Page:    2,
+				Size:    2,
+			},
+		},
+		{
+			name: "page past the last row",
+			mode: TotalWindow,
+			page: 3,
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_simple_entities".*, count(*) OVER () AS "__total" FROM "test_simple_entities" WHERE (test_simple_entities.name = 'a') ORDER BY id DESC LIMIT 2 OFFSET 6`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "__total"}))
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT count(*) FROM "test_simple_entities" WHERE (test_simple_entities.name = 'a')`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+			},
+			expected: PageResult[TestSimpleEnt]{
+				Items:   []TestSimpleEnt{},
+				Applied: AppliedQuery{OrderBy: "id DESC", Limit: 2, Offset: 6},
+				Total:   5,
+				Page:    3,
+				Size:    2,
+			},
+		},
+		{
+			name:     "unknown mode",
+			mode:     TotalMode(7),
+			mock:     func(conn *MockBunConnSet) {},
+			expected: PageResult[TestSimpleEnt]{Items: []TestSimpleEnt{}},
+			err:      ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			res, err := repo.FindPageWithTotalMode(
+				context.Background(),
+				nil,
+				nil,
+				dataspec.NewEqual("name", "a"),
+				NewPager(2, tt.page),
+				NewSorter().WithSort("id", "desc"),
+				tt.mode,
+			)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
+func TestBunCrudRepository_FindPageWithTotalModeWithRelations(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range []TotalMode{TotalWindow, TotalCTE} {
+		subject := crudRepositoryShortTestSetUp(t)
+		repo := NewTestCategoryEntRepository(subject.conn)
+
+		subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`WITH "filtered" AS (SELECT "test_categories".* FROM "test_categories" INNER JOIN test_category_items ON test_category_items.category_id = test_categories.id INNER JOIN test_items ON item_id = test_items.id WHERE ("test_items"."name" = 'John')) SELECT *, (SELECT count(DISTINCT ("test_categories"."id")) FROM "filtered" AS "test_categories") AS "__total" FROM "filtered" AS "test_categories" LIMIT 5`) + "$").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "__total"}).AddRow(1, "a", 1))
+
+		res, err := repo.FindPageWithTotalMode(context.Background(), nil, []string{"*"}, dataspec.NewEqual("Category.Items.name", "John"), NewPager(5, 0), nil, mode)
+
+		assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		assert.NoError(t, err)
+		assert.Len(t, res.Items, 1)
+		assert.Equal(t, 1, res.Total)
+		assert.False(t, res.HasNext)
+	}
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/uptrace/bun"
+)
+
+// Partitioned entity is stored in a table partitioned by range of the timestamp PartitionColumn.
+// Monthly partitions are named <table>_yYYYYmMM and bounded by UTC months.
+type Partitioned interface {
+	PartitionColumn() string
+}
+
+// Partition a partition of the entity table.
+type Partition struct {
+	Name  string `bun:"name"`
+	Bound string `bun:"bound"`
+	Size  int64  `bun:"size"`
+}
+
+// PartitionName returns the name of the monthly partition holding at.
+func (r BunCrudRepository[E, T]) PartitionName(at time.Time) string {
+	at = at.UTC()
+
+	return fmt.Sprintf("%s_y%04dm%02d", r.Meta.PersistenceName(), at.Year(), int(at.Month()))
+}
+
+// CreateMonthlyPartitions creates missing monthly partitions covering from through to, returning their names.
+func (r BunCrudRepository[E, T]) CreateMonthlyPartitions(
+	ctx context.Context,
+	tx bun.IDB,
+	from time.Time,
+	to time.Time,
+) ([]string, error) {
+	if _, err := r.partitionColumn(); err != nil {
+		return nil, fmt.Errorf("create monthly partitions: %w", err)
+	}
+
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("create monthly partitions: %w", err)
+	}
+
+	var names []string
+
+	from, to = from.UTC(), to.UTC()
+
+	for month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC); !month.After(to); month = month.AddDate(0, 1, 0) {
+		name := r.PartitionName(month)
+
+		_, err := tx.ExecContext(
+			ctx,
+			"CREATE TABLE IF NOT EXISTS ? PARTITION OF ? FOR VALUES FROM (?) TO (?)",
+			bun.Ident(name),
+			bun.Ident(r.Meta.PersistenceName()),
+			month,
+			month.AddDate(0, 1, 0),
+		)
+		if err != nil {
+			return names, fmt.Errorf("create monthly partitions %s: %w", name, dbError(err))
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// CreateAllRouted inserts entities directly into their monthly partitions, skipping routing through the parent
+// table and failing for a missing partition instead of falling into a default one. Entities are prepared and
+// inserted the way CreateAll does, with one insert per partition.
+func (r BunCrudRepository[E, T]) CreateAllRouted(
+	ctx context.Context,
+	tx bun.IDB,
+	entities []E,
+) ([]E, error) {
+	column, err := r.partitionColumn()
+	if err != nil {
+		return entities, fmt.Errorf("create all routed: %w", err)
+	}
+
+	tx, err = r.writeDB(ctx, tx)
+	if err != nil {
+		return entities, fmt.Errorf("create all routed: %w", err)
+	}
+
+	field := tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem()).LookupField(column)
+	if field == nil {
+		return entities, fmt.Errorf("create all routed %s: %w", column, ErrUnknownColumn)
+	}
+
+	if err := r.prepareInsertAll(ctx, tx, entities); err != nil {
+		return entities, fmt.Errorf("create all routed: %w", err)
+	}
+
+	var (
+		names   []string
+		indexes = make(map[string][]int)
+	)
+
+	for i := range entities {
+		at, ok := field.Value(reflect.ValueOf(&entities[i]).Elem()).Interface().(time.Time)
+		if !ok {
+			return entities, fmt.Errorf("create all routed %s: %w", column, ErrInvalidValue)
+		}
+
+		name := r.PartitionName(at)
+		if _, ok := indexes[name]; !ok {
+			names = append(names, name)
+		}
+
+		indexes[name] = append(indexes[name], i)
+	}
+
+	for _, name := range names {
+		group := make([]E, 0, len(indexes[name]))
+		for _, i := range indexes[name] {
+			group = append(group, entities[i])
+		}
+
+		if err := r.insertAllInto(ctx, tx, name, group, nil); err != nil {
+			return entities, fmt.Errorf("create all routed %s: %w", name, err)
+		}
+
+		for j, i := range indexes[name] {
+			entities[i] = group[j]
+		}
+	}
+
+	return entities, nil
+}
+
+// DeleteInPartitions deletes rows matching spec with the partition column in [from, to),
+// so that the planner prunes partitions outside the range.
+func (r BunCrudRepository[E, T]) DeleteInPartitions(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	from time.Time,
+	to time.Time,
+) (int, error) {
+	column, err := r.partitionColumn()
+	if err != nil {
+		return 0, fmt.Errorf("delete in partitions: %w", err)
+	}
+
+	presenter, ok := r.Meta.PersistencePresenterMapping()[column]
+	if !ok {
+		return 0, fmt.Errorf("delete in partitions %s: %w", column, ErrUnknownColumn)
+	}
+
+	bounded := dataspec.NewAnd(dataspec.NewGte(presenter, from), dataspec.NewLt(presenter, to))
+	if spec != nil && !spec.IsEmpty() {
+		bounded.Append(spec)
+	}
+
+	return r.Delete(ctx, tx, bounded)
+}
+
+// Partitions lists partitions of the entity table with their bounds and total sizes in bytes.
+func (r BunCrudRepository[E, T]) Partitions(ctx context.Context, tx bun.IDB) ([]Partition, error) {
+	var partitions = make([]Partition, 0)
+
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return partitions, fmt.Errorf("partitions: %w", err)
+	}
+
+	err = tx.NewSelect().
+		ColumnExpr("c.relname AS name").
+		ColumnExpr("pg_get_expr(c.relpartbound, c.oid) AS bound").
+		ColumnExpr("pg_total_relation_size(c.oid) AS size").
+		TableExpr("pg_inherits AS i").
+		Join("JOIN pg_class AS c ON c.oid = i.inhrelid").
+		Where("i.inhparent = ?::regclass", r.Meta.PersistenceName()).
+		OrderExpr("c.relname").
+		Scan(ctx, &partitions)
+	if err != nil {
+		return partitions, fmt.Errorf("partitions: %w", err)
+	}
+
+	return partitions, nil
+}
+
+func (r BunCrudRepository[E, T]) partitionColumn() (string, error) {
+	partitioned, ok := any(*new(E)).(Partitioned)
+	if !ok {
+		return "", ErrPartitionNotSupported
+	}
+
+	return partitioned.PartitionColumn(), nil
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type TestEventEnt struct {
+	bun.BaseModel `bun:"table:test_events,alias:test_events"`
+
+	ID        int       `bun:"id,pk" json:"id"`
+	Kind      string    `bun:"kind" json:"kind"`
+	CreatedAt time.Time `bun:"created_at" json:"createdAt"`
+}
+
+func (r TestEventEnt) EntityName() string {
+	return "TestEventEnt"
+}
+
+func (r TestEventEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+func (r TestEventEnt) PartitionColumn() string {
+	return "created_at"
+}
+
+type TestEventEntMeta struct {
+	TestEventEnt
+}
+
+func (r TestEventEntMeta) Entity() metadata.Entity { return r.TestEventEnt }
+
+func (r TestEventEntMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func newTestEventEntRepository(subject crudRepositoryShortTest) BunCrudRepository[TestEventEnt, bun.Tx] {
+	return BunCrudRepository[TestEventEnt, bun.Tx]{
+		ConnSet: subject.conn,
+		Meta:    meta.Parser(TestEventEntMeta{}),
+	}
+}
+
+func TestBunCrudRepository_CreateMonthlyPartitions(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := newTestEventEntRepository(subject)
+
+	for _, q := range []string{
+		`CREATE TABLE IF NOT EXISTS "test_events_y2024m12" PARTITION OF "test_events" FOR VALUES FROM ('2024-12-01 00:00:00+00:00') TO ('2025-01-01 00:00:00+00:00')`,
+		`CREATE TABLE IF NOT EXISTS "test_events_y2025m01" PARTITION OF "test_events" FOR VALUES FROM ('2025-01-01 00:00:00+00:00') TO ('2025-02-01 00:00:00+00:00')`,
+	} {
+		subject.conn.Mock.ExpectExec("^" + regexp.QuoteMeta(q) + "$").WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	names, err := repo.CreateMonthlyPartitions(
+		context.Background(),
+		nil,
+		time.Date(2024, 12, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+	)
+
+	assert.NoError(t, err)
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.Equal(t, []string{"test_events_y2024m12", "test_events_y2025m01"}, names)
+
+	_, err = NewTestSimpleEntRepository(subject.conn).CreateMonthlyPartitions(context.Background(), nil, time.Now(), time.Now())
+	assert.ErrorIs(t, err, ErrPartitionNotSupported)
+}
+
+func TestBunCrudRepository_CreateAllRouted(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := newTestEventEntRepository(subject)
+
+	jan := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2025, 2, 2, 0, 0, 0, 0, time.UTC)
+
+	subject.conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`INSERT INTO "test_events_y2025m01" AS "test_events" ("id", "kind", "created_at") VALUES (1, 'a', '2025-01-02 00:00:00+00:00'), (3, 'c', '2025-01-02 00:00:00+00:00')`) + "$").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	subject.conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`INSERT INTO "test_events_y2025m02" AS "test_events" ("id", "kind", "created_at") VALUES (2, 'b', '2025-02-02 00:00:00+00:00')`) + "$").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res, err := repo.CreateAllRouted(context.Background(), nil, []TestEventEnt{
+		{ID: 1, Kind: "a", CreatedAt: jan},
+		{ID: 2, Kind: "b", CreatedAt: feb},
+		{ID: 3, Kind: "c", CreatedAt: jan},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.Len(t, res, 3)
+}
+
+func TestBunCrudRepository_DeleteInPartitions(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := newTestEventEntRepository(subject)
+
+	subject.conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`DELETE FROM "test_events" AS "test_events" WHERE ((test_events.created_at >= '2025-01-01 00:00:00+00:00' AND test_events.created_at < '2025-02-01 00:00:00+00:00' AND test_events.kind = 'a'))`) + "$").
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	rows, err := repo.DeleteInPartitions(
+		context.Background(),
+		nil,
+		dataspec.NewEqual("kind", "a"),
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	)
+
+	assert.NoError(t, err)
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.Equal(t, 4, rows)
+}
+
+func TestBunCrudRepository_Partitions(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := newTestEventEntRepository(subject)
+
+	subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT c.relname AS name, pg_get_expr(c.relpartbound, c.oid) AS bound, pg_total_relation_size(c.oid) AS size FROM pg_inherits AS i JOIN pg_class AS c ON c.oid = i.inhrelid WHERE (i.inhparent = 'test_events'::regclass) ORDER BY c.relname`) + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "bound", "size"}).
+			AddRow("test_events_y2025m01", "FOR VALUES FROM ('2025-01-01 00:00:00+00') TO ('2025-02-01 00:00:00+00')", 8192))
+
+	res, err := repo.Partitions(context.Background(), nil)
+
+	assert.NoError(t, err)
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.Equal(t, []Partition{{
+		Name:  "test_events_y2025m01",
+		Bound: "FOR VALUES FROM ('2025-01-01 00:00:00+00') TO ('2025-02-01 00:00:00+00')",
+		Size:  8192,
+	}}, res)
+}
+
+func TestBunCrudRepository_CreateAllRoutedConflict(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := newTestEventEntRepository(subject)
+
+	subject.conn.Mock.ExpectExec(`^INSERT INTO "test_events_y2025m01"`).WillReturnError(testPgError{code: "23505"})
+
+	_, err := repo.CreateAllRouted(context.Background(), nil, []TestEventEnt{
+		{ID: 1, Kind: "a", CreatedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+	})
+
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+}
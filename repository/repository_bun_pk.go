@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/uptrace/bun"
+)
+
+// PrimaryKeyOrder returns the key fields in the order primary key operations render them,
+// sorted by name, independent of map iteration.
+func PrimaryKeyOrder(pk metadata.PrimaryKey) []string {
+	return pk.SortedKeys()
+}
+
+// PrimaryKeyValues returns the key values in PrimaryKeyOrder.
+func PrimaryKeyValues(pk metadata.PrimaryKey) []any {
+	keys := PrimaryKeyOrder(pk)
+	values := make([]any, len(keys))
+
+	for i, k := range keys {
+		values[i] = pk[k]
+	}
+
+	return values
+}
+
+// pkSpec matches the row with the primary key.
+func pkSpec(pk metadata.PrimaryKey) dataset.Specifier {
+	spec := dataspec.NewAnd()
+
+	for _, k := range PrimaryKeyOrder(pk) {
+		spec.Append(dataspec.NewEqual(k, pk[k]))
+	}
+
+	return spec
+}
+
+// pksSpec matches rows with the primary keys, composite keys are matched as tuples in PrimaryKeyOrder.
+// Keys must have the same fields, pks must not be empty.
+func pksSpec(pks []metadata.PrimaryKey) (dataset.Specifier, error) {
+	keys := PrimaryKeyOrder(pks[0])
+	values := make([]any, 0, len(pks))
+
+	for i, pk := range pks {
+		if !slices.Equal(keys, PrimaryKeyOrder(pk)) {
+			return nil, fmt.Errorf("primary key %d %v, expected %v: %w", i, PrimaryKeyOrder(pk), keys, ErrInconsistentColumns)
+		}
+
+		if len(keys) == 1 {
+			values = append(values, pk[keys[0]])
+		} else {
+			values = append(values, PrimaryKeyValues(pk))
+		}
+	}
+
+	if len(keys) == 1 {
+		return dataspec.NewIn(keys[0], bun.In(values)), nil
+	}
+
+	return dataspec.NewCompositeIn(keys, bun.In(values)), nil
+}
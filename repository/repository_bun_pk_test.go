@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/schema"
+)
+
+func TestPrimaryKeyOrder_Property(t *testing.T) {
+	t.Parallel()
+
+	// the same key is built inserting keys in the given and in the reversed order
+	property := func(keys []string) bool {
+		pk := metadata.PrimaryKey{}
+		reversed := metadata.PrimaryKey{}
+
+		for i, k := range keys {
+			pk[k] = i
+		}
+
+		for i := len(keys) - 1; i >= 0; i-- {
+			reversed[keys[i]] = pk[keys[i]]
+		}
+
+		order := PrimaryKeyOrder(pk)
+		vals := PrimaryKeyValues(pk)
+
+		if !sort.StringsAreSorted(order) || len(order) != len(pk) || len(vals) != len(pk) {
+			return false
+		}
+
+		for i, k := range order {
+			if vals[i] != pk[k] {
+				return false
+			}
+		}
+
+		return fmt.Sprint(order) == fmt.Sprint(PrimaryKeyOrder(reversed)) &&
+			fmt.Sprint(vals) == fmt.Sprint(PrimaryKeyValues(reversed))
+	}
+
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestPksSpec_Property(t *testing.T) {
+	t.Parallel()
+
+	m := NewEntities().Get(TestComplexEnt{}.EntityName())
+	fmter := schema.NewFormatter(pgdialect.New())
+
+	// composite tuples always follow the sorted key order, whatever the map iteration order
+	property := func(first, second []int16) bool {
+		n := min(len(first), len(second))
+		if n == 0 {
+			return true
+		}
+
+		pks := make([]metadata.PrimaryKey, n)
+		tuples := make([]string, n)
+
+		for i := 0; i < n; i++ {
+			pks[i] = metadata.PrimaryKey{"secondId": int(second[i]), "firstId": int(first[i])}
+			tuples[i] = fmt.Sprintf("(%d, %d)", first[i], second[i])
+		}
+
+		expected := "(test_complex_entities.first_id,test_complex_entities.second_id) IN (" + strings.Join(tuples, ", ") + ")"
+
+		for i := 0; i < 3; i++ {
+			spec, err := pksSpec(pks)
+			if err != nil || fmter.FormatQuery(spec.Query(m), spec.Values()...) != expected {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestBunCrudRepository_FindAllByPksKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		pks  []metadata.PrimaryKey
+		err  error
+	}{
+		{
+			name: "no keys",
+		},
+		{
+			name: "inconsistent keys",
+			pks:  []metadata.PrimaryKey{{"firstId": 1, "secondId": 2}, {"firstId": 3}},
+			err:  ErrInconsistentColumns,
+		},
+		{
+			name: "different keys",
+			pks:  []metadata.PrimaryKey{{"firstId": 1, "secondId": 2}, {"firstId": 3, "complexName": 4}},
+			err:  ErrInconsistentColumns,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestComplexEntRepository(subject.conn)
+
+			res, err := repo.FindAllByPks(context.Background(), nil, nil, tt.pks)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.ErrorIs(t, err, tt.err)
+			assert.Empty(t, res)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// SlicePool reuses entity slices of FindAllPooled results.
+type SlicePool[E any] struct {
+	// MaxCap drops released slices above this capacity, so rare huge results are not retained. Zero keeps all.
+	MaxCap int
+
+	pool sync.Pool
+}
+
+func NewSlicePool[E any](maxCap int) *SlicePool[E] {
+	return &SlicePool[E]{MaxCap: maxCap}
+}
+
+func (r *SlicePool[E]) get() []E {
+	if s, ok := r.pool.Get().(*[]E); ok {
+		return (*s)[:0]
+	}
+
+	return make([]E, 0)
+}
+
+func (r *SlicePool[E]) put(s []E) {
+	if r.MaxCap > 0 && cap(s) > r.MaxCap {
+		return
+	}
+
+	clear(s[:cap(s)])
+	s = s[:0]
+	r.pool.Put(&s)
+}
+
+// PooledSlice holds FindAllPooled rows. Items must not be used after Release,
+// copy entities which outlive the call.
+type PooledSlice[E any] struct {
+	Items []E
+
+	pool *SlicePool[E]
+	once sync.Once
+}
+
+// Release returns Items to the pool, further calls are no-ops.
+func (r *PooledSlice[E]) Release() {
+	r.once.Do(func() {
+		if r.pool != nil && r.Items != nil {
+			r.pool.put(r.Items)
+		}
+
+		r.Items = nil
+	})
+}
+
+// FindAllPooled is FindAll scanning into a slice of the repository Slices pool.
+// Without the pool it allocates like FindAll and Release only drops Items.
+func (r BunCrudRepository[E, T]) FindAllPooled(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+) (*PooledSlice[E], error) {
+	res := &PooledSlice[E]{pool: r.Slices}
+
+	if res.pool != nil {
+		res.Items = res.pool.get()
+	} else {
+		res.Items = make([]E, 0)
+	}
+
+	if err := r.checkColumns("find all", columns...); err != nil {
+		res.Release()
+
+		return nil, fmt.Errorf("find all: %w", err)
+	}
+
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		res.Release()
+
+		return nil, fmt.Errorf("find all: %w", err)
+	}
+
+	query := tx.
+		NewSelect().
+		Model(&res.Items).
+		Column(columns...)
+
+	r.applySpec(query, spec)
+
+	err = query.Scan(ctx)
+	if err != nil {
+		res.Release()
+
+		return nil, fmt.Errorf("find all: %w", dbError(err))
+	}
+
+	r.localize(res.Items)
+
+	if err := r.afterScan(ctx, res.Items); err != nil {
+		res.Release()
+
+		return nil, fmt.Errorf("find all: %w", err)
+	}
+
+	return res, nil
+}
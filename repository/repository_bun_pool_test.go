@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestBunCrudRepository_FindAllPooled(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		pool  *SlicePool[TestSimpleEnt]
+		err   error
+		items []TestSimpleEnt
+	}{
+		{
+			name:  "find all pooled",
+			pool:  NewSlicePool[TestSimpleEnt](0),
+			items: []TestSimpleEnt{{ID: 1, Name: "a"}, {ID: 2, Name: "a"}},
+		},
+		{
+			name:  "find all pooled without pool",
+			items: []TestSimpleEnt{{ID: 1, Name: "a"}, {ID: 2, Name: "a"}},
+		},
+		{
+			name: "find all pooled error",
+			pool: NewSlicePool[TestSimpleEnt](0),
+			err:  errors.New("conn"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+			repo.Slices = tt.pool
+
+			expect := subject.conn.Mock.ExpectQuery("^SELECT .* FROM \"test_simple_entities\" WHERE \\(test_simple_entities.name = 'a'\\)$")
+			if tt.err != nil {
+				expect.WillReturnError(tt.err)
+			} else {
+				expect.WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "a"))
+			}
+
+			res, err := repo.FindAllPooled(context.Background(), nil, nil, dataspec.NewEqual("name", "a"))
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.ErrorIs(t, err, tt.err)
+
+			if tt.err != nil {
+				return
+			}
+
+			assert.Equal(t, tt.items, res.Items)
+
+			res.Release()
+			res.Release()
+
+			assert.Nil(t, res.Items)
+		})
+	}
+}
+
+func TestSlicePool(t *testing.T) {
+	t.Parallel()
+
+	pool := NewSlicePool[TestSimpleEnt](2)
+
+	small := append(pool.get(), TestSimpleEnt{ID: 1, Name: "a"})
+	pool.put(small)
+
+	assert.Equal(t, TestSimpleEnt{}, small[:1][0])
+
+	got := pool.get()
+	assert.Empty(t, got)
+
+	pool.put(make([]TestSimpleEnt, 3))
+}
+
+func BenchmarkBunCrudRepository_FindAllPooled(b *testing.B) {
+	for _, pooled := range []bool{false, true} {
+		pooled := pooled
+
+		b.Run(map[bool]string{false: "find all", true: "pooled"}[pooled], func(b *testing.B) {
+			conn, err := NewMockBunConnSet()
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			repo := NewTestSimpleEntRepository(conn)
+			repo.Slices = NewSlicePool[TestSimpleEnt](0)
+
+			for i := 0; i < b.N; i++ {
+				rows := sqlmock.NewRows([]string{"id", "name"})
+				for j := 0; j < 100; j++ {
+					rows.AddRow(j, "name")
+				}
+
+				conn.Mock.ExpectQuery("SELECT").WillReturnRows(rows)
+			}
+
+			ctx := context.Background()
+			db := bun.NewDB(conn.db, pgdialect.New())
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if pooled {
+					res, _ := repo.FindAllPooled(ctx, db, nil, nil)
+					res.Release()
+				} else {
+					_, _ = repo.FindAll(ctx, db, nil, nil)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// PreviewDelete counts rows Delete would affect for spec without deleting them.
+func (r BunCrudRepository[E, T]) PreviewDelete(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (int, error) {
+	if err := r.guardFullTable(ctx, spec); err != nil {
+		return 0, fmt.Errorf("preview delete: %w", err)
+	}
+
+	count, err := r.Count(ctx, tx, spec)
+	if err != nil {
+		return 0, fmt.Errorf("preview delete: %w", err)
+	}
+
+	return count, nil
+}
+
+// PreviewUpdate counts rows an update of values for spec would affect without updating them.
+// Value keys are presenter or persistence names and must name entity columns.
+func (r BunCrudRepository[E, T]) PreviewUpdate(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	values map[string]any,
+) (int, error) {
+	if err := r.guardFullTable(ctx, spec); err != nil {
+		return 0, fmt.Errorf("preview update: %w", err)
+	}
+
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("preview update: %w", err)
+	}
+
+	if err := r.checkValueColumns(tx, values); err != nil {
+		return 0, fmt.Errorf("preview update: %w", err)
+	}
+
+	count, err := r.Count(ctx, tx, spec)
+	if err != nil {
+		return 0, fmt.Errorf("preview update: %w", err)
+	}
+
+	return count, nil
+}
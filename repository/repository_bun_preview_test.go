@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_Preview(t *testing.T) {
+	t.Parallel()
+
+	countQuery := "^SELECT count\\(\\*\\) FROM \"test_simple_entities\" WHERE \\(test_simple_entities\\.name = 'John'\\)$"
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		call     func(ctx context.Context, repo *TestSimpleEntBunRepo) (int, error)
+		expected func(t *testing.T, res int, err error)
+	}{
+		{
+			name: "preview delete",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery(countQuery).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo) (int, error) {
+				return repo.PreviewDelete(ctx, nil, dataspec.NewEqual("name", "John"))
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 3, res)
+			},
+		},
+		{
+			name: "preview update",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery(countQuery).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+			},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo) (int, error) {
+				return repo.PreviewUpdate(ctx, nil, dataspec.NewEqual("name", "John"), map[string]any{"name": "Jane"})
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 2, res)
+			},
+		},
+		{
+			name: "preview update of unknown column",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo) (int, error) {
+				return repo.PreviewUpdate(ctx, nil, dataspec.NewEqual("name", "John"), map[string]any{"unknown": 1})
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrUnknownColumn)
+			},
+		},
+		{
+			name: "preview update without values",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo) (int, error) {
+				return repo.PreviewUpdate(ctx, nil, dataspec.NewEqual("name", "John"), nil)
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrEmptyValues)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			res, err := tt.call(context.Background(), repo)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+
+			tt.expected(t, res, err)
+		})
+	}
+}
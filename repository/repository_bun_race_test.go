@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBunCrudRepository_ConcurrentUse shares one repository between goroutines, run with -race.
+func TestBunCrudRepository_ConcurrentUse(t *testing.T) {
+	t.Parallel()
+
+	const workers = 8
+
+	subject := crudRepositoryShortTestSetUp(t)
+	subject.conn.Mock.MatchExpectationsInOrder(false)
+
+	repo := NewTestSimpleEntRepository(subject.conn)
+	repo.Strict = NewStrictMode()
+
+	for i := 0; i < workers; i++ {
+		subject.conn.Mock.ExpectQuery(fmt.Sprintf("^SELECT .* FROM \"test_simple_entities\" WHERE \\(test_simple_entities.name = 'n%d'\\)", i)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(i, fmt.Sprintf("n%d", i)))
+		subject.conn.Mock.ExpectQuery(fmt.Sprintf("^SELECT count\\(\\*\\) FROM \"test_simple_entities\" WHERE \\(test_simple_entities.id = %d\\)", i)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		subject.conn.Mock.ExpectQuery(fmt.Sprintf("^INSERT INTO \"test_simple_entities\" \\(\"id\", \"name\"\\) VALUES \\(%d, 'c%d'\\)", 100+i, i)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(100 + i))
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			ctx := context.Background()
+
+			res, err := repo.FindAll(ctx, nil, []string{"id", "name"}, dataspec.NewEqual("name", fmt.Sprintf("n%d", i)))
+			assert.NoError(t, err)
+			assert.Len(t, res, 1)
+
+			count, err := repo.Count(ctx, nil, dataspec.NewEqual("id", i))
+			assert.NoError(t, err)
+			assert.Equal(t, 1, count)
+
+			_, err = repo.CreateOne(ctx, nil, &TestSimpleEnt{ID: 100 + i, Name: fmt.Sprintf("c%d", i)}, []string{"id"})
+			assert.NoError(t, err)
+
+			_, err = repo.FindAll(ctx, nil, []string{"unknown"}, dataspec.NewEqual("id", i))
+			assert.ErrorIs(t, err, ErrStrictViolation)
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.Len(t, repo.Strict.Report(), workers)
+}
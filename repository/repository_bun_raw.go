@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+)
+
+// SafeSQL is a raw SQL snippet explicitly marked as trusted with Safe.
+type SafeSQL struct {
+	sql string
+}
+
+// Safe marks a SQL snippet as trusted for RawExpr and RawSort. It must never wrap user input,
+// values go to RawExpr args instead. Keeping every raw snippet behind Safe makes them greppable.
+func Safe(sql string) SafeSQL {
+	return SafeSQL{sql: sql}
+}
+
+func (r SafeSQL) String() string {
+	return r.sql
+}
+
+// RawSpecification is a raw SQL condition, args are bound to its ? placeholders.
+type RawSpecification struct {
+	expr SafeSQL
+	args []any
+}
+
+// RawExpr builds a spec from a raw condition, it composes with dataspec And and Or specs.
+func RawExpr(expr SafeSQL, args ...any) dataset.Specifier {
+	return &RawSpecification{
+		expr: expr,
+		args: args,
+	}
+}
+
+func (r *RawSpecification) Joins(_ metadata.Meta) []metadata.Join {
+	return nil
+}
+
+func (r *RawSpecification) Query(_ metadata.Meta) string {
+	return "(" + r.expr.sql + ")"
+}
+
+func (r *RawSpecification) Values() []any {
+	return r.args
+}
+
+func (r *RawSpecification) IsEmpty() bool {
+	return strings.TrimSpace(r.expr.sql) == ""
+}
+
+// RawSorter orders by raw expressions, strict mode does not validate them.
+type RawSorter struct {
+	items []SafeSQL
+}
+
+// RawSort builds a sorter from raw order by items such as Safe("lower(name) ASC").
+func RawSort(items ...SafeSQL) RawSorter {
+	return RawSorter{items: items}
+}
+
+func (r RawSorter) OrderBy(_ metadata.Meta) string {
+	clause := make([]string, 0, len(r.items))
+
+	for _, item := range r.items {
+		if s := strings.TrimSpace(item.sql); s != "" {
+			clause = append(clause, s)
+		}
+	}
+
+	return strings.Join(clause, ", ")
+}
+
+func (r RawSorter) IsEmpty() bool {
+	return r.OrderBy(nil) == ""
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_RawExpr(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestComplexEntRepository(subject.conn)
+	repo.Strict = NewStrictMode()
+
+	subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_complex_entities"."complex_name" FROM "test_complex_entities" WHERE ((test_complex_entities.complex_name = 'a' AND (length(complex_name) > 3))) ORDER BY lower(complex_name) DESC, complex_id`) + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"complex_name"}).AddRow("abcd"))
+
+	res, err := repo.FindPage(
+		context.Background(),
+		nil,
+		[]string{"complex_name"},
+		dataspec.NewAnd(
+			dataspec.NewEqual("complexName", "a"),
+			RawExpr(Safe("length(complex_name) > ?"), 3),
+		),
+		nil,
+		RawSort(Safe("lower(complex_name) DESC"), Safe(""), Safe("complex_id")),
+	)
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Empty(t, repo.Strict.Report())
+}
+
+func TestRawSort_IsEmpty(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, RawSort().IsEmpty())
+	assert.True(t, RawSort(Safe(" ")).IsEmpty())
+	assert.False(t, RawSort(Safe("id")).IsEmpty())
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type TestRegionViewEnt struct {
+	bun.BaseModel `bun:"table:test_region_views,alias:test_region_views"`
+
+	Region string  `bun:"region" json:"region"`
+	Total  float64 `bun:"total" json:"total"`
+}
+
+func (r TestRegionViewEnt) EntityName() string {
+	return "TestRegionViewEnt"
+}
+
+func (r TestRegionViewEnt) PrimaryKey() metadata.PrimaryKey {
+	return nil
+}
+
+func (r TestRegionViewEnt) ReadOnly() bool {
+	return true
+}
+
+type TestRegionViewEntMeta struct {
+	TestRegionViewEnt
+}
+
+func (r TestRegionViewEntMeta) Entity() metadata.Entity { return r.TestRegionViewEnt }
+
+func (r TestRegionViewEntMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func newTestRegionViewEntRepository(subject crudRepositoryShortTest) BunCrudRepository[TestRegionViewEnt, bun.Tx] {
+	return BunCrudRepository[TestRegionViewEnt, bun.Tx]{
+		ConnSet: subject.conn,
+		Meta:    meta.Parser(TestRegionViewEntMeta{}),
+	}
+}
+
+func TestBunCrudRepository_ReadOnlyFind(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := newTestRegionViewEntRepository(subject)
+
+	subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_region_views"."region", "test_region_views"."total" FROM "test_region_views" WHERE (test_region_views.region = 'eu')`) + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"region", "total"}).AddRow("eu", 10.5))
+
+	res, err := repo.FindAll(context.Background(), nil, nil, dataspec.NewEqual("region", "eu"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []TestRegionViewEnt{{Region: "eu", Total: 10.5}}, res)
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+}
+
+func TestBunCrudRepository_ReadOnlyRejects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context, repo BunCrudRepository[TestRegionViewEnt, bun.Tx]) error
+		err  error
+	}{
+		{
+			name: "create one",
+			call: func(ctx context.Context, repo BunCrudRepository[TestRegionViewEnt, bun.Tx]) error {
+				_, err := repo.CreateOne(ctx, nil, &TestRegionViewEnt{Region: "eu"}, nil)
+
+				return err //nolint:wrapcheck
+			},
+			err: ErrReadOnlyEntity,
+		},
+		{
+			name: "update by spec",
+			call: func(ctx context.Context, repo BunCrudRepository[TestRegionViewEnt, bun.Tx]) error {
+				_, err := repo.UpdateBySpec(ctx, nil, map[string]any{"total": 1}, dataspec.NewEqual("region", "eu"))
+
+				return err //nolint:wrapcheck
+			},
+			err: ErrReadOnlyEntity,
+		},
+		{
+			name: "delete",
+			call: func(ctx context.Context, repo BunCrudRepository[TestRegionViewEnt, bun.Tx]) error {
+				_, err := repo.Delete(ctx, nil, dataspec.NewEqual("region", "eu"))
+
+				return err //nolint:wrapcheck
+			},
+			err: ErrReadOnlyEntity,
+		},
+		{
+			name: "bulk load",
+			call: func(ctx context.Context, repo BunCrudRepository[TestRegionViewEnt, bun.Tx]) error {
+				_, err := repo.BulkLoad(ctx, []TestRegionViewEnt{{Region: "eu"}})
+
+				return err //nolint:wrapcheck
+			},
+			err: ErrReadOnlyEntity,
+		},
+		{
+			name: "find one by empty pk",
+			call: func(ctx context.Context, repo BunCrudRepository[TestRegionViewEnt, bun.Tx]) error {
+				_, err := repo.FindOneByPk(ctx, nil, nil, TestRegionViewEnt{}.PrimaryKey())
+
+				return err //nolint:wrapcheck
+			},
+			err: ErrEmptyValues,
+		},
+		{
+			name: "batches by pk",
+			call: func(ctx context.Context, repo BunCrudRepository[TestRegionViewEnt, bun.Tx]) error {
+				return repo.ForEachBatchByPk(ctx, nil, nil, 10, func([]TestRegionViewEnt) error { return nil }) //nolint:wrapcheck
+			},
+			err: ErrNoPrimaryKey,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := newTestRegionViewEntRepository(subject)
+
+			err := tt.call(context.Background(), repo)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
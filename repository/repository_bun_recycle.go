@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// Recycled soft delete entity declares how long deleted rows are kept before ReapTrash removes them.
+type Recycled interface {
+	TrashRetention() time.Duration
+}
+
+// Restore undeletes soft deleted rows matching spec, clearing the delete audit columns as well.
+func (r BunCrudRepository[E, T]) Restore(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (int, error) {
+	if err := r.guardFullTable(ctx, spec); err != nil {
+		return 0, fmt.Errorf("restore: %w", err)
+	}
+
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("restore: %w", err)
+	}
+
+	field, err := r.softDeleteField(tx)
+	if err != nil {
+		return 0, fmt.Errorf("restore: %w", err)
+	}
+
+	query := tx.NewUpdate().
+		Model((*E)(nil)).
+		WhereDeleted().
+		Set("? = NULL", bun.Ident(field.Name))
+
+	if da, ok := any(*new(E)).(DeleteAudited); ok {
+		query.
+			Set("? = NULL", bun.Ident(da.DeletedByColumn())).
+			Set("? = NULL", bun.Ident(da.DeletedReasonColumn()))
+	}
+
+	r.whereTrashed(tx, query.QueryBuilder(), spec)
+
+	res, err := query.Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("restore: %w", dbError(err))
+	}
+
+	rows, err := res.RowsAffected()
+
+	return int(rows), err //nolint:wrapcheck
+}
+
+// EmptyTrash permanently deletes soft deleted rows matching spec.
+func (r BunCrudRepository[E, T]) EmptyTrash(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (int, error) {
+	if err := r.guardFullTable(ctx, spec); err != nil {
+		return 0, fmt.Errorf("empty trash: %w", err)
+	}
+
+	rows, err := r.emptyTrash(ctx, tx, spec, time.Time{})
+	if err != nil {
+		return 0, fmt.Errorf("empty trash: %w", err)
+	}
+
+	return rows, nil
+}
+
+// ReapTrash permanently deletes soft deleted rows older than the entity trash retention.
+func (r BunCrudRepository[E, T]) ReapTrash(ctx context.Context, tx bun.IDB) (int, error) {
+	rc, ok := any(*new(E)).(Recycled)
+	if !ok {
+		return 0, fmt.Errorf("reap trash: %w", ErrRecycleNotSupported)
+	}
+
+	rows, err := r.emptyTrash(ctx, tx, nil, time.Now().Add(-rc.TrashRetention()))
+	if err != nil {
+		return 0, fmt.Errorf("reap trash: %w", err)
+	}
+
+	return rows, nil
+}
+
+// PurgeDeleted permanently deletes soft deleted rows deleted more than olderThan ago.
+func (r BunCrudRepository[E, T]) PurgeDeleted(ctx context.Context, tx bun.IDB, olderThan time.Duration) (int, error) {
+	if olderThan < 0 {
+		return 0, fmt.Errorf("purge deleted: %w: negative age %s", ErrInvalidValue, olderThan)
+	}
+
+	rows, err := r.emptyTrash(ctx, tx, nil, time.Now().Add(-olderThan))
+	if err != nil {
+		return 0, fmt.Errorf("purge deleted: %w", err)
+	}
+
+	return rows, nil
+}
+
+// emptyTrash force deletes soft deleted rows matching spec, deleted before the given time unless it's zero.
+func (r BunCrudRepository[E, T]) emptyTrash(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	before time.Time,
+) (int, error) {
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	field, err := r.softDeleteField(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	query := tx.NewDelete().
+		Model((*E)(nil)).
+		WhereDeleted().
+		ForceDelete()
+
+	if !before.IsZero() {
+		query.Where("?TableAlias.? < ?", bun.Ident(field.Name), r.bindValue(before))
+	}
+
+	r.whereTrashed(tx, query.QueryBuilder(), spec)
+
+	res, err := query.Exec(ctx)
+	if err != nil {
+		return 0, dbError(err)
+	}
+
+	rows, err := res.RowsAffected()
+
+	return int(rows), err //nolint:wrapcheck
+}
+
+// whereTrashed adds the spec condition to a statement on soft deleted rows. Update and delete statements
+// can't join, so rows of specs joining relations are selected by ctid.
+func (r BunCrudRepository[E, T]) whereTrashed(tx bun.IDB, query bun.QueryBuilder, spec dataset.Specifier) {
+	applied := r.evalSpec(spec)
+	if applied == nil {
+		return
+	}
+
+	if !applied.joined() {
+		query.Where(applied.query, applied.values...)
+
+		return
+	}
+
+	sub := tx.NewSelect().
+		Model((*E)(nil)).
+		ColumnExpr("?TableAlias.ctid").
+		WhereDeleted()
+	applied.apply(sub)
+
+	query.Where("ctid IN (?)", sub)
+}
+
+func (r BunCrudRepository[E, T]) softDeleteField(tx bun.IDB) (*schema.Field, error) {
+	field := tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem()).SoftDeleteField
+	if field == nil {
+		return nil, ErrSoftDeleteNotSupported
+	}
+
+	return field, nil
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type testOwnerSpec struct {
+	name string
+}
+
+func (r testOwnerSpec) Joins(metadata.Meta) []metadata.Join {
+	return []metadata.Join{{JoinString: "JOIN owners ON owners.id = test_audited_entities.id"}}
+}
+
+func (r testOwnerSpec) Query(metadata.Meta) string { return "owners.name = ?" }
+
+func (r testOwnerSpec) Values() []any { return []any{r.name} }
+
+func (r testOwnerSpec) IsEmpty() bool { return false }
+
+func TestBunCrudRepository_Restore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		prepare  func(repo *BunCrudRepository[TestAuditedEnt, bun.Tx])
+		spec     dataset.Specifier
+		expected func(t *testing.T, res int, err error)
+	}{
+		{
+			name: "restore",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^UPDATE \"test_audited_entities\" AS \"test_audited_entities\" SET \"deleted_at\" = NULL, \"deleted_by\" = NULL, \"deleted_reason\" = NULL WHERE \\(test_audited_entities.id = 1\\) AND \"test_audited_entities\".\"deleted_at\" IS NOT NULL$").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			spec: dataspec.NewEqual("id", 1),
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 1, res)
+			},
+		},
+		{
+			name: "restore with joined spec",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`UPDATE "test_audited_entities" AS "test_audited_entities" SET "deleted_at" = NULL, "deleted_by" = NULL, "deleted_reason" = NULL WHERE (ctid IN (SELECT "test_audited_entities".ctid FROM "test_audited_entities" JOIN owners ON owners.id = test_audited_entities.id WHERE (owners.name = 'a') AND "test_audited_entities"."deleted_at" IS NOT NULL)) AND "test_audited_entities"."deleted_at" IS NOT NULL`) + "$").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			spec: testOwnerSpec{name: "a"},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 2, res)
+			},
+		},
+		{
+			name: "restore with location",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec(regexp.QuoteMeta(`WHERE (test_audited_entities.deleted_at > '2024-01-01 03:00:00+03:00')`)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			prepare: func(repo *BunCrudRepository[TestAuditedEnt, bun.Tx]) {
+				repo.Location = time.FixedZone("UTC+3", 3*3600)
+			},
+			spec: dataspec.NewGt("deletedAt", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "restore full table",
+			mock: func(conn *MockBunConnSet) {},
+			prepare: func(repo *BunCrudRepository[TestAuditedEnt, bun.Tx]) {
+				repo.GuardFullTable = true
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrFullTable)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestAuditedEntRepository(subject.conn)
+			if tt.prepare != nil {
+				tt.prepare(&repo)
+			}
+
+			tt.mock(subject.conn)
+
+			res, err := repo.Restore(context.Background(), nil, tt.spec)
+			tt.expected(t, res, err)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestBunCrudRepository_EmptyTrash(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestAuditedEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectExec("^DELETE FROM \"test_audited_entities\" AS \"test_audited_entities\" WHERE \\(test_audited_entities.id = 1\\) AND \"test_audited_entities\".\"deleted_at\" IS NOT NULL$").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res, err := repo.EmptyTrash(context.Background(), nil, dataspec.NewEqual("id", 1))
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res)
+}
+
+func TestBunCrudRepository_ReapTrash(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		reap     func(conn *MockBunConnSet) (int, error)
+		expected func(t *testing.T, res int, err error)
+	}{
+		{
+			name: "reap trash",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^DELETE FROM \"test_audited_entities\" AS \"test_audited_entities\" WHERE \\(\"test_audited_entities\".\"deleted_at\" < '.+'\\) AND \"test_audited_entities\".\"deleted_at\" IS NOT NULL$").
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			reap: func(conn *MockBunConnSet) (int, error) {
+				return NewTestAuditedEntRepository(conn).ReapTrash(context.Background(), nil)
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 3, res)
+			},
+		},
+		{
+			name: "reap trash without retention",
+			mock: func(conn *MockBunConnSet) {},
+			reap: func(conn *MockBunConnSet) (int, error) {
+				return NewTestSoftDeleteEntRepository(conn).ReapTrash(context.Background(), nil)
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrRecycleNotSupported)
+			},
+		},
+		{
+			name: "purge deleted",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^DELETE FROM \"test_soft_delete_entities\" AS \"test_soft_delete_entities\" WHERE \\(\"test_soft_delete_entities\".\"deleted_at\" < '.+'\\) AND \"test_soft_delete_entities\".\"deleted_at\" IS NOT NULL$").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			reap: func(conn *MockBunConnSet) (int, error) {
+				return NewTestSoftDeleteEntRepository(conn).PurgeDeleted(context.Background(), nil, 30*24*time.Hour)
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 2, res)
+			},
+		},
+		{
+			name: "purge deleted with negative age",
+			mock: func(conn *MockBunConnSet) {},
+			reap: func(conn *MockBunConnSet) (int, error) {
+				return NewTestSoftDeleteEntRepository(conn).PurgeDeleted(context.Background(), nil, -time.Hour)
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrInvalidValue)
+			},
+		},
+		{
+			name: "purge deleted without soft delete",
+			mock: func(conn *MockBunConnSet) {},
+			reap: func(conn *MockBunConnSet) (int, error) {
+				return NewTestSimpleEntRepository(conn).PurgeDeleted(context.Background(), nil, time.Hour)
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrSoftDeleteNotSupported)
+			},
+		},
+		{
+			name: "empty trash without soft delete",
+			mock: func(conn *MockBunConnSet) {},
+			reap: func(conn *MockBunConnSet) (int, error) {
+				return NewTestSimpleEntRepository(conn).EmptyTrash(context.Background(), nil, nil)
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrSoftDeleteNotSupported)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			tt.mock(subject.conn)
+
+			res, err := tt.reap(subject.conn)
+			tt.expected(t, res, err)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
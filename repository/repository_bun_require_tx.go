@@ -0,0 +1,23 @@
+package repository
+
+import "context"
+
+type autocommitCtxKey struct{}
+
+// WithAutocommit allows write operations of a RequireTx repository to run on the write pool without a transaction.
+func WithAutocommit(ctx context.Context) context.Context {
+	return context.WithValue(ctx, autocommitCtxKey{}, true)
+}
+
+// requireTx rejects writes without a transaction unless the call allows autocommit.
+func (r BunCrudRepository[E, T]) requireTx(ctx context.Context) error {
+	if !r.RequireTx {
+		return nil
+	}
+
+	if allowed, _ := ctx.Value(autocommitCtxKey{}).(bool); allowed {
+		return nil
+	}
+
+	return ErrTxRequired
+}
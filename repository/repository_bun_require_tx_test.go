@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+func TestBunCrudRepository_RequireTx(t *testing.T) {
+	t.Parallel()
+
+	insertQuery := "^INSERT INTO \"test_simple_entities\" \\(\"id\", \"name\"\\) VALUES \\(0, 'a'\\) RETURNING \\*$"
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		call     func(ctx context.Context, repo *TestSimpleEntBunRepo, conn *MockBunConnSet) error
+		expected error
+	}{
+		{
+			name: "require tx without tx",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo, _ *MockBunConnSet) error {
+				_, err := repo.CreateOne(ctx, nil, &TestSimpleEnt{Name: "a"}, []string{"*"})
+
+				return err
+			},
+			expected: ErrTxRequired,
+		},
+		{
+			name: "require tx with autocommit",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery(insertQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+			},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo, _ *MockBunConnSet) error {
+				_, err := repo.CreateOne(WithAutocommit(ctx), nil, &TestSimpleEnt{Name: "a"}, []string{"*"})
+
+				return err
+			},
+		},
+		{
+			name: "require tx with tx",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectBegin()
+				conn.Mock.ExpectQuery(insertQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+				conn.Mock.ExpectCommit()
+			},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo, conn *MockBunConnSet) error {
+				return conn.WritePool().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+					_, err := repo.CreateOne(ctx, tx, &TestSimpleEnt{Name: "a"}, []string{"*"})
+
+					return err
+				})
+			},
+		},
+		{
+			name: "require tx read without tx",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^SELECT .* FROM \"test_simple_entities\"").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+			},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo, _ *MockBunConnSet) error {
+				_, err := repo.FindOne(ctx, nil, nil, dataspec.NewEqual("name", "a"))
+
+				return err
+			},
+		},
+		{
+			name: "require tx with pool as tx",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo, conn *MockBunConnSet) error {
+				_, err := repo.CreateOne(ctx, conn.WritePool(), &TestSimpleEnt{Name: "a"}, []string{"*"})
+
+				return err
+			},
+			expected: ErrTxRequired,
+		},
+		{
+			name: "require tx locked create without tx",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectBegin()
+				conn.Mock.ExpectExec("^SELECT pg_advisory_xact_lock").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				conn.Mock.ExpectQuery("^SELECT .* FROM \"test_simple_entities\"").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+				conn.Mock.ExpectCommit()
+			},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo, _ *MockBunConnSet) error {
+				_, err := repo.FirstOrCreateLocked(ctx, nil, "a", dataspec.NewEqual("name", "a"), &TestSimpleEnt{Name: "a"}, nil)
+
+				return err
+			},
+		},
+		{
+			name: "require tx locked create with pool as tx",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectBegin()
+				conn.Mock.ExpectExec("^SELECT pg_advisory_xact_lock").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				conn.Mock.ExpectQuery("^SELECT .* FROM \"test_simple_entities\"").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+				conn.Mock.ExpectCommit()
+			},
+			call: func(ctx context.Context, repo *TestSimpleEntBunRepo, conn *MockBunConnSet) error {
+				_, err := repo.FirstOrCreateLocked(ctx, conn.WritePool(), "a", dataspec.NewEqual("name", "a"), &TestSimpleEnt{Name: "a"}, nil)
+
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+			repo.RequireTx = true
+
+			tt.mock(subject.conn)
+
+			err := tt.call(context.Background(), repo, subject.conn)
+
+			assert.ErrorIs(t, err, tt.expected)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
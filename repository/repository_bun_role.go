@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aso779/bun-pg-connector"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/uptrace/bun"
+)
+
+// Role database role a connection pool is logged in as, e.g. migration, app or reporting user.
+type Role string
+
+type roleCtxKey struct{}
+
+// WithRole makes repository operations without an explicit tx use the pool of the role.
+func WithRole(ctx context.Context, role Role) context.Context {
+	return context.WithValue(ctx, roleCtxKey{}, role)
+}
+
+func RoleFromContext(ctx context.Context) (Role, bool) {
+	role, ok := ctx.Value(roleCtxKey{}).(Role)
+
+	return role, ok
+}
+
+type rolePools interface {
+	Pool(role Role) (*bun.DB, error)
+}
+
+// RoleConnSet connection set with additional pools per role. The embedded set serves operations
+// without a role. Pass a role pool as tx to select it for a single operation.
+type RoleConnSet struct {
+	bunpgconnector.BunConnSet
+
+	Pools map[Role]*bun.DB
+}
+
+func NewRoleConnSet(connSet bunpgconnector.BunConnSet, pools map[Role]*bun.DB) RoleConnSet {
+	return RoleConnSet{
+		BunConnSet: connSet,
+		Pools:      pools,
+	}
+}
+
+// Pool returns the pool of the role, there's no fallback to the default pools.
+func (r RoleConnSet) Pool(role Role) (*bun.DB, error) {
+	pool, ok := r.Pools[role]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", role, ErrUnknownRole)
+	}
+
+	return pool, nil
+}
+
+// readDB returns tx when set, otherwise the pool of the context role or the read pool.
+func (r BunCrudRepository[E, T]) readDB(ctx context.Context, tx bun.IDB) (bun.IDB, error) {
+	if tx != nil {
+		routed(ctx, TargetTx, "")
+
+		return tx, nil
+	}
+
+	return r.rolePool(ctx, r.ConnSet.ReadPool, TargetReplica)
+}
+
+// writeDB returns tx when set, otherwise the pool of the context role or the write pool.
+// A nil tx or a pool passed as tx fails with ErrTxRequired for RequireTx repositories,
+// read-only entities fail with ErrReadOnlyEntity.
+func (r BunCrudRepository[E, T]) writeDB(ctx context.Context, tx bun.IDB) (bun.IDB, error) {
+	if r.readOnly() {
+		return nil, ErrReadOnlyEntity
+	}
+
+	return r.primaryDB(ctx, tx)
+}
+
+// primaryDB is writeDB for statements not writing entity rows, e.g. view refreshes.
+func (r BunCrudRepository[E, T]) primaryDB(ctx context.Context, tx bun.IDB) (bun.IDB, error) {
+	if tx != nil {
+		if !isTx(tx) {
+			if err := r.requireTx(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		routed(ctx, TargetTx, "")
+
+		return tx, nil
+	}
+
+	if err := r.requireTx(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.rolePool(ctx, r.ConnSet.WritePool, TargetPrimary)
+}
+
+// isTx reports whether db is a transaction rather than a pool or connection.
+func isTx(db bun.IDB) bool {
+	switch db.(type) {
+	case bun.Tx, *bun.Tx:
+		return true
+	default:
+		return false
+	}
+}
+
+// readOnly reports whether E is a meta.ReadOnly entity.
+func (r BunCrudRepository[E, T]) readOnly() bool {
+	readOnly, ok := any(*new(E)).(meta.ReadOnly)
+
+	return ok && readOnly.ReadOnly()
+}
+
+func (r BunCrudRepository[E, T]) rolePool(
+	ctx context.Context,
+	fallback func() *bun.DB,
+	target Target,
+) (bun.IDB, error) {
+	role, ok := RoleFromContext(ctx)
+	if !ok {
+		routed(ctx, target, "")
+
+		return fallback(), nil
+	}
+
+	roles, ok := r.ConnSet.(rolePools)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", role, ErrUnknownRole)
+	}
+
+	pool, err := roles.Pool(role)
+	if err != nil {
+		return nil, err
+	}
+
+	routed(ctx, TargetRole, role)
+
+	return pool, nil
+}
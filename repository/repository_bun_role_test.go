@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+func TestBunCrudRepository_Role(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ctx      func() context.Context
+		mock     func(app, reporting *MockBunConnSet)
+		expected func(t *testing.T, err error)
+	}{
+		{
+			name: "without role",
+			ctx:  context.Background,
+			mock: func(app, reporting *MockBunConnSet) {
+				app.Mock.ExpectQuery("^SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			expected: func(t *testing.T, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "with role",
+			ctx: func() context.Context {
+				return WithRole(context.Background(), "reporting")
+			},
+			mock: func(app, reporting *MockBunConnSet) {
+				reporting.Mock.ExpectQuery("^SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			expected: func(t *testing.T, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "with unknown role",
+			ctx: func() context.Context {
+				return WithRole(context.Background(), "migration")
+			},
+			mock: func(app, reporting *MockBunConnSet) {},
+			expected: func(t *testing.T, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrUnknownRole)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := crudRepositoryShortTestSetUp(t).conn
+			reporting := crudRepositoryShortTestSetUp(t).conn
+			repo := NewTestSimpleEntRepository(NewRoleConnSet(app, map[Role]*bun.DB{
+				"reporting": reporting.ReadPool(),
+			}))
+			tt.mock(app, reporting)
+
+			_, err := repo.FindAll(tt.ctx(), nil, nil, nil)
+			tt.expected(t, err)
+			assert.NoError(t, app.Mock.ExpectationsWereMet())
+			assert.NoError(t, reporting.Mock.ExpectationsWereMet())
+		})
+	}
+}
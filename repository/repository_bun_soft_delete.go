@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// DeleteAudited soft delete entity declares persistence columns recording who deleted a row and why.
+type DeleteAudited interface {
+	DeletedByColumn() string
+	DeletedReasonColumn() string
+}
+
+type deleteActorCtxKey struct{}
+
+type deleteActor struct {
+	actor  string
+	reason string
+}
+
+// WithDeleteActor makes Delete of DeleteAudited entities record the actor and reason.
+func WithDeleteActor(ctx context.Context, actor, reason string) context.Context {
+	return context.WithValue(ctx, deleteActorCtxKey{}, deleteActor{actor: actor, reason: reason})
+}
+
+// FindTrashed returns soft deleted rows matching spec.
+func (r BunCrudRepository[E, T]) FindTrashed(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+) ([]E, error) {
+	var entities = make([]E, 0)
+
+	if err := r.checkColumns("find trashed", columns...); err != nil {
+		return entities, fmt.Errorf("find trashed: %w", err)
+	}
+
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return entities, fmt.Errorf("find trashed: %w", err)
+	}
+
+	query := tx.
+		NewSelect().
+		Model(&entities).
+		Column(columns...).
+		WhereDeleted()
+
+	r.applySpec(query, spec)
+
+	err = query.Scan(ctx)
+	if err != nil {
+		return entities, fmt.Errorf("find trashed: %w", dbError(err))
+	}
+
+	r.localize(entities)
+
+	if err := r.afterScan(ctx, entities); err != nil {
+		return entities, fmt.Errorf("find trashed: %w", err)
+	}
+
+	return entities, nil
+}
+
+// auditedDelete builds the soft delete update recording the context actor,
+// it returns nil when the entity or context has nothing to record.
+func (r BunCrudRepository[E, T]) auditedDelete(ctx context.Context, tx bun.IDB) *bun.UpdateQuery {
+	da, ok := any(*new(E)).(DeleteAudited)
+	if !ok {
+		return nil
+	}
+
+	actor, ok := ctx.Value(deleteActorCtxKey{}).(deleteActor)
+	if !ok {
+		return nil
+	}
+
+	table := tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem())
+	if table.SoftDeleteField == nil {
+		return nil
+	}
+
+	return tx.NewUpdate().
+		Model((*E)(nil)).
+		Set("? = ?", bun.Ident(table.SoftDeleteField.Name), r.bindValue(time.Now())).
+		Set("? = ?", bun.Ident(da.DeletedByColumn()), actor.actor).
+		Set("? = ?", bun.Ident(da.DeletedReasonColumn()), actor.reason)
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/bun-pg-connector"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type TestAuditedEnt struct {
+	bun.BaseModel `bun:"table:test_audited_entities,alias:test_audited_entities"`
+
+	ID            int       `bun:"id,pk" json:"id"`
+	Name          string    `bun:"name" json:"name"`
+	DeletedAt     time.Time `bun:"deleted_at,soft_delete,nullzero" json:"deletedAt"`
+	DeletedBy     string    `bun:"deleted_by,nullzero" json:"deletedBy"`
+	DeletedReason string    `bun:"deleted_reason,nullzero" json:"deletedReason"`
+}
+
+func (r TestAuditedEnt) EntityName() string {
+	return "TestAuditedEnt"
+}
+
+func (r TestAuditedEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+func (r TestAuditedEnt) DeletedByColumn() string {
+	return "deleted_by"
+}
+
+func (r TestAuditedEnt) DeletedReasonColumn() string {
+	return "deleted_reason"
+}
+
+func (r TestAuditedEnt) TrashRetention() time.Duration {
+	return 30 * 24 * time.Hour
+}
+
+type TestAuditedEntMeta struct {
+	TestAuditedEnt
+}
+
+func (r TestAuditedEntMeta) Entity() metadata.Entity { return r.TestAuditedEnt }
+
+func (r TestAuditedEntMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func NewTestAuditedEntRepository(
+	connSet bunpgconnector.BunConnSet,
+) BunCrudRepository[TestAuditedEnt, bun.Tx] {
+	return BunCrudRepository[TestAuditedEnt, bun.Tx]{
+		ConnSet: connSet,
+		Meta:    meta.Parser(TestAuditedEntMeta{}),
+	}
+}
+
+func TestBunCrudRepository_DeleteWithActor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ctx  func() context.Context
+		mock func(set *MockBunConnSet)
+	}{
+		{
+			name: "delete with actor",
+			ctx: func() context.Context {
+				return WithDeleteActor(context.Background(), "admin", "gdpr request")
+			},
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^UPDATE \"test_audited_entities\" AS \"test_audited_entities\" SET \"deleted_at\" = '.+', \"deleted_by\" = 'admin', \"deleted_reason\" = 'gdpr request' WHERE \\(test_audited_entities.id = 1\\) AND \"test_audited_entities\".\"deleted_at\" IS NULL$").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "delete without actor",
+			ctx:  context.Background,
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^UPDATE \"test_audited_entities\" AS \"test_audited_entities\" SET \"deleted_at\" = '.+' WHERE \\(test_audited_entities.id = 1\\) AND \"test_audited_entities\".\"deleted_at\" IS NULL$").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestAuditedEntRepository(subject.conn)
+			tt.mock(subject.conn)
+
+			res, err := repo.Delete(tt.ctx(), nil, dataspec.NewEqual("id", 1))
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.NoError(t, err)
+			assert.Equal(t, 1, res)
+		})
+	}
+}
+
+func TestBunCrudRepository_DeleteWithActorLocation(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestAuditedEntRepository(subject.conn)
+	repo.Location = time.FixedZone("UTC+3", 3*3600)
+
+	subject.conn.Mock.ExpectExec("^UPDATE \"test_audited_entities\" AS \"test_audited_entities\" SET \"deleted_at\" = '[^']+\\+03:00', \"deleted_by\" = 'admin', \"deleted_reason\" = 'cleanup' WHERE \\(test_audited_entities.name < '2024-03-01 03:00:00\\+03:00'\\) AND \"test_audited_entities\".\"deleted_at\" IS NULL$").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res, err := repo.Delete(WithDeleteActor(context.Background(), "admin", "cleanup"), nil, dataspec.NewLt("name", testDate(3)))
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res)
+}
+
+func TestBunCrudRepository_FindTrashed(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestAuditedEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectQuery("^SELECT .* FROM \"test_audited_entities\" WHERE \\(test_audited_entities.id = 1\\) AND \"test_audited_entities\".\"deleted_at\" IS NOT NULL$").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "deleted_by", "deleted_reason"}).AddRow(1, "admin", "gdpr request"))
+
+	res, err := repo.FindTrashed(context.Background(), nil, nil, dataspec.NewEqual("id", 1))
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", res[0].DeletedBy)
+	assert.Equal(t, "gdpr request", res[0].DeletedReason)
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/aso779/crud-repository/entrel"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// checkRelated verifies rows referenced by to-one relation columns of the entities exist, when SoftForeignKeys
+// is set. NULL references are skipped, with columns given only relations using one of them are checked.
+func (r BunCrudRepository[E, T]) checkRelated(ctx context.Context, tx bun.IDB, entities []E, columns []string) error {
+	if !r.SoftForeignKeys || len(entities) == 0 {
+		return nil
+	}
+
+	relations := r.Meta.Relations()
+	names := sortedKeys(relations)
+	table := tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem())
+
+	for _, name := range names {
+		toOne, ok := relations[name].(entrel.ToOne)
+		if !ok || len(toOne.JoinColumns) == 0 {
+			continue
+		}
+
+		if len(columns) > 0 && !slices.ContainsFunc(toOne.JoinColumns, func(c entrel.JoinColumn) bool {
+			return slices.Contains(columns, unqualified(c.Name))
+		}) {
+			continue
+		}
+
+		checked := make(map[string]bool)
+
+		for i := range entities {
+			values, err := referenceValues(table, reflect.ValueOf(&entities[i]).Elem(), toOne)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+
+			key := fmt.Sprint(values...)
+			if values == nil || checked[key] {
+				continue
+			}
+
+			checked[key] = true
+
+			query := tx.NewSelect().TableExpr("?", bun.Ident(toOne.JoinTable))
+			for j, c := range toOne.JoinColumns {
+				query.Where("? = ?", bun.Ident(c.ReferencedName), values[j])
+			}
+
+			exists, err := query.Exists(ctx)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, dbError(err))
+			}
+
+			if !exists {
+				return fmt.Errorf("%s: %w", name, ErrRelatedNotFound)
+			}
+		}
+	}
+
+	return nil
+}
+
+// referenceValues returns values of the relation columns of the entity, nil when any of them is NULL.
+func referenceValues(table *schema.Table, entity reflect.Value, toOne entrel.ToOne) ([]any, error) {
+	values := make([]any, 0, len(toOne.JoinColumns))
+
+	for _, c := range toOne.JoinColumns {
+		field := table.LookupField(unqualified(c.Name))
+		if field == nil {
+			return nil, fmt.Errorf("%s: %w", c.Name, ErrUnknownColumn)
+		}
+
+		v := field.Value(entity)
+		if (field.IsPtr && v.IsNil()) || (field.NullZero && field.IsZero(v)) {
+			return nil, nil
+		}
+
+		values = append(values, v.Interface())
+	}
+
+	return values, nil
+}
+
+func unqualified(column string) string {
+	return column[strings.LastIndex(column, ".")+1:]
+}
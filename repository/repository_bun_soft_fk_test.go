@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+func TestBunCrudRepository_SoftForeignKeys(t *testing.T) {
+	t.Parallel()
+
+	exists := "^" + regexp.QuoteMeta(`SELECT EXISTS (SELECT * FROM "test_items" WHERE ("id" = 10))`) + "$"
+
+	tests := []struct {
+		name  string
+		mock  func(conn *MockBunConnSet)
+		write func(repo BunCrudRepository[TestCategoryEnt, bun.Tx]) error
+		err   error
+	}{
+		{
+			name: "create one with existing related row",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery(exists).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				conn.Mock.ExpectQuery("^INSERT INTO \"test_categories\"").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+			write: func(repo BunCrudRepository[TestCategoryEnt, bun.Tx]) error {
+				_, err := repo.CreateOne(context.Background(), nil, &TestCategoryEnt{ID: 1, MainItemID: 10}, []string{"id"})
+
+				return err
+			},
+		},
+		{
+			name: "create all with missing related row",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery(exists).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			write: func(repo BunCrudRepository[TestCategoryEnt, bun.Tx]) error {
+				_, err := repo.CreateAll(context.Background(), nil, []TestCategoryEnt{
+					{ID: 1, MainItemID: 10},
+					{ID: 2, MainItemID: 10},
+				}, nil)
+
+				return err
+			},
+			err: ErrRelatedNotFound,
+		},
+		{
+			name: "update of other columns is not checked",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^UPDATE \"test_categories\"").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			write: func(repo BunCrudRepository[TestCategoryEnt, bun.Tx]) error {
+				_, err := repo.UpdateOne(context.Background(), nil, &TestCategoryEnt{ID: 1, MainItemID: 10}, []string{"name"}, nil)
+
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestCategoryEntRepository(subject.conn).BunCrudRepository
+			repo.SoftForeignKeys = true
+
+			tt.mock(subject.conn)
+
+			err := tt.write(repo)
+
+			assert.ErrorIs(t, err, tt.err)
+			if tt.err != nil {
+				assert.ErrorContains(t, err, "MainItem")
+			}
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"github.com/aso779/crud-repository/spec"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+)
+
+// SpecKey returns a stable key of the spec, equal for specs differing only in branch or value order
+// of spec package And, Or and In conditions, see spec.Canonical.
+// It is suitable for count caches, singleflight groups and query plan logging.
+func (r BunCrudRepository[E, T]) SpecKey(s dataset.Specifier) string {
+	return spec.Key(r.ConnSet.ReadPool().Dialect(), r.specMeta(), s)
+}
+
+// CanonicalSpec returns the canonical condition SpecKey is computed from.
+func (r BunCrudRepository[E, T]) CanonicalSpec(s dataset.Specifier) string {
+	return spec.Canonical(r.ConnSet.ReadPool().Dialect(), r.specMeta(), s)
+}
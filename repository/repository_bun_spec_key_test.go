@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aso779/crud-repository/spec"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+type testBetweenSpec struct {
+	from, to int
+}
+
+func (r testBetweenSpec) Joins(metadata.Meta) []metadata.Join { return nil }
+
+func (r testBetweenSpec) Query(meta metadata.Meta) string {
+	return meta.PersistenceName() + ".id BETWEEN ? AND ?"
+}
+
+func (r testBetweenSpec) Values() []any { return []any{r.from, r.to} }
+
+func (r testBetweenSpec) IsEmpty() bool { return false }
+
+func TestBunCrudRepository_SpecKey(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestSimpleEntRepository(subject.conn)
+
+	name := "a"
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600))
+
+	tests := []struct {
+		name  string
+		left  dataset.Specifier
+		right dataset.Specifier
+		equal bool
+	}{
+		{
+			name:  "and branches order",
+			left:  spec.NewAnd(spec.NewEqual("name", "a"), spec.NewEqual("id", 1)),
+			right: spec.NewAnd(spec.NewEqual("id", 1), spec.NewEqual("name", "a")),
+			equal: true,
+		},
+		{
+			name: "nested or branches order",
+			left: spec.NewAnd(
+				spec.NewOr(spec.NewEqual("name", "a"), spec.NewEqual("name", "b")),
+				spec.NewEqual("id", 1),
+			),
+			right: spec.NewAnd(
+				spec.NewEqual("id", 1),
+				spec.NewOr(spec.NewEqual("name", "b"), spec.NewEqual("name", "a")),
+			),
+			equal: true,
+		},
+		{
+			name:  "in list order",
+			left:  spec.NewAnd(spec.NewIn("id", []int{3, 1, 2})),
+			right: spec.NewAnd(spec.NewIn("id", []int{1, 2, 3, 2})),
+			equal: true,
+		},
+		{
+			name:  "time zone",
+			left:  spec.NewAnd(spec.NewEqual("name", at)),
+			right: spec.NewAnd(spec.NewEqual("name", at.UTC())),
+			equal: true,
+		},
+		{
+			name:  "pointer value",
+			left:  spec.NewAnd(spec.NewEqual("name", &name)),
+			right: spec.NewAnd(spec.NewEqual("name", "a")),
+			equal: true,
+		},
+		{
+			name:  "and or differ",
+			left:  spec.NewAnd(spec.NewEqual("name", "a"), spec.NewEqual("id", 1)),
+			right: spec.NewAnd(spec.NewOr(spec.NewEqual("name", "a"), spec.NewEqual("id", 1))),
+			equal: false,
+		},
+		{
+			name:  "nested and flattened",
+			left:  spec.NewAnd(spec.NewEqual("name", "a"), spec.NewAnd(spec.NewEqual("id", 1), spec.NewEqual("id", 2))),
+			right: spec.NewAnd(spec.NewAnd(spec.NewEqual("id", 2), spec.NewEqual("name", "a")), spec.NewEqual("id", 1)),
+			equal: true,
+		},
+		{
+			name:  "between bounds",
+			left:  spec.NewAnd(testBetweenSpec{from: 1, to: 2}, spec.NewEqual("name", "a")),
+			right: spec.NewAnd(testBetweenSpec{from: 2, to: 1}, spec.NewEqual("name", "a")),
+			equal: false,
+		},
+		{
+			name:  "foreign and not reordered",
+			left:  dataspec.NewAnd(dataspec.NewEqual("name", "a"), dataspec.NewEqual("id", 1)),
+			right: dataspec.NewAnd(dataspec.NewEqual("id", 1), dataspec.NewEqual("name", "a")),
+			equal: false,
+		},
+		{
+			name:  "quoted separator",
+			left:  spec.NewAnd(spec.NewEqual("name", "a AND b")),
+			right: spec.NewAnd(spec.NewEqual("name", "b AND a")),
+			equal: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			left, right := repo.SpecKey(tt.left), repo.SpecKey(tt.right)
+
+			if tt.equal {
+				assert.Equal(t, left, right, "%s != %s", repo.CanonicalSpec(tt.left), repo.CanonicalSpec(tt.right))
+			} else {
+				assert.NotEqual(t, left, right)
+			}
+		})
+	}
+}
+
+func TestBunCrudRepository_CanonicalSpec(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestSimpleEntRepository(subject.conn)
+
+	assert.Equal(t, "", repo.CanonicalSpec(nil))
+	assert.Equal(
+		t,
+		"(test_simple_entities.id IN (1, 2) AND test_simple_entities.name = 'a')",
+		repo.CanonicalSpec(spec.NewAnd(
+			spec.NewEqual("name", "a"),
+			spec.NewIn("id", []int{2, 1}),
+			spec.NewEqual("name", "a"),
+		)),
+	)
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+const (
+	ViolationColumn = "column"
+	ViolationOrder  = "order"
+)
+
+// Violation input rejected by the strict mode.
+type Violation struct {
+	Entity string
+	Op     string
+	Kind   string
+	Input  string
+}
+
+// DefaultMaxViolations number of violations StrictMode keeps when MaxViolations isn't set.
+const DefaultMaxViolations = 1000
+
+// StrictMode makes the repository reject column, returning and order input that doesn't resolve
+// to entity columns through metadata instead of passing it to the query. Rejected input is logged
+// and the latest MaxViolations of it are kept for Report. It's opt-in via the repository Strict field
+// and safe for concurrent use.
+type StrictMode struct {
+	Logger *slog.Logger
+	// MaxViolations caps collected violations, older ones are dropped first; DefaultMaxViolations when not positive.
+	MaxViolations int
+
+	mu sync.Mutex
+	// violations ring buffer, next is the position of the oldest one once it's full
+	violations []Violation
+	next       int
+	dropped    int
+}
+
+func NewStrictMode() *StrictMode {
+	return &StrictMode{}
+}
+
+// Report returns violations collected since creation or the last Reset, oldest first.
+func (r *StrictMode) Report() []Violation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make([]Violation, 0, len(r.violations))
+	res = append(res, r.violations[r.next:]...)
+
+	return append(res, r.violations[:r.next]...)
+}
+
+// Dropped returns the number of violations dropped from Report since creation or the last Reset.
+func (r *StrictMode) Dropped() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.dropped
+}
+
+func (r *StrictMode) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.violations = nil
+	r.next = 0
+	r.dropped = 0
+}
+
+func (r *StrictMode) record(v Violation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	limit := r.MaxViolations
+	if limit <= 0 {
+		limit = DefaultMaxViolations
+	}
+
+	if len(r.violations) < limit {
+		r.violations = append(r.violations, v)
+
+		return
+	}
+
+	r.violations[r.next] = v
+	r.next = (r.next + 1) % len(r.violations)
+	r.dropped++
+}
+
+func (r *StrictMode) reject(v Violation) error {
+	r.record(v)
+
+	logger := r.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	logger.Warn("strict mode violation", "entity", v.Entity, "op", v.Op, "kind", v.Kind, "input", v.Input)
+
+	return fmt.Errorf("%s %q: %w", v.Kind, v.Input, ErrStrictViolation)
+}
+
+// checkColumns rejects columns other than "*" and entity persistence names in strict mode.
+func (r BunCrudRepository[E, T]) checkColumns(op string, columns ...string) error {
+	if r.Strict == nil {
+		return nil
+	}
+
+	known := r.Meta.PersistencePresenterMapping()
+
+	for _, c := range columns {
+		if _, ok := known[c]; ok || c == "*" {
+			continue
+		}
+
+		return r.Strict.reject(Violation{Entity: r.Meta.EntityName(), Op: op, Kind: ViolationColumn, Input: c})
+	}
+
+	return nil
+}
+
+// checkOrder accepts comma separated "[table.]column [ASC|DESC] [NULLS FIRST|LAST]" items in strict mode,
+// identifiers may be double-quoted.
+func (r BunCrudRepository[E, T]) checkOrder(op string, orderBy string) error {
+	if r.Strict == nil {
+		return nil
+	}
+
+	known := r.Meta.PersistencePresenterMapping()
+
+	for _, item := range strings.Split(orderBy, ",") {
+		if !validOrderItem(item, r.specMeta().PersistenceName(), known) {
+			return r.Strict.reject(Violation{Entity: r.Meta.EntityName(), Op: op, Kind: ViolationOrder, Input: orderBy})
+		}
+	}
+
+	return nil
+}
+
+func validOrderItem(item string, table string, known map[string]string) bool {
+	tokens := strings.Fields(item)
+	if len(tokens) == 0 {
+		return false
+	}
+
+	column := strings.ReplaceAll(tokens[0], `"`, "")
+	if qualifier, name, ok := strings.Cut(column, "."); ok {
+		if qualifier != table {
+			return false
+		}
+
+		column = name
+	}
+
+	if _, ok := known[column]; !ok {
+		return false
+	}
+
+	rest := strings.ToUpper(strings.Join(tokens[1:], " "))
+	rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(rest, "ASC"), "DESC"))
+
+	switch rest {
+	case "", "NULLS FIRST", "NULLS LAST":
+		return true
+	default:
+		return false
+	}
+}
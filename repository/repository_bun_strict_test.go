@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_StrictFindPage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		columns  []string
+		sort     Sort
+		expected func(t *testing.T, err error, report []Violation)
+	}{
+		{
+			name: "strict find page with known input",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_complex_entities"."complex_name" FROM "test_complex_entities" ORDER BY complex_name DESC`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"complex_name"}))
+			},
+			columns: []string{"complex_name"},
+			sort:    NewSorter().WithSort("complexName", "desc"),
+			expected: func(t *testing.T, err error, report []Violation) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Empty(t, report)
+			},
+		},
+		{
+			name:    "strict find page with unknown column",
+			mock:    func(conn *MockBunConnSet) {},
+			columns: []string{"password"},
+			sort:    NewSorter(),
+			expected: func(t *testing.T, err error, report []Violation) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrStrictViolation)
+				assert.Equal(t, []Violation{
+					{Entity: "TestComplexEnt", Op: "find page", Kind: ViolationColumn, Input: "password"},
+				}, report)
+			},
+		},
+		{
+			name:    "strict find page with injected order",
+			mock:    func(conn *MockBunConnSet) {},
+			columns: nil,
+			sort:    NewSorter().WithSort("(SELECT 1)", "asc"),
+			expected: func(t *testing.T, err error, report []Violation) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrStrictViolation)
+				assert.Equal(t, ViolationOrder, report[0].Kind)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestComplexEntRepository(subject.conn)
+			repo.Strict = NewStrictMode()
+			tt.mock(subject.conn)
+
+			_, err := repo.FindPage(context.Background(), nil, tt.columns, nil, nil, tt.sort)
+			tt.expected(t, err, repo.Strict.Report())
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestStrictMode_MaxViolations(t *testing.T) {
+	t.Parallel()
+
+	strict := NewStrictMode()
+	strict.MaxViolations = 2
+
+	for _, input := range []string{"a", "b", "c"} {
+		assert.ErrorIs(t, strict.reject(Violation{Kind: ViolationColumn, Input: input}), ErrStrictViolation)
+	}
+
+	assert.Equal(t, []Violation{
+		{Kind: ViolationColumn, Input: "b"},
+		{Kind: ViolationColumn, Input: "c"},
+	}, strict.Report())
+	assert.Equal(t, 1, strict.Dropped())
+
+	strict.Reset()
+	assert.Empty(t, strict.Report())
+	assert.Zero(t, strict.Dropped())
+}
+
+func TestValidOrderItem(t *testing.T) {
+	t.Parallel()
+
+	known := map[string]string{"name": "name", "created_at": "createdAt"}
+
+	tests := []struct {
+		item     string
+		expected bool
+	}{
+		{item: "name", expected: true},
+		{item: " name desc", expected: true},
+		{item: `"items"."created_at" ASC NULLS LAST`, expected: true},
+		{item: "other.name", expected: false},
+		{item: "unknown", expected: false},
+		{item: "name; DROP TABLE items", expected: false},
+		{item: "name ASC NULLS", expected: false},
+		{item: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.item, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, validOrderItem(tt.item, "items", known))
+		})
+	}
+}
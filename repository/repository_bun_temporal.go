@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/aso779/crud-repository/spec"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// BiTemporal entity keeps business validity as a half-open [from, to) interval.
+// A zero or NULL valid to value means the row is valid indefinitely.
+// Validity columns must be time.Time or *time.Time fields.
+type BiTemporal interface {
+	ValidFromColumn() string
+	ValidToColumn() string
+}
+
+// FindValidAt returns rows matching spec which are valid at the given business time.
+func (r BunCrudRepository[E, T]) FindValidAt(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+	at time.Time,
+) ([]E, error) {
+	var entities = make([]E, 0)
+
+	bt, ok := any(*new(E)).(BiTemporal)
+	if !ok {
+		return entities, fmt.Errorf("find valid at: %w", ErrBiTemporalNotSupported)
+	}
+
+	if err := r.checkColumns("find valid at", columns...); err != nil {
+		return entities, fmt.Errorf("find valid at: %w", err)
+	}
+
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return entities, fmt.Errorf("find valid at: %w", err)
+	}
+
+	query := tx.
+		NewSelect().
+		Model(&entities).
+		Column(columns...)
+
+	r.applySpec(query, spec)
+
+	bound := r.bindValues([]any{at})[0]
+
+	query.
+		Where("?TableAlias.? <= ?", bun.Ident(bt.ValidFromColumn()), bound).
+		Where("(?TableAlias.? IS NULL OR ?TableAlias.? > ?)", bun.Ident(bt.ValidToColumn()), bun.Ident(bt.ValidToColumn()), bound)
+
+	err = query.Scan(ctx)
+	if err != nil {
+		return entities, fmt.Errorf("find valid at: %w", dbError(err))
+	}
+
+	r.localize(entities)
+
+	if err := r.afterScan(ctx, entities); err != nil {
+		return entities, fmt.Errorf("find valid at: %w", err)
+	}
+
+	return entities, nil
+}
+
+// CreateValid inserts the entity unless its validity overlaps rows of the same identity.
+// Identity spec selects all versions of the same business object.
+func (r BunCrudRepository[E, T]) CreateValid(
+	ctx context.Context,
+	tx bun.IDB,
+	entity *E,
+	identity dataset.Specifier,
+	columns []string,
+) (*E, error) {
+	err := r.inTx(ctx, tx, func(ctx context.Context, tx bun.IDB) error {
+		overlapping, err := r.overlapping(ctx, tx, entity, identity)
+		if err != nil {
+			return err
+		}
+
+		if len(overlapping) > 0 {
+			return ErrValidityOverlap
+		}
+
+		_, err = r.CreateOne(ctx, tx, entity, columns)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create valid: %w", err)
+	}
+
+	return entity, nil
+}
+
+// UpdateValid updates the entity unless its new validity overlaps other rows of the same identity.
+func (r BunCrudRepository[E, T]) UpdateValid(
+	ctx context.Context,
+	tx bun.IDB,
+	entity *E,
+	identity dataset.Specifier,
+	columnsToUpdate []string,
+	columns []string,
+) (*E, error) {
+	err := r.inTx(ctx, tx, func(ctx context.Context, tx bun.IDB) error {
+		overlapping, err := r.overlapping(ctx, tx, entity, identity)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range overlapping {
+			if !reflect.DeepEqual(v.PrimaryKey(), (*entity).PrimaryKey()) {
+				return ErrValidityOverlap
+			}
+		}
+
+		_, err = r.UpdateOne(ctx, tx, entity, columnsToUpdate, columns)
+
+		return err
+	})
+	if err != nil {
+		return entity, fmt.Errorf("update valid: %w", err)
+	}
+
+	return entity, nil
+}
+
+// CorrectValid records a correction valid for the entity interval: overlapping versions of the same identity
+// are trimmed, split around the correction or removed when fully covered, then the entity is inserted.
+// Split versions are inserted as copies, so autoincrement and identity PK columns are reset to DB defaults.
+func (r BunCrudRepository[E, T]) CorrectValid(
+	ctx context.Context,
+	tx bun.IDB,
+	entity *E,
+	identity dataset.Specifier,
+	columns []string,
+) (*E, error) {
+	err := r.inTx(ctx, tx, func(ctx context.Context, tx bun.IDB) error {
+		bt, ok := any(*entity).(BiTemporal)
+		if !ok {
+			return ErrBiTemporalNotSupported
+		}
+
+		from, to := r.validity(tx, entity)
+
+		overlapping, err := r.overlapping(ctx, tx, entity, identity)
+		if err != nil {
+			return err
+		}
+
+		for i := range overlapping {
+			old := &overlapping[i]
+			oldFrom, oldTo := r.validity(tx, old)
+
+			startsBefore := oldFrom.Before(from)
+			endsAfter := !to.IsZero() && (oldTo.IsZero() || oldTo.After(to))
+
+			switch {
+			case startsBefore && endsAfter:
+				tail := *old
+				r.setValidity(tx, &tail, bt.ValidFromColumn(), to)
+				r.resetGeneratedPKs(tx, &tail)
+
+				if _, err = r.CreateOne(ctx, tx, &tail, nil); err != nil {
+					return err
+				}
+
+				err = r.updateValidity(ctx, tx, old, bt.ValidToColumn(), from)
+			case startsBefore:
+				err = r.updateValidity(ctx, tx, old, bt.ValidToColumn(), from)
+			case endsAfter:
+				err = r.updateValidity(ctx, tx, old, bt.ValidFromColumn(), to)
+			default:
+				_, err = tx.NewDelete().Model(old).WherePK().Exec(ctx)
+			}
+
+			if err != nil {
+				return dbError(err)
+			}
+		}
+
+		_, err = r.CreateOne(ctx, tx, entity, columns)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("correct valid: %w", err)
+	}
+
+	return entity, nil
+}
+
+// overlapping locks and returns identity rows whose validity overlaps the entity validity.
+// Row locks don't cover free periods, so writers of the same identity are serialized by a transaction-scoped
+// advisory lock on the hash of the identity spec key, which includes the entity name.
+func (r BunCrudRepository[E, T]) overlapping(
+	ctx context.Context,
+	tx bun.IDB,
+	entity *E,
+	identity dataset.Specifier,
+) ([]E, error) {
+	var entities = make([]E, 0)
+
+	bt, ok := any(*entity).(BiTemporal)
+	if !ok {
+		return nil, ErrBiTemporalNotSupported
+	}
+
+	from, to := r.validity(tx, entity)
+
+	key := spec.Key(tx.Dialect(), r.specMeta(), identity)
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext(?))", key); err != nil {
+		return nil, dbError(err)
+	}
+
+	query := tx.
+		NewSelect().
+		Model(&entities).
+		For("UPDATE")
+
+	r.applySpec(query, identity)
+
+	query.Where(
+		"(?TableAlias.? IS NULL OR ?TableAlias.? > ?)",
+		bun.Ident(bt.ValidToColumn()), bun.Ident(bt.ValidToColumn()), r.bindValue(from),
+	)
+
+	if !to.IsZero() {
+		query.Where("?TableAlias.? < ?", bun.Ident(bt.ValidFromColumn()), r.bindValue(to))
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, dbError(err)
+	}
+
+	return entities, nil
+}
+
+func (r BunCrudRepository[E, T]) updateValidity(
+	ctx context.Context,
+	tx bun.IDB,
+	entity *E,
+	column string,
+	value time.Time,
+) error {
+	_, err := tx.NewUpdate().
+		Model(entity).
+		Set("? = ?", bun.Ident(column), r.bindValue(value)).
+		WherePK().
+		Exec(ctx)
+
+	return err //nolint:wrapcheck
+}
+
+// validity returns valid from and valid to values, zero valid to means an open interval.
+func (r BunCrudRepository[E, T]) validity(tx bun.IDB, entity *E) (time.Time, time.Time) {
+	bt := any(*entity).(BiTemporal) //nolint:forcetypeassert
+
+	return r.timeValue(tx, entity, bt.ValidFromColumn()), r.timeValue(tx, entity, bt.ValidToColumn())
+}
+
+func (r BunCrudRepository[E, T]) timeValue(tx bun.IDB, entity *E, column string) time.Time {
+	table := tx.Dialect().Tables().Get(reflect.TypeOf(entity).Elem())
+
+	field := table.LookupField(column)
+	if field == nil {
+		return time.Time{}
+	}
+
+	v := field.Value(reflect.ValueOf(entity).Elem())
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return time.Time{}
+		}
+
+		v = v.Elem()
+	}
+
+	t, _ := v.Interface().(time.Time)
+
+	return t
+}
+
+func (r BunCrudRepository[E, T]) setValidity(tx bun.IDB, entity *E, column string, value time.Time) {
+	table := tx.Dialect().Tables().Get(reflect.TypeOf(entity).Elem())
+	v := table.LookupField(column).Value(reflect.ValueOf(entity).Elem())
+
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.ValueOf(&value))
+
+		return
+	}
+
+	v.Set(reflect.ValueOf(value))
+}
+
+func (r BunCrudRepository[E, T]) resetGeneratedPKs(tx bun.IDB, entity *E) {
+	table := tx.Dialect().Tables().Get(reflect.TypeOf(entity).Elem())
+	strct := reflect.ValueOf(entity).Elem()
+
+	for _, f := range table.PKs {
+		if f.AutoIncrement || f.Identity {
+			v := f.Value(strct)
+			v.Set(reflect.Zero(v.Type()))
+		}
+	}
+}
+
+// inTx runs fn in the given transaction, otherwise in a new transaction of the given pool or,
+// when tx is nil, of the write pool. The new transaction satisfies RequireTx.
+func (r BunCrudRepository[E, T]) inTx(
+	ctx context.Context,
+	tx bun.IDB,
+	fn func(ctx context.Context, tx bun.IDB) error,
+) error {
+	if tx != nil && isTx(tx) {
+		return fn(ctx, tx)
+	}
+
+	db := tx
+	if db == nil {
+		if r.readOnly() {
+			return ErrReadOnlyEntity
+		}
+
+		pool, err := r.rolePool(ctx, r.ConnSet.WritePool, TargetPrimary)
+		if err != nil {
+			return err
+		}
+
+		db = pool
+	}
+
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error { //nolint:wrapcheck
+		return fn(ctx, tx)
+	})
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/bun-pg-connector"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type TestPriceEnt struct {
+	bun.BaseModel `bun:"table:test_prices,alias:test_prices"`
+
+	ID        int       `bun:"id,pk,autoincrement" json:"id"`
+	Code      string    `bun:"code" json:"code"`
+	Amount    int       `bun:"amount" json:"amount"`
+	ValidFrom time.Time `bun:"valid_from" json:"validFrom"`
+	ValidTo   time.Time `bun:"valid_to,nullzero" json:"validTo"`
+}
+
+func (r TestPriceEnt) EntityName() string {
+	return "TestPriceEnt"
+}
+
+func (r TestPriceEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+func (r TestPriceEnt) ValidFromColumn() string {
+	return "valid_from"
+}
+
+func (r TestPriceEnt) ValidToColumn() string {
+	return "valid_to"
+}
+
+type TestPriceEntMeta struct {
+	TestPriceEnt
+}
+
+func (r TestPriceEntMeta) Entity() metadata.Entity { return r.TestPriceEnt }
+
+func (r TestPriceEntMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func NewTestPriceEntRepository(
+	connSet bunpgconnector.BunConnSet,
+) BunCrudRepository[TestPriceEnt, bun.Tx] {
+	return BunCrudRepository[TestPriceEnt, bun.Tx]{
+		ConnSet: connSet,
+		Meta:    meta.Parser(TestPriceEntMeta{}),
+	}
+}
+
+func testDate(month int) time.Time {
+	return time.Date(2024, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestBunCrudRepository_FindValidAt(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestPriceEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "test_prices" WHERE (test_prices.code = 'A') AND ("test_prices"."valid_from" <= '2024-03-01 00:00:00+00:00') AND (("test_prices"."valid_to" IS NULL OR "test_prices"."valid_to" > '2024-03-01 00:00:00+00:00'))`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code"}).AddRow(1, "A"))
+
+	res, err := repo.FindValidAt(context.Background(), nil, []string{"*"}, dataspec.NewEqual("code", "A"), testDate(3))
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(res))
+}
+
+func TestBunCrudRepository_CreateValidOverlap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		location *time.Location
+		from     string
+		to       string
+	}{
+		{name: "utc", from: "2024-03-01 00:00:00+00:00", to: "2024-05-01 00:00:00+00:00"},
+		{
+			name:     "location",
+			location: time.FixedZone("UTC+3", 3*3600),
+			from:     "2024-03-01 03:00:00+03:00",
+			to:       "2024-05-01 03:00:00+03:00",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestPriceEntRepository(subject.conn)
+			repo.Location = tt.location
+
+			subject.conn.Mock.ExpectBegin()
+			subject.conn.Mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock(hashtext('TestPriceEnt:`)).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			subject.conn.Mock.ExpectQuery(regexp.QuoteMeta(`SELECT "test_prices"."id", "test_prices"."code", "test_prices"."amount", "test_prices"."valid_from", "test_prices"."valid_to" FROM "test_prices" WHERE (test_prices.code = 'A') AND (("test_prices"."valid_to" IS NULL OR "test_prices"."valid_to" > '` + tt.from + `')) AND ("test_prices"."valid_from" < '` + tt.to + `') FOR UPDATE`)).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "code", "valid_from"}).AddRow(1, "A", testDate(1)))
+			subject.conn.Mock.ExpectRollback()
+
+			_, err := repo.CreateValid(
+				context.Background(),
+				nil,
+				&TestPriceEnt{Code: "A", Amount: 10, ValidFrom: testDate(3), ValidTo: testDate(5)},
+				dataspec.NewEqual("code", "A"),
+				[]string{"*"},
+			)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.ErrorIs(t, err, ErrValidityOverlap)
+		})
+	}
+}
+
+func TestBunCrudRepository_CorrectValidSplit(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestPriceEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectBegin()
+	subject.conn.Mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock(hashtext('TestPriceEnt:`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	subject.conn.Mock.ExpectQuery(`^SELECT .* FROM "test_prices" WHERE .* FOR UPDATE$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "amount", "valid_from", "valid_to"}).
+			AddRow(7, "A", 5, testDate(1), nil))
+	subject.conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`INSERT INTO "test_prices" ("id", "code", "amount", "valid_from", "valid_to") VALUES (DEFAULT, 'A', 5, '2024-05-01 00:00:00+00:00', DEFAULT)`) + "$").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	subject.conn.Mock.ExpectExec(regexp.QuoteMeta(`UPDATE "test_prices" AS "test_prices" SET "valid_to" = '2024-03-01 00:00:00+00:00' WHERE ("test_prices"."id" = 7)`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	subject.conn.Mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "test_prices" ("id", "code", "amount", "valid_from", "valid_to") VALUES (DEFAULT, 'A', 10, '2024-03-01 00:00:00+00:00', '2024-05-01 00:00:00+00:00') RETURNING *`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+	subject.conn.Mock.ExpectCommit()
+
+	res, err := repo.CorrectValid(
+		context.Background(),
+		nil,
+		&TestPriceEnt{Code: "A", Amount: 10, ValidFrom: testDate(3), ValidTo: testDate(5)},
+		dataspec.NewEqual("code", "A"),
+		[]string{"*"},
+	)
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, 9, res.ID)
+}
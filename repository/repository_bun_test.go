@@ -915,6 +915,68 @@ func TestBunCrudRepository_Count(t *testing.T) {
 	}
 }
 
+func TestBunCrudRepository_Exists(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		spec     dataset.Specifier
+		expected func(t *testing.T, res bool, err error)
+	}{
+		{
+			name: "exists",
+			mock: func(conn *MockBunConnSet) {
+				rows := sqlmock.NewRows([]string{"exists"}).AddRow(true)
+
+				conn.Mock.ExpectQuery("^SELECT EXISTS \\(SELECT 1 FROM \"test_simple_entities\"\\)$").
+					WillReturnRows(rows)
+			},
+			spec: func() dataset.Specifier {
+				return nil
+			}(),
+			expected: func(t *testing.T, res bool, err error) {
+				t.Helper()
+				assert.True(t, res)
+			},
+		},
+		{
+			name: "exists with spec",
+			mock: func(conn *MockBunConnSet) {
+				rows := sqlmock.NewRows([]string{"exists"}).AddRow(true)
+
+				conn.Mock.ExpectQuery("^SELECT EXISTS \\(SELECT 1 FROM \"test_simple_entities\" WHERE \\(test_simple_entities\\.name = 'John'\\)\\)$").
+					WillReturnRows(rows)
+			},
+			spec: func() dataset.Specifier {
+				return dataspec.NewEqual("name", "John")
+			}(),
+			expected: func(t *testing.T, res bool, err error) {
+				t.Helper()
+				assert.True(t, res)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			res, err := repo.Exists(context.Background(), nil, tt.spec)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+
+			tt.expected(t, res, err)
+		})
+	}
+}
+
 func TestBunCrudRepository_CreateOne(t *testing.T) {
 	t.Parallel()
 
@@ -1332,7 +1394,7 @@ func TestBunCrudRepository_IsColumnValueUnique(t *testing.T) {
 			mock: func(conn *MockBunConnSet) {
 				rows := sqlmock.NewRows([]string{"exists"}).AddRow(true)
 
-				conn.Mock.ExpectQuery("^SELECT EXISTS \\(SELECT \"test_simple_entities\".\"id\" FROM \"test_simple_entities\" WHERE \\(name = 'test'\\)\\)$").
+				conn.Mock.ExpectQuery("^SELECT EXISTS \\(SELECT \"test_simple_entities\".\"id\" FROM \"test_simple_entities\" WHERE \\(\"name\" = 'test'\\)\\)$").
 					WillReturnRows(rows)
 			},
 			column: "name",
@@ -1361,3 +1423,102 @@ func TestBunCrudRepository_IsColumnValueUnique(t *testing.T) {
 		})
 	}
 }
+
+func TestBunCrudRepository_FindOneBy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		criteria map[string]any
+		expected func(t *testing.T, res *TestComplexEnt, err error)
+	}{
+		{
+			name: "find one by natural key",
+			mock: func(conn *MockBunConnSet) {
+				rows := sqlmock.NewRows([]string{"first_id", "second_id"}).
+					AddRow(1, 2)
+				conn.Mock.ExpectQuery("^SELECT \\* FROM \"test_complex_entities\" WHERE \\(\\(test_complex_entities.complex_description IS NULL AND test_complex_entities.complex_name = 'John'\\)\\)$").
+					WillReturnRows(rows)
+			},
+			criteria: map[string]any{"complexName": "John", "complexDescription": nil},
+			expected: func(t *testing.T, res *TestComplexEnt, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.NotNil(t, res)
+			},
+		},
+		{
+			name: "find one by persistence name",
+			mock: func(conn *MockBunConnSet) {
+				rows := sqlmock.NewRows([]string{"first_id", "second_id"}).
+					AddRow(1, 2)
+				conn.Mock.ExpectQuery("^SELECT \\* FROM \"test_complex_entities\" WHERE \\(\\(test_complex_entities.complex_name = 'John'\\)\\)$").
+					WillReturnRows(rows)
+			},
+			criteria: map[string]any{"complex_name": "John"},
+			expected: func(t *testing.T, res *TestComplexEnt, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.NotNil(t, res)
+			},
+		},
+		{
+			name:     "find one by empty criteria",
+			mock:     func(conn *MockBunConnSet) {},
+			criteria: map[string]any{},
+			expected: func(t *testing.T, res *TestComplexEnt, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrEmptyValues)
+				assert.Nil(t, res)
+			},
+		},
+		{
+			name:     "find one by unknown field",
+			mock:     func(conn *MockBunConnSet) {},
+			criteria: map[string]any{"password": "John"},
+			expected: func(t *testing.T, res *TestComplexEnt, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrUnknownField)
+				assert.Nil(t, res)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestComplexEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			res, err := repo.FindOneBy(context.Background(), nil, []string{"*"}, tt.criteria)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+
+			tt.expected(t, res, err)
+		})
+	}
+}
+
+func TestBunCrudRepository_FindAllBy(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestComplexEntRepository(subject.conn)
+
+	rows := sqlmock.NewRows([]string{"first_id", "second_id"}).
+		AddRow(1, 2).
+		AddRow(1, 3)
+	subject.conn.Mock.ExpectQuery("^SELECT \\* FROM \"test_complex_entities\" WHERE \\(\\(test_complex_entities.complex_name = 'John' AND test_complex_entities.first_id = 1\\)\\)$").
+		WillReturnRows(rows)
+
+	res, err := repo.FindAllBy(context.Background(), nil, []string{"*"}, map[string]any{"firstId": 1, "complexName": "John"})
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(res))
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// Granularity date_trunc unit of time series buckets.
+type Granularity string
+
+const (
+	GranularityMinute  Granularity = "minute"
+	GranularityHour    Granularity = "hour"
+	GranularityDay     Granularity = "day"
+	GranularityWeek    Granularity = "week"
+	GranularityMonth   Granularity = "month"
+	GranularityQuarter Granularity = "quarter"
+	GranularityYear    Granularity = "year"
+)
+
+// step returns the interval between buckets of the granularity.
+func (g Granularity) step() (string, bool) {
+	switch g {
+	case GranularityMinute, GranularityHour, GranularityDay, GranularityWeek, GranularityMonth, GranularityYear:
+		return "1 " + string(g), true
+	case GranularityQuarter:
+		return "3 month", true
+	default:
+		return "", false
+	}
+}
+
+// TimePoint value of a time series bucket starting at At.
+type TimePoint struct {
+	At    time.Time `bun:"at"`
+	Value float64   `bun:"value"`
+}
+
+// TimeSeriesCount counts rows matching spec per bucket of the timestamp column in [from, to).
+// Every bucket of the range is returned in order, empty ones with a zero value.
+func (r BunCrudRepository[E, T]) TimeSeriesCount(
+	ctx context.Context,
+	tx bun.IDB,
+	column string,
+	granularity Granularity,
+	from, to time.Time,
+	spec dataset.Specifier,
+) ([]TimePoint, error) {
+	points, err := r.timeSeries(ctx, tx, column, granularity, from, to, spec, "count(*)::float8")
+	if err != nil {
+		return points, fmt.Errorf("time series count: %w", err)
+	}
+
+	return points, nil
+}
+
+// TimeSeriesAggregate returns fn over a numeric column of rows matching spec per bucket of the timestamp
+// column in [from, to). Every bucket of the range is returned in order, empty ones with a zero value.
+func (r BunCrudRepository[E, T]) TimeSeriesAggregate(
+	ctx context.Context,
+	tx bun.IDB,
+	fn AggregateFunc,
+	valueColumn string,
+	column string,
+	granularity Granularity,
+	from, to time.Time,
+	spec dataset.Specifier,
+) ([]TimePoint, error) {
+	expr, valueColumn, err := r.aggregateExpr(fn, valueColumn)
+	if err != nil {
+		return make([]TimePoint, 0), fmt.Errorf("time series aggregate: %w", err)
+	}
+
+	points, err := r.timeSeries(ctx, tx, column, granularity, from, to, spec, expr, bun.Ident(valueColumn))
+	if err != nil {
+		return points, fmt.Errorf("time series aggregate: %w", err)
+	}
+
+	return points, nil
+}
+
+// timeSeries left joins expr values grouped by truncated column to the generated series of buckets.
+func (r BunCrudRepository[E, T]) timeSeries(
+	ctx context.Context,
+	tx bun.IDB,
+	column string,
+	granularity Granularity,
+	from, to time.Time,
+	spec dataset.Specifier,
+	expr string,
+	args ...any,
+) ([]TimePoint, error) {
+	var points = make([]TimePoint, 0)
+
+	step, ok := granularity.step()
+	if !ok {
+		return points, fmt.Errorf("granularity %s: %w", granularity, ErrInvalidValue)
+	}
+
+	if !from.Before(to) {
+		return points, fmt.Errorf("range %s - %s: %w", from, to, ErrInvalidValue)
+	}
+
+	column = r.persistenceName(column)
+	if _, ok := r.Meta.PersistencePresenterMapping()[column]; !ok {
+		return points, fmt.Errorf("%s: %w", column, ErrUnknownColumn)
+	}
+
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return points, err
+	}
+
+	lower, upper := r.bindValue(from), r.bindValue(to)
+
+	data := tx.
+		NewSelect().
+		Model((*E)(nil)).
+		ColumnExpr("date_trunc(?, ?TableAlias.?) AS at", string(granularity), bun.Ident(column)).
+		ColumnExpr(expr+" AS value", args...).
+		Where("?TableAlias.? >= ?", bun.Ident(column), lower).
+		Where("?TableAlias.? < ?", bun.Ident(column), upper).
+		GroupExpr("1")
+
+	r.applySpec(data, spec)
+
+	query := tx.
+		NewSelect().
+		With("series_data", data).
+		TableExpr(
+			"generate_series(date_trunc(?, ?::timestamptz), ?::timestamptz - interval '1 microsecond', ?::interval) AS series (at)",
+			string(granularity), lower, upper, step,
+		).
+		ColumnExpr("series.at").
+		ColumnExpr("COALESCE(series_data.value, 0) AS value").
+		Join("LEFT JOIN series_data ON series_data.at = series.at").
+		OrderExpr("series.at")
+
+	if err := query.Scan(ctx, &points); err != nil {
+		return points, dbError(err)
+	}
+
+	r.localize(points)
+
+	return points, nil
+}
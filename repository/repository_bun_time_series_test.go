@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+func TestBunCrudRepository_TimeSeries(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		run      func(repo BunCrudRepository[TestEventEnt, bun.Tx]) ([]TimePoint, error)
+		mock     func(conn *MockBunConnSet)
+		expected []TimePoint
+		err      error
+	}{
+		{
+			name: "count per day",
+			run: func(repo BunCrudRepository[TestEventEnt, bun.Tx]) ([]TimePoint, error) {
+				return repo.TimeSeriesCount(
+					context.Background(), nil, "createdAt", GranularityDay, from, to, dataspec.NewEqual("kind", "login"),
+				)
+			},
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`WITH "series_data" AS (SELECT date_trunc('day', "test_events"."created_at") AS at, count(*)::float8 AS value FROM "test_events" WHERE ("test_events"."created_at" >= '2025-01-01 00:00:00+00:00') AND ("test_events"."created_at" < '2025-01-04 00:00:00+00:00') AND (test_events.kind = 'login') GROUP BY 1) SELECT series.at, COALESCE(series_data.value, 0) AS value FROM generate_series(date_trunc('day', '2025-01-01 00:00:00+00:00'::timestamptz), '2025-01-04 00:00:00+00:00'::timestamptz - interval '1 microsecond', '1 day'::interval) AS series (at) LEFT JOIN series_data ON series_data.at = series.at ORDER BY series.at`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"at", "value"}).
+						AddRow(from, 3.0).
+						AddRow(from.AddDate(0, 0, 1), 0.0).
+						AddRow(from.AddDate(0, 0, 2), 5.0))
+			},
+			expected: []TimePoint{
+				{At: from, Value: 3},
+				{At: from.AddDate(0, 0, 1), Value: 0},
+				{At: from.AddDate(0, 0, 2), Value: 5},
+			},
+		},
+		{
+			name: "max per quarter",
+			run: func(repo BunCrudRepository[TestEventEnt, bun.Tx]) ([]TimePoint, error) {
+				return repo.TimeSeriesAggregate(
+					context.Background(), nil, AggregateMax, "id", "created_at", GranularityQuarter, from, to, nil,
+				)
+			},
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`WITH "series_data" AS (SELECT date_trunc('quarter', "test_events"."created_at") AS at, MAX("test_events"."id")::float8 AS value FROM "test_events" WHERE ("test_events"."created_at" >= '2025-01-01 00:00:00+00:00') AND ("test_events"."created_at" < '2025-01-04 00:00:00+00:00') GROUP BY 1) SELECT series.at, COALESCE(series_data.value, 0) AS value FROM generate_series(date_trunc('quarter', '2025-01-01 00:00:00+00:00'::timestamptz), '2025-01-04 00:00:00+00:00'::timestamptz - interval '1 microsecond', '3 month'::interval) AS series (at) LEFT JOIN series_data ON series_data.at = series.at ORDER BY series.at`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"at", "value"}).AddRow(from, 7.0))
+			},
+			expected: []TimePoint{{At: from, Value: 7}},
+		},
+		{
+			name: "unknown granularity",
+			run: func(repo BunCrudRepository[TestEventEnt, bun.Tx]) ([]TimePoint, error) {
+				return repo.TimeSeriesCount(context.Background(), nil, "createdAt", Granularity("decade"), from, to, nil)
+			},
+			mock:     func(conn *MockBunConnSet) {},
+			expected: []TimePoint{},
+			err:      ErrInvalidValue,
+		},
+		{
+			name: "empty range",
+			run: func(repo BunCrudRepository[TestEventEnt, bun.Tx]) ([]TimePoint, error) {
+				return repo.TimeSeriesCount(context.Background(), nil, "createdAt", GranularityHour, to, from, nil)
+			},
+			mock:     func(conn *MockBunConnSet) {},
+			expected: []TimePoint{},
+			err:      ErrInvalidValue,
+		},
+		{
+			name: "unknown column",
+			run: func(repo BunCrudRepository[TestEventEnt, bun.Tx]) ([]TimePoint, error) {
+				return repo.TimeSeriesCount(context.Background(), nil, "updatedAt", GranularityHour, from, to, nil)
+			},
+			mock:     func(conn *MockBunConnSet) {},
+			expected: []TimePoint{},
+			err:      ErrUnknownColumn,
+		},
+		{
+			name: "unknown aggregate function",
+			run: func(repo BunCrudRepository[TestEventEnt, bun.Tx]) ([]TimePoint, error) {
+				return repo.TimeSeriesAggregate(
+					context.Background(), nil, AggregateFunc("MEDIAN"), "id", "createdAt", GranularityHour, from, to, nil,
+				)
+			},
+			mock:     func(conn *MockBunConnSet) {},
+			expected: []TimePoint{},
+			err:      ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := newTestEventEntRepository(subject)
+
+			tt.mock(subject.conn)
+
+			res, err := tt.run(repo)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
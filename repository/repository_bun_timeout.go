@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+// QueryTimeouts decorates a repository converting errors of operations run past the context deadline
+// into QueryTimeoutError, matching ErrQueryTimeout. The interrupted SQL is attached when CancellationHook
+// is added to the pools. Only CrudRepository methods are decorated, errors of other BunCrudRepository
+// methods, e.g. UpdateEachByPk or Restore, get no conversion.
+type QueryTimeouts[E metadata.Entity, T bun.Tx] struct {
+	CrudRepository[E, T]
+}
+
+func NewQueryTimeouts[E metadata.Entity, T bun.Tx](repo CrudRepository[E, T]) QueryTimeouts[E, T] {
+	return QueryTimeouts[E, T]{
+		CrudRepository: repo,
+	}
+}
+
+func (r QueryTimeouts[E, T]) FindOne(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+) (*E, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.FindOne(ctx, tx, columns, spec)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) FindOneByPk(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	pk metadata.PrimaryKey,
+) (*E, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.FindOneByPk(ctx, tx, columns, pk)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) FindOneBy(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	criteria map[string]any,
+) (*E, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.FindOneBy(ctx, tx, columns, criteria)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) FindAll(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+) ([]E, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.FindAll(ctx, tx, columns, spec)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) FindAllBy(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	criteria map[string]any,
+) ([]E, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.FindAllBy(ctx, tx, columns, criteria)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) FindPage(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+	page dataset.Pager,
+	sort dataset.Sorter,
+) ([]E, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.FindPage(ctx, tx, columns, spec, page, sort)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) FindAllByPks(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	pks []metadata.PrimaryKey,
+) ([]E, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.FindAllByPks(ctx, tx, columns, pks)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) Count(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (int, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.Count(ctx, tx, spec)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) Exists(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (bool, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.Exists(ctx, tx, spec)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) CreateOne(
+	ctx context.Context,
+	tx bun.IDB,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.CreateOne(ctx, tx, entity, columns)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) CreateAll(
+	ctx context.Context,
+	tx bun.IDB,
+	entities []E,
+	columns []string,
+) ([]E, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.CreateAll(ctx, tx, entities, columns)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) FirstOrCreate(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.FirstOrCreate(ctx, tx, spec, entity, columns)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) FirstOrCreateLocked(
+	ctx context.Context,
+	tx bun.IDB,
+	key string,
+	spec dataset.Specifier,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.FirstOrCreateLocked(ctx, tx, key, spec, entity, columns)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) UpdateOrCreate(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	values map[string]any,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.UpdateOrCreate(ctx, tx, spec, values, entity, columns)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) UpdateOne(
+	ctx context.Context,
+	tx bun.IDB,
+	entity *E,
+	columnsToUpdate []string,
+	columns []string,
+) (*E, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.UpdateOne(ctx, tx, entity, columnsToUpdate, columns)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) ForceDelete(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (int, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.ForceDelete(ctx, tx, spec)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) Delete(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+) (int, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.Delete(ctx, tx, spec)
+
+	return res, queryTimeout(ctx, rec, err)
+}
+
+func (r QueryTimeouts[E, T]) IsColumnValueUnique(
+	ctx context.Context,
+	tx bun.IDB,
+	column string,
+	value any,
+) (bool, error) {
+	ctx, rec := withCancelRecorder(ctx)
+
+	res, err := r.CrudRepository.IsColumnValueUnique(ctx, tx, column, value)
+
+	return res, queryTimeout(ctx, rec, err)
+}
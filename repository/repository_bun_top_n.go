@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// topNRankColumn is the row_number column of TopNPerGroup, it can't clash with entity columns.
+const topNRankColumn = "__rank"
+
+// TopNPerGroup returns up to n rows matching spec per distinct value of groupColumn, the first ones by
+// orderBy, e.g. the 3 most recent orders per customer with groupColumn "customerId" and orderBy
+// "createdAt DESC". OrderBy is a "column [ASC|DESC] [NULLS FIRST|LAST]" item, columns are presenter
+// or persistence names. Rows are ordered by group, then by rank within the group.
+func (r BunCrudRepository[E, T]) TopNPerGroup(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+	groupColumn string,
+	orderBy string,
+	n int,
+) ([]E, error) {
+	var entities = make([]E, 0)
+
+	if n <= 0 {
+		return entities, fmt.Errorf("top n per group %d: %w", n, ErrInvalidValue)
+	}
+
+	if err := r.checkColumns("top n per group", columns...); err != nil {
+		return entities, fmt.Errorf("top n per group: %w", err)
+	}
+
+	known := r.Meta.PersistencePresenterMapping()
+
+	groupColumn = r.persistenceName(groupColumn)
+	if _, ok := known[groupColumn]; !ok {
+		return entities, fmt.Errorf("top n per group %s: %w", groupColumn, ErrUnknownColumn)
+	}
+
+	orderColumn, direction, err := r.topNOrder(orderBy)
+	if err != nil {
+		return entities, fmt.Errorf("top n per group: %w", err)
+	}
+
+	if err := r.guardFullTable(ctx, spec); err != nil {
+		return entities, fmt.Errorf("top n per group: %w", err)
+	}
+
+	tx, err = r.readDB(ctx, tx)
+	if err != nil {
+		return entities, fmt.Errorf("top n per group: %w", err)
+	}
+
+	ranked := tx.
+		NewSelect().
+		Model((*E)(nil)).
+		ColumnExpr(
+			"?TableAlias.*, row_number() OVER (PARTITION BY ?TableAlias.? ORDER BY ?TableAlias.? "+direction+") AS ?",
+			bun.Ident(groupColumn), bun.Ident(orderColumn), bun.Ident(topNRankColumn),
+		)
+
+	r.applySpec(ranked, spec)
+
+	query := tx.
+		NewSelect().
+		Model(&entities).
+		Column(columns...).
+		ModelTableExpr("(?) AS ?TableAlias", ranked).
+		Where("?TableAlias.? <= ?", bun.Ident(topNRankColumn), n).
+		OrderExpr("?TableAlias.?", bun.Ident(groupColumn)).
+		OrderExpr("?TableAlias.?", bun.Ident(topNRankColumn))
+
+	if err := query.Scan(ctx); err != nil {
+		return entities, fmt.Errorf("top n per group: %w", dbError(err))
+	}
+
+	r.localize(entities)
+
+	if err := r.afterScan(ctx, entities); err != nil {
+		return entities, fmt.Errorf("top n per group: %w", err)
+	}
+
+	return entities, nil
+}
+
+// topNOrder splits a validated orderBy item into the persistence name of its column and its direction.
+func (r BunCrudRepository[E, T]) topNOrder(orderBy string) (string, string, error) {
+	tokens := strings.Fields(orderBy)
+	if len(tokens) == 0 {
+		return "", "", fmt.Errorf("order by %q: %w", orderBy, ErrInvalidValue)
+	}
+
+	column := r.persistenceName(tokens[0])
+	direction := strings.ToUpper(strings.Join(tokens[1:], " "))
+
+	if !validOrderItem(column+" "+direction, r.specMeta().PersistenceName(), r.Meta.PersistencePresenterMapping()) {
+		return "", "", fmt.Errorf("order by %q: %w", orderBy, ErrInvalidValue)
+	}
+
+	return column, direction, nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_TopNPerGroup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		columns  []string
+		group    string
+		orderBy  string
+		n        int
+		mock     func(conn *MockBunConnSet)
+		expected []TestComplexEnt
+		err      error
+	}{
+		{
+			name:    "all columns",
+			group:   "firstId",
+			orderBy: "secondId desc",
+			n:       2,
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_complex_entities"."first_id", "test_complex_entities"."second_id", "test_complex_entities"."complex_name", "test_complex_entities"."complex_description" FROM (SELECT "test_complex_entities".*, row_number() OVER (PARTITION BY "test_complex_entities"."first_id" ORDER BY "test_complex_entities"."second_id" DESC) AS "__rank" FROM "test_complex_entities" WHERE (test_complex_entities.second_id > 0)) AS "test_complex_entities" WHERE ("test_complex_entities"."__rank" <= 2) ORDER BY "test_complex_entities"."first_id", "test_complex_entities"."__rank"`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"first_id", "second_id", "complex_name"}).
+						AddRow(1, 3, "c").
+						AddRow(1, 2, "b").
+						AddRow(2, 5, "e"))
+			},
+			expected: []TestComplexEnt{
+				{FirstID: 1, SecondID: 3, Name: "c"},
+				{FirstID: 1, SecondID: 2, Name: "b"},
+				{FirstID: 2, SecondID: 5, Name: "e"},
+			},
+		},
+		{
+			name:    "selected columns",
+			columns: []string{"second_id", "complex_name"},
+			group:   "first_id",
+			orderBy: "complexName asc nulls last",
+			n:       1,
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`SELECT "test_complex_entities"."second_id", "test_complex_entities"."complex_name" FROM (SELECT "test_complex_entities".*, row_number() OVER (PARTITION BY "test_complex_entities"."first_id" ORDER BY "test_complex_entities"."complex_name" ASC NULLS LAST) AS "__rank" FROM "test_complex_entities" WHERE (test_complex_entities.second_id > 0)) AS "test_complex_entities" WHERE ("test_complex_entities"."__rank" <= 1) ORDER BY "test_complex_entities"."first_id", "test_complex_entities"."__rank"`) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"second_id", "complex_name"}).AddRow(1, "a").AddRow(4, "d"))
+			},
+			expected: []TestComplexEnt{{SecondID: 1, Name: "a"}, {SecondID: 4, Name: "d"}},
+		},
+		{
+			name:     "invalid n",
+			group:    "firstId",
+			orderBy:  "secondId",
+			n:        0,
+			mock:     func(conn *MockBunConnSet) {},
+			expected: []TestComplexEnt{},
+			err:      ErrInvalidValue,
+		},
+		{
+			name:     "unknown group column",
+			group:    "customerId",
+			orderBy:  "secondId",
+			n:        3,
+			mock:     func(conn *MockBunConnSet) {},
+			expected: []TestComplexEnt{},
+			err:      ErrUnknownColumn,
+		},
+		{
+			name:     "unknown order column",
+			group:    "firstId",
+			orderBy:  "createdAt DESC",
+			n:        3,
+			mock:     func(conn *MockBunConnSet) {},
+			expected: []TestComplexEnt{},
+			err:      ErrInvalidValue,
+		},
+		{
+			name:     "invalid order direction",
+			group:    "firstId",
+			orderBy:  "secondId DESC; DROP TABLE test_complex_entities",
+			n:        3,
+			mock:     func(conn *MockBunConnSet) {},
+			expected: []TestComplexEnt{},
+			err:      ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestComplexEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			res, err := repo.TopNPerGroup(
+				context.Background(),
+				nil,
+				tt.columns,
+				dataspec.NewGt("secondId", 0),
+				tt.group,
+				tt.orderBy,
+				tt.n,
+			)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
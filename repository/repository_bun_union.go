@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// FindPageUnion pages rows of the spec combined with rows of the other spec of a repository of the same shape,
+// e.g. an archive table, applying page and sort to the union. UNION ALL keeps duplicate rows when all is set.
+func (r BunCrudRepository[E, T]) FindPageUnion(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+	other BunCrudRepository[E, T],
+	otherSpec dataset.Specifier,
+	all bool,
+	page dataset.Pager,
+	sort dataset.Sorter,
+) ([]E, error) {
+	var entities = make([]E, 0)
+
+	if err := r.checkColumns("find page union", columns...); err != nil {
+		return entities, fmt.Errorf("find page union: %w", err)
+	}
+
+	tx, err := r.readDB(ctx, tx)
+	if err != nil {
+		return entities, fmt.Errorf("find page union: %w", err)
+	}
+
+	idents := make([]bun.Ident, 0, len(columns))
+
+	if slices.Contains(columns, "*") {
+		for _, f := range tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem()).Fields {
+			idents = append(idents, bun.Ident(f.Name))
+		}
+	} else {
+		for _, c := range columns {
+			idents = append(idents, bun.Ident(c))
+		}
+	}
+
+	union := r.selectTable(tx, spec, idents)
+	if all {
+		union.UnionAll(other.selectTable(tx, otherSpec, idents))
+	} else {
+		union.Union(other.selectTable(tx, otherSpec, idents))
+	}
+
+	// the union is aliased as the table so table qualified sort columns resolve
+	query := tx.NewSelect().TableExpr("(?) AS ?", union, bun.Ident(r.specMeta().PersistenceName()))
+
+	if page != nil && !page.IsEmpty() {
+		query.Limit(page.GetSize())
+		query.Offset(page.GetOffset())
+	}
+
+	if sort != nil && !sort.IsEmpty() {
+		orderBy := sort.OrderBy(r.specMeta())
+		if _, raw := sort.(RawSorter); !raw {
+			if err := r.checkOrder("find page union", orderBy); err != nil {
+				return entities, fmt.Errorf("find page union: %w", err)
+			}
+		}
+
+		query.OrderExpr(orderBy)
+	}
+
+	err = query.Scan(ctx, &entities)
+	if err != nil {
+		return entities, fmt.Errorf("find page union: %w", dbError(err))
+	}
+
+	r.localize(entities)
+
+	if err := r.afterScan(ctx, entities); err != nil {
+		return entities, fmt.Errorf("find page union: %w", err)
+	}
+
+	return entities, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_FindPageUnion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		query   string
+		columns []string
+		all     bool
+		page    dataset.Pager
+		sort    dataset.Sorter
+	}{
+		{
+			name:    "union all paged and sorted",
+			query:   `SELECT * FROM ((SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" AS "test_simple_entities" WHERE (test_simple_entities.id > 1)) UNION ALL (SELECT "staging_test_simple_entities"."id", "staging_test_simple_entities"."name" FROM "staging_test_simple_entities" AS "staging_test_simple_entities")) AS "test_simple_entities" ORDER BY name DESC LIMIT 10 OFFSET 30`,
+			columns: []string{"*"},
+			all:     true,
+			page:    NewPager(10, 3),
+			sort:    NewSorter().WithSort("name", "desc"),
+		},
+		{
+			name:    "union of columns",
+			query:   `SELECT * FROM ((SELECT "test_simple_entities"."name" FROM "test_simple_entities" AS "test_simple_entities" WHERE (test_simple_entities.id > 1)) UNION (SELECT "staging_test_simple_entities"."name" FROM "staging_test_simple_entities" AS "staging_test_simple_entities")) AS "test_simple_entities"`,
+			columns: []string{"name"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+			archive := repo.BunCrudRepository
+			archive.Meta = stagingMeta{Meta: repo.Meta}
+
+			subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(tt.query) + "$").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(1, "a"))
+
+			res, err := repo.FindPageUnion(
+				context.Background(), nil, tt.columns, dataspec.NewGt("id", 1), archive, nil, tt.all, tt.page, tt.sort,
+			)
+
+			assert.NoError(t, err)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+			assert.Equal(t, []TestSimpleEnt{{ID: 1, Name: "a"}, {ID: 1, Name: "a"}}, res)
+		})
+	}
+}
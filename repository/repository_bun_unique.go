@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// UniqueExpressions entity declares columns whose unique indexes are on expressions, by persistence name,
+// e.g. {"email": "lower(?)"} for CREATE UNIQUE INDEX ON users (lower(email)). The expression has a single
+// ? placeholder for the column, IsColumnValueUnique compares it applied to both the column and the value.
+type UniqueExpressions interface {
+	UniqueExpressions() map[string]string
+}
+
+// uniqueCondition returns the IsColumnValueUnique condition of column and value.
+func (r BunCrudRepository[E, T]) uniqueCondition(column string, value any) (string, []any, error) {
+	name := r.persistenceName(column)
+
+	if err := r.checkColumns("is column value unique", name); err != nil {
+		return "", nil, err
+	}
+
+	args := []any{bun.Ident(name), r.bindValue(value)}
+
+	ue, ok := any(*new(E)).(UniqueExpressions)
+	if !ok {
+		return "? = ?", args, nil
+	}
+
+	expr, ok := ue.UniqueExpressions()[name]
+	if !ok {
+		return "? = ?", args, nil
+	}
+
+	if strings.Count(expr, "?") != 1 {
+		return "", nil, fmt.Errorf("unique expression %s of %s: %w", expr, name, ErrInvalidValue)
+	}
+
+	return expr + " = " + expr, args, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type TestAccountEnt struct {
+	bun.BaseModel `bun:"table:test_accounts,alias:test_accounts"`
+
+	ID    int    `bun:"id,pk" json:"id"`
+	Email string `bun:"email" json:"email"`
+	Login string `bun:"login" json:"login"`
+}
+
+func (r TestAccountEnt) EntityName() string {
+	return "TestAccountEnt"
+}
+
+func (r TestAccountEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+func (r TestAccountEnt) UniqueExpressions() map[string]string {
+	return map[string]string{
+		"email": "lower(?)",
+		"login": "?",
+		"bad":   "coalesce(?, ?)",
+	}
+}
+
+func (r TestAccountEnt) ConstraintColumns() map[string][]string {
+	return map[string][]string{
+		"test_accounts_lower_email_idx": {"email"},
+	}
+}
+
+type TestAccountEntMeta struct {
+	TestAccountEnt
+}
+
+func (r TestAccountEntMeta) Entity() metadata.Entity { return r.TestAccountEnt }
+
+func (r TestAccountEntMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func TestBunCrudRepository_IsColumnValueUniqueExpression(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		column string
+		value  string
+		query  string
+		strict bool
+		err    error
+	}{
+		{
+			name:   "expression index",
+			column: "email",
+			value:  "John@Example.com",
+			query:  `SELECT EXISTS (SELECT "test_accounts"."id" FROM "test_accounts" WHERE (lower("email") = lower('John@Example.com')))`,
+		},
+		{
+			name:   "plain expression",
+			column: "login",
+			value:  "john",
+			query:  `SELECT EXISTS (SELECT "test_accounts"."id" FROM "test_accounts" WHERE ("login" = 'john'))`,
+		},
+		{
+			name:   "no expression",
+			column: "id",
+			value:  "1",
+			query:  `SELECT EXISTS (SELECT "test_accounts"."id" FROM "test_accounts" WHERE ("id" = '1'))`,
+		},
+		{
+			name:   "strict unknown column",
+			column: "name = 'x' OR 1",
+			value:  "x",
+			strict: true,
+			err:    ErrStrictViolation,
+		},
+		{
+			name:   "invalid expression",
+			column: "bad",
+			value:  "x",
+			err:    ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := BunCrudRepository[TestAccountEnt, bun.Tx]{
+				ConnSet: subject.conn,
+				Meta:    meta.Parser(TestAccountEntMeta{}),
+			}
+			if tt.strict {
+				repo.Strict = NewStrictMode()
+			}
+
+			if tt.query != "" {
+				subject.conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(tt.query) + "$").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			}
+
+			res, err := repo.IsColumnValueUnique(context.Background(), nil, tt.column, tt.value)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.False(t, res)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// UpdateAll updates many entities by primary key in one bulk UPDATE ... FROM (VALUES ...) statement.
+// Columns to update and returning columns apply to every entity, as for UpdateOne. Returned rows are
+// matched to entities by primary key, entities without one aren't refreshed with returned values.
+func (r BunCrudRepository[E, T]) UpdateAll(
+	ctx context.Context,
+	tx bun.IDB,
+	entities []E,
+	columnsToUpdate []string,
+	columns []string,
+) ([]E, error) {
+	if len(entities) == 0 {
+		return entities, nil
+	}
+
+	if err := r.checkColumns("update all", columnsToUpdate...); err != nil {
+		return entities, fmt.Errorf("update all: %w", err)
+	}
+
+	if err := r.checkColumns("update all", columns...); err != nil {
+		return entities, fmt.Errorf("update all: %w", err)
+	}
+
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return entities, fmt.Errorf("update all: %w", err)
+	}
+
+	if err := r.normalizeAll(tx, entities); err != nil {
+		return entities, fmt.Errorf("update all: %w", err)
+	}
+
+	if err := r.validate(ctx, entities...); err != nil {
+		return entities, fmt.Errorf("update all: %w", err)
+	}
+
+	if err := r.checkRelated(ctx, tx, entities, columnsToUpdate); err != nil {
+		return entities, fmt.Errorf("update all: %w", err)
+	}
+
+	for i := range entities {
+		if err := r.setChecksum(tx, &entities[i]); err != nil {
+			return entities, fmt.Errorf("update all: %w", err)
+		}
+	}
+
+	if cs, ok := any(entities[0]).(Checksummed); ok && len(columnsToUpdate) > 0 {
+		columnsToUpdate = append(columnsToUpdate[:len(columnsToUpdate):len(columnsToUpdate)], cs.ChecksumColumn())
+	}
+
+	generated := generatedColumns(entities[0])
+	returning := withGenerated(columns, generated)
+
+	table := tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem())
+	scan := len(returning) > 0 && !r.NoReturning && len(table.PKs) > 0
+
+	if scan && !slices.Contains(returning, "*") {
+		for _, f := range table.PKs {
+			if !slices.Contains(returning, f.Name) {
+				returning = append(returning[:len(returning):len(returning)], f.Name)
+			}
+		}
+	}
+
+	query := tx.NewUpdate().
+		Model(&entities).
+		Returning(r.returning(strings.Join(returning, ",")))
+
+	if len(columnsToUpdate) > 0 {
+		query.Column(withoutGenerated(columnsToUpdate, generated)...)
+	} else if len(generated) > 0 {
+		query.ExcludeColumn(generated...)
+	}
+
+	if !scan {
+		if _, err := query.Bulk().Exec(ctx); err != nil {
+			return entities, fmt.Errorf("update all: %w", dbError(err))
+		}
+
+		return entities, nil
+	}
+
+	var returned []E
+
+	if _, err := query.Bulk().Exec(ctx, &returned); err != nil {
+		return entities, fmt.Errorf("update all: %w", dbError(err))
+	}
+
+	mergeReturned(table, entities, returned, returning)
+
+	return entities, nil
+}
+
+// mergeReturned copies returned columns of RETURNING rows onto the entities with the same primary key,
+// Postgres doesn't return rows of UPDATE ... FROM in the order of the entities.
+func mergeReturned[E any](table *schema.Table, entities []E, returned []E, columns []string) {
+	fields := table.Fields
+	if !slices.Contains(columns, "*") {
+		fields = make([]*schema.Field, 0, len(columns))
+
+		for _, c := range columns {
+			if f := table.LookupField(c); f != nil {
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	byPk := make(map[string]int, len(entities))
+	for i := range entities {
+		byPk[pkKey(table, reflect.ValueOf(&entities[i]).Elem())] = i
+	}
+
+	for i := range returned {
+		src := reflect.ValueOf(&returned[i]).Elem()
+
+		j, ok := byPk[pkKey(table, src)]
+		if !ok {
+			continue
+		}
+
+		dst := reflect.ValueOf(&entities[j]).Elem()
+		for _, f := range fields {
+			f.Value(dst).Set(f.Value(src))
+		}
+	}
+}
+
+// pkKey returns an unambiguous key of the primary key values of a struct.
+func pkKey(table *schema.Table, strct reflect.Value) string {
+	values := make([]any, len(table.PKs))
+	for i, f := range table.PKs {
+		values[i] = f.Value(strct).Interface()
+	}
+
+	return fmt.Sprintf("%#v", values)
+}
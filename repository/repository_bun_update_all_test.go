@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_UpdateAll(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		mock            func(set *MockBunConnSet)
+		entities        []TestSimpleEnt
+		columnsToUpdate []string
+		columnsToReturn []string
+		expected        func(t *testing.T, res []TestSimpleEnt, err error)
+	}{
+		{
+			name: "update all",
+			mock: func(conn *MockBunConnSet) {
+				rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "first").AddRow(2, "second")
+
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`WITH "_data" ("id", "name") AS (VALUES (1::BIGINT, 'a'::VARCHAR), (2::BIGINT, 'b'::VARCHAR)) UPDATE "test_simple_entities" AS "test_simple_entities" SET "name" = _data."name" FROM _data WHERE ("test_simple_entities"."id" = _data."id") RETURNING id,name`) + "$").
+					WillReturnRows(rows)
+			},
+			entities:        []TestSimpleEnt{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}},
+			columnsToUpdate: []string{"name"},
+			columnsToReturn: []string{"id", "name"},
+			expected: func(t *testing.T, res []TestSimpleEnt, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, []TestSimpleEnt{{ID: 1, Name: "first"}, {ID: 2, Name: "second"}}, res)
+			},
+		},
+		{
+			name: "update all matches returned rows by pk",
+			mock: func(conn *MockBunConnSet) {
+				rows := sqlmock.NewRows([]string{"name", "id"}).AddRow("second", 2).AddRow("first", 1)
+
+				conn.Mock.ExpectQuery("^" + regexp.QuoteMeta(`WITH "_data" ("id", "name") AS (VALUES (1::BIGINT, 'a'::VARCHAR), (2::BIGINT, 'b'::VARCHAR)) UPDATE "test_simple_entities" AS "test_simple_entities" SET "name" = _data."name" FROM _data WHERE ("test_simple_entities"."id" = _data."id") RETURNING name,id`) + "$").
+					WillReturnRows(rows)
+			},
+			entities:        []TestSimpleEnt{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}},
+			columnsToUpdate: []string{"name"},
+			columnsToReturn: []string{"name"},
+			expected: func(t *testing.T, res []TestSimpleEnt, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, []TestSimpleEnt{{ID: 1, Name: "first"}, {ID: 2, Name: "second"}}, res)
+			},
+		},
+		{
+			name:     "update all without entities",
+			mock:     func(conn *MockBunConnSet) {},
+			entities: nil,
+			expected: func(t *testing.T, res []TestSimpleEnt, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Empty(t, res)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+			res, err := repo.UpdateAll(context.Background(), nil, tt.entities, tt.columnsToUpdate, tt.columnsToReturn)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+
+			tt.expected(t, res, err)
+		})
+	}
+}
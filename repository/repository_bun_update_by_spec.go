@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// UpdateBySpec sets values on all rows matching spec in one UPDATE statement and returns the number of rows affected.
+// Value keys are presenter or persistence names. Spec joins are not applied.
+func (r BunCrudRepository[E, T]) UpdateBySpec(
+	ctx context.Context,
+	tx bun.IDB,
+	values map[string]any,
+	spec dataset.Specifier,
+) (int, error) {
+	if err := r.guardFullTable(ctx, spec); err != nil {
+		return 0, fmt.Errorf("update by spec: %w", err)
+	}
+
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("update by spec: %w", err)
+	}
+
+	if err := r.checkValueColumns(tx, values); err != nil {
+		return 0, fmt.Errorf("update by spec: %w", err)
+	}
+
+	if err := r.checkChecksumValues(values); err != nil {
+		return 0, fmt.Errorf("update by spec: %w", err)
+	}
+
+	values, err = r.normalizeValues(values)
+	if err != nil {
+		return 0, fmt.Errorf("update by spec: %w", err)
+	}
+
+	query, err := r.newUpdateBySpec(tx, values, spec)
+	if err != nil {
+		return 0, fmt.Errorf("update by spec: %w", err)
+	}
+
+	res, err := query.Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("update by spec: %w", dbError(err))
+	}
+
+	rows, err := res.RowsAffected()
+
+	return int(rows), err
+}
+
+// checkValueColumns rejects empty values and keys not naming entity columns.
+func (r BunCrudRepository[E, T]) checkValueColumns(tx bun.IDB, values map[string]any) error {
+	if len(values) == 0 {
+		return ErrEmptyValues
+	}
+
+	table := tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem())
+
+	for _, k := range sortedKeys(values) {
+		if table.LookupField(r.persistenceName(k)) == nil {
+			return fmt.Errorf("%s: %w", k, ErrUnknownColumn)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_UpdateBySpec(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		values   map[string]any
+		spec     dataset.Specifier
+		expected func(t *testing.T, res int, err error)
+	}{
+		{
+			name: "update by spec",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`UPDATE "test_simple_entities" AS "test_simple_entities" SET "name" = 'Jane' WHERE (test_simple_entities.name = 'John')`) + "$").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			values: map[string]any{"name": "Jane"},
+			spec:   dataspec.NewEqual("name", "John"),
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 2, res)
+			},
+		},
+		{
+			name:   "update by spec of unknown column",
+			mock:   func(conn *MockBunConnSet) {},
+			values: map[string]any{"unknown": 1},
+			spec:   dataspec.NewEqual("name", "John"),
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrUnknownColumn)
+			},
+		},
+		{
+			name:   "update by spec without values",
+			mock:   func(conn *MockBunConnSet) {},
+			values: nil,
+			spec:   dataspec.NewEqual("name", "John"),
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrEmptyValues)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			res, err := repo.UpdateBySpec(context.Background(), nil, tt.values, tt.spec)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+
+			tt.expected(t, res, err)
+		})
+	}
+}
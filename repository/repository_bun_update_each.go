@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// PKValues new column values for the row with the given primary key.
+type PKValues struct {
+	PK     metadata.PrimaryKey
+	Values map[string]any
+}
+
+// UpdateEachByPk updates many rows with per row values in one UPDATE ... FROM (VALUES ...) statement.
+// Every update must set the same columns and have a primary key with the same keys, primary keys must be
+// unique and generated columns can't be set. Keys are presenter or persistence names.
+func (r BunCrudRepository[E, T]) UpdateEachByPk(
+	ctx context.Context,
+	tx bun.IDB,
+	updates []PKValues,
+) (int, error) {
+	if len(updates) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("update each by pk: %w", err)
+	}
+
+	pkKeys := updates[0].PK.SortedKeys()
+	valueKeys := sortedKeys(updates[0].Values)
+
+	if len(pkKeys) == 0 {
+		return 0, fmt.Errorf("update each by pk: primary key: %w", ErrEmptyValues)
+	}
+
+	if len(valueKeys) == 0 {
+		return 0, fmt.Errorf("update each by pk: %w", ErrInconsistentColumns)
+	}
+
+	table := tx.Dialect().Tables().Get(reflect.TypeOf((*E)(nil)).Elem())
+	pkFields := make([]*schema.Field, len(pkKeys))
+	valueFields := make([]*schema.Field, len(valueKeys))
+
+	for i, k := range pkKeys {
+		if pkFields[i] = table.LookupField(r.persistenceName(k)); pkFields[i] == nil {
+			return 0, fmt.Errorf("update each by pk %s: %w", k, ErrUnknownColumn)
+		}
+	}
+
+	generated := generatedColumns(*new(E))
+
+	for i, k := range valueKeys {
+		if valueFields[i] = table.LookupField(r.persistenceName(k)); valueFields[i] == nil {
+			return 0, fmt.Errorf("update each by pk %s: %w", k, ErrUnknownColumn)
+		}
+
+		if slices.Contains(generated, valueFields[i].Name) {
+			return 0, fmt.Errorf("update each by pk: generated column %s: %w", valueFields[i].Name, ErrInvalidValue)
+		}
+
+		if slices.Contains(valueFields[:i], valueFields[i]) {
+			return 0, fmt.Errorf("update each by pk: duplicate column %s: %w", valueFields[i].Name, ErrInconsistentColumns)
+		}
+	}
+
+	if err := r.checkColumns("update each by pk", fieldNames(pkFields, valueFields)...); err != nil {
+		return 0, fmt.Errorf("update each by pk: %w", err)
+	}
+
+	if err := r.checkChecksumValues(updates[0].Values); err != nil {
+		return 0, fmt.Errorf("update each by pk: %w", err)
+	}
+
+	rows := make([]any, 0, len(updates))
+	seen := make(map[string]struct{}, len(updates))
+
+	for i, u := range updates {
+		if !sameKeys(u.PK, pkKeys) {
+			return 0, fmt.Errorf("update each by pk: primary key %d %v, expected %v: %w", i, u.PK.SortedKeys(), pkKeys, ErrInvalidValue)
+		}
+
+		if !sameKeys(u.Values, valueKeys) {
+			return 0, fmt.Errorf("update each by pk: %w", ErrInconsistentColumns)
+		}
+
+		values, err := r.normalizeValues(u.Values)
+		if err != nil {
+			return 0, fmt.Errorf("update each by pk: %w", err)
+		}
+
+		row := make([]any, 0, len(pkKeys)+len(valueKeys))
+
+		for _, k := range pkKeys {
+			row = append(row, r.bindValue(u.PK[k]))
+		}
+
+		pk := fmt.Sprintf("%#v", row)
+		if _, ok := seen[pk]; ok {
+			return 0, fmt.Errorf("update each by pk: duplicate primary key %v: %w", u.PK, ErrInvalidValue)
+		}
+
+		seen[pk] = struct{}{}
+
+		for _, k := range valueKeys {
+			row = append(row, r.bindValue(values[k]))
+		}
+
+		rows = append(rows, row)
+	}
+
+	columns := make([]bun.Ident, 0, len(pkKeys)+len(valueKeys))
+
+	query := tx.NewUpdate().Model((*E)(nil))
+
+	for _, field := range pkFields {
+		columns = append(columns, bun.Ident(field.Name))
+
+		query.Where("?TableAlias.? = _data.?::?", bun.Ident(field.Name), bun.Ident(field.Name), bun.Safe(field.UserSQLType))
+	}
+
+	for _, field := range valueFields {
+		columns = append(columns, bun.Ident(field.Name))
+
+		query.Set("? = _data.?::?", bun.Ident(field.Name), bun.Ident(field.Name), bun.Safe(field.UserSQLType))
+	}
+
+	query.TableExpr("(VALUES ?) AS _data (?)", bun.In(rows), bun.In(columns))
+
+	res, err := query.Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("update each by pk: %w", dbError(err))
+	}
+
+	affected, err := res.RowsAffected()
+
+	return int(affected), err
+}
+
+// fieldNames returns persistence names of the fields.
+func fieldNames(fields ...[]*schema.Field) []string {
+	var names []string
+
+	for _, fs := range fields {
+		for _, f := range fs {
+			names = append(names, f.Name)
+		}
+	}
+
+	return names
+}
+
+// persistenceName maps a presenter name to its persistence name, persistence names are returned as is.
+func (r BunCrudRepository[E, T]) persistenceName(name string) string {
+	if column := r.Meta.PresenterToPersistence(name); column != "" {
+		return column
+	}
+
+	return name
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sameKeys[V any](m map[string]V, keys []string) bool {
+	if len(m) != len(keys) {
+		return false
+	}
+
+	for _, k := range keys {
+		if _, ok := m[k]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
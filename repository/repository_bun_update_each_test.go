@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_UpdateEachByPk(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		updates  []PKValues
+		expected func(t *testing.T, res int, err error)
+	}{
+		{
+			name: "update each by pk",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`UPDATE "test_complex_entities" AS "test_complex_entities" SET "complex_name" = _data."complex_name"::VARCHAR FROM (VALUES (1, 2, 'a'), (3, 4, 'b')) AS _data ("first_id", "second_id", "complex_name") WHERE ("test_complex_entities"."first_id" = _data."first_id"::BIGINT) AND ("test_complex_entities"."second_id" = _data."second_id"::BIGINT)`) + "$").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			updates: []PKValues{
+				{
+					PK:     metadata.PrimaryKey{"firstId": 1, "secondId": 2},
+					Values: map[string]any{"complexName": "a"},
+				},
+				{
+					PK:     metadata.PrimaryKey{"firstId": 3, "secondId": 4},
+					Values: map[string]any{"complexName": "b"},
+				},
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 2, res)
+			},
+		},
+		{
+			name: "update each by pk with different columns",
+			mock: func(conn *MockBunConnSet) {},
+			updates: []PKValues{
+				{
+					PK:     metadata.PrimaryKey{"firstId": 1, "secondId": 2},
+					Values: map[string]any{"complexName": "a"},
+				},
+				{
+					PK:     metadata.PrimaryKey{"firstId": 3, "secondId": 4},
+					Values: map[string]any{"complexDescription": "b"},
+				},
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrInconsistentColumns)
+			},
+		},
+		{
+			name: "update each by pk with empty pk",
+			mock: func(conn *MockBunConnSet) {},
+			updates: []PKValues{
+				{
+					PK:     metadata.PrimaryKey{},
+					Values: map[string]any{"complexName": "a"},
+				},
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrEmptyValues)
+			},
+		},
+		{
+			name: "update each by pk with different pk keys",
+			mock: func(conn *MockBunConnSet) {},
+			updates: []PKValues{
+				{
+					PK:     metadata.PrimaryKey{"firstId": 1, "secondId": 2},
+					Values: map[string]any{"complexName": "a"},
+				},
+				{
+					PK:     metadata.PrimaryKey{"firstId": 3},
+					Values: map[string]any{"complexName": "b"},
+				},
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrInvalidValue)
+			},
+		},
+		{
+			name: "update each by pk with duplicate pk",
+			mock: func(conn *MockBunConnSet) {},
+			updates: []PKValues{
+				{
+					PK:     metadata.PrimaryKey{"firstId": 1, "secondId": 2},
+					Values: map[string]any{"complexName": "a"},
+				},
+				{
+					PK:     metadata.PrimaryKey{"firstId": 1, "secondId": 2},
+					Values: map[string]any{"complexName": "b"},
+				},
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrInvalidValue)
+			},
+		},
+		{
+			name: "update each by pk with duplicate column",
+			mock: func(conn *MockBunConnSet) {},
+			updates: []PKValues{
+				{
+					PK:     metadata.PrimaryKey{"firstId": 1, "secondId": 2},
+					Values: map[string]any{"complexName": "a", "complex_name": "b"},
+				},
+			},
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrInconsistentColumns)
+			},
+		},
+		{
+			name:    "update each by pk without updates",
+			mock:    func(conn *MockBunConnSet) {},
+			updates: nil,
+			expected: func(t *testing.T, res int, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 0, res)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestComplexEntRepository(subject.conn)
+
+			tt.mock(subject.conn)
+
+			res, err := repo.UpdateEachByPk(context.Background(), nil, tt.updates)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+
+			tt.expected(t, res, err)
+		})
+	}
+}
+
+func TestBunCrudRepository_UpdateEachByPkNormalized(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestNormalizedEntRepository(subject.conn)
+
+	subject.conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`UPDATE "test_normalized_entities" AS "test_normalized_entities" SET "email" = _data."email"::VARCHAR FROM (VALUES (1, 'john@example.com')) AS _data ("id", "email") WHERE ("test_normalized_entities"."id" = _data."id"::BIGINT)`) + "$").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res, err := repo.UpdateEachByPk(context.Background(), nil, []PKValues{
+		{PK: metadata.PrimaryKey{"id": 1}, Values: map[string]any{"email": " John@Example.com "}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res)
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+}
+
+func TestBunCrudRepository_UpdateEachByPkGenerated(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestGeneratedEntRepository(subject.conn)
+
+	_, err := repo.UpdateEachByPk(context.Background(), nil, []PKValues{
+		{PK: metadata.PrimaryKey{"id": 1}, Values: map[string]any{"searchBy": "john"}},
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+}
+
+func TestBunCrudRepository_UpdateEachByPkChecksum(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestChecksumEntRepository(subject.conn)
+
+	_, err := repo.UpdateEachByPk(context.Background(), nil, []PKValues{
+		{PK: metadata.PrimaryKey{"id": 1}, Values: map[string]any{"email": "j@x.io"}},
+	})
+
+	assert.ErrorIs(t, err, ErrChecksumNotSupported)
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+}
+
+func TestBunCrudRepository_UpdateEachByPkStrict(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := NewTestComplexEntRepository(subject.conn)
+	repo.Strict = NewStrictMode()
+
+	subject.conn.Mock.ExpectExec("^UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := repo.UpdateEachByPk(context.Background(), nil, []PKValues{
+		{PK: metadata.PrimaryKey{"firstId": 1, "secondId": 2}, Values: map[string]any{"complexName": "a"}},
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, repo.Strict.Report())
+}
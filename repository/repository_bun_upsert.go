@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// FirstOrCreate returns the first row matching spec or inserts the entity.
+// The insert uses ON CONFLICT DO NOTHING followed by a re-select, so a unique constraint covering
+// the spec columns makes concurrent calls return the same row. Reads go to the write pool when tx is nil.
+func (r BunCrudRepository[E, T]) FirstOrCreate(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("first or create: %w", err)
+	}
+
+	found, err := r.FindOne(ctx, tx, columns, spec)
+	if err == nil {
+		return found, nil
+	}
+
+	if !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("first or create: %w", err)
+	}
+
+	if err := r.prepareInsertOne(ctx, tx, entity); err != nil {
+		return nil, fmt.Errorf("first or create: %w", err)
+	}
+
+	inserted, err := r.insertIgnoringConflict(ctx, tx, entity, columns)
+	if err != nil {
+		return nil, fmt.Errorf("first or create: %w", err)
+	}
+
+	if inserted {
+		return entity, nil
+	}
+
+	existing, err := r.FindOne(ctx, tx, columns, spec)
+	if err != nil {
+		return nil, fmt.Errorf("first or create: %w", err)
+	}
+
+	return existing, nil
+}
+
+// FirstOrCreateLocked is FirstOrCreate for natural keys without a unique constraint.
+// Lookup and insert run under a transaction-scoped advisory lock on the hash of the table name and key,
+// so tx must be a transaction; a new one is started when tx is nil.
+func (r BunCrudRepository[E, T]) FirstOrCreateLocked(
+	ctx context.Context,
+	tx bun.IDB,
+	key string,
+	spec dataset.Specifier,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	var res *E
+
+	err := r.inTx(ctx, tx, func(ctx context.Context, tx bun.IDB) error {
+		_, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext(?))", r.Meta.EntityName()+":"+key)
+		if err != nil {
+			return dbError(err)
+		}
+
+		found, err := r.FindOne(ctx, tx, columns, spec)
+		if err == nil {
+			res = found
+
+			return nil
+		}
+
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+
+		if err := r.prepareInsertOne(ctx, tx, entity); err != nil {
+			return err
+		}
+
+		query, err := r.newInsertOne(tx, entity, columns)
+		if err != nil {
+			return err
+		}
+
+		if _, err = query.Exec(ctx); err != nil {
+			return dbError(err)
+		}
+
+		res = entity
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("first or create locked: %w", err)
+	}
+
+	return res, nil
+}
+
+// UpdateOrCreate updates rows matching spec with values and returns the first one, or inserts the entity
+// with values applied when nothing matches. A concurrent insert detected through a conflict is retried as update.
+// Value keys are presenter or persistence names.
+func (r BunCrudRepository[E, T]) UpdateOrCreate(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	values map[string]any,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	if r.NoReturning {
+		return nil, fmt.Errorf("update or create: %w", ErrReturningRequired)
+	}
+
+	if err := r.checkColumns("update or create", columns...); err != nil {
+		return nil, fmt.Errorf("update or create: %w", err)
+	}
+
+	if err := r.guardFullTable(ctx, spec); err != nil {
+		return nil, fmt.Errorf("update or create: %w", err)
+	}
+
+	tx, err := r.writeDB(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("update or create: %w", err)
+	}
+
+	if err := r.checkChecksumValues(values); err != nil {
+		return nil, fmt.Errorf("update or create: %w", err)
+	}
+
+	values, err = r.normalizeValues(values)
+	if err != nil {
+		return nil, fmt.Errorf("update or create: %w", err)
+	}
+
+	if err := r.setValues(tx, entity, values); err != nil {
+		return nil, fmt.Errorf("update or create: %w", err)
+	}
+
+	returning := "*"
+	if len(columns) > 0 {
+		returning = strings.Join(columns, ",")
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		var updated []E
+
+		query, err := r.newUpdateBySpec(tx, values, spec)
+		if err != nil {
+			return nil, fmt.Errorf("update or create: %w", err)
+		}
+
+		_, err = query.
+			Returning(returning).
+			Exec(ctx, &updated)
+		if err != nil {
+			return nil, fmt.Errorf("update or create: %w", err)
+		}
+
+		if len(updated) > 0 {
+			return &updated[0], nil
+		}
+
+		if attempt == 0 {
+			if err := r.prepareInsertOne(ctx, tx, entity); err != nil {
+				return nil, fmt.Errorf("update or create: %w", err)
+			}
+		}
+
+		inserted, err := r.insertIgnoringConflict(ctx, tx, entity, columns)
+		if err != nil {
+			return nil, fmt.Errorf("update or create: %w", err)
+		}
+
+		if inserted {
+			return entity, nil
+		}
+	}
+
+	return nil, fmt.Errorf("update or create: %w", ErrConcurrentCreate)
+}
+
+func (r BunCrudRepository[E, T]) insertIgnoringConflict(
+	ctx context.Context,
+	tx bun.IDB,
+	entity *E,
+	columns []string,
+) (bool, error) {
+	query, err := r.newInsertOne(tx, entity, columns)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := query.
+		On("CONFLICT DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		return false, err //nolint:wrapcheck
+	}
+
+	rows, err := res.RowsAffected()
+
+	return rows > 0, err //nolint:wrapcheck
+}
+
+// newUpdateBySpec builds UPDATE ... SET values WHERE spec. Spec joins are not applied.
+func (r BunCrudRepository[E, T]) newUpdateBySpec(
+	tx bun.IDB,
+	values map[string]any,
+	spec dataset.Specifier,
+) (*bun.UpdateQuery, error) {
+	if len(values) == 0 {
+		return nil, ErrEmptyValues
+	}
+
+	query := tx.NewUpdate().Model((*E)(nil))
+
+	for _, k := range sortedKeys(values) {
+		query.Set("? = ?", bun.Ident(r.persistenceName(k)), r.bindValue(values[k]))
+	}
+
+	if spec != nil && !spec.IsEmpty() {
+		query.Where(spec.Query(r.specMeta()), r.bindValues(spec.Values())...)
+	}
+
+	return query, nil
+}
+
+// setValues assigns values to the entity fields by presenter or persistence name.
+func (r BunCrudRepository[E, T]) setValues(tx bun.IDB, entity *E, values map[string]any) error {
+	table := tx.Dialect().Tables().Get(reflect.TypeOf(entity).Elem())
+	strct := reflect.ValueOf(entity).Elem()
+
+	for k, v := range values {
+		field := table.LookupField(r.persistenceName(k))
+		if field == nil {
+			return fmt.Errorf("%s: %w", k, ErrUnknownColumn)
+		}
+
+		dst := field.Value(strct)
+
+		if v == nil {
+			dst.Set(reflect.Zero(dst.Type()))
+
+			continue
+		}
+
+		src := reflect.ValueOf(v)
+		if src.Type().ConvertibleTo(dst.Type()) {
+			dst.Set(src.Convert(dst.Type()))
+
+			continue
+		}
+
+		// scanner fields such as decimals accept their textual form
+		if scanner, ok := dst.Addr().Interface().(sql.Scanner); ok {
+			if err := scanner.Scan(v); err != nil {
+				return fmt.Errorf("%s: %w: %w", k, ErrInvalidValue, err)
+			}
+
+			continue
+		}
+
+		return fmt.Errorf("%s: %T to %s: %w", k, v, dst.Type(), ErrInvalidValue)
+	}
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunCrudRepository_FirstOrCreate(t *testing.T) {
+	t.Parallel()
+
+	selectQuery := "^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" WHERE (test_simple_entities.name = 'a')`) + "$"
+	insertQuery := "^" + regexp.QuoteMeta(`INSERT INTO "test_simple_entities" AS "test_simple_entities" ("id", "name") VALUES (0, 'a') ON CONFLICT DO NOTHING`) + "$"
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		expected func(t *testing.T, res *TestSimpleEnt, err error)
+	}{
+		{
+			name: "first or create existing",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery(selectQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(7, "a"))
+			},
+			expected: func(t *testing.T, res *TestSimpleEnt, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 7, res.ID)
+			},
+		},
+		{
+			name: "first or create created",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery(selectQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+				conn.Mock.ExpectExec(insertQuery).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expected: func(t *testing.T, res *TestSimpleEnt, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, "a", res.Name)
+			},
+		},
+		{
+			name: "first or create lost race",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery(selectQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+				conn.Mock.ExpectExec(insertQuery).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				conn.Mock.ExpectQuery(selectQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(9, "a"))
+			},
+			expected: func(t *testing.T, res *TestSimpleEnt, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 9, res.ID)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+			tt.mock(subject.conn)
+
+			res, err := repo.FirstOrCreate(
+				context.Background(),
+				nil,
+				dataspec.NewEqual("name", "a"),
+				&TestSimpleEnt{Name: "a"},
+				nil,
+			)
+			tt.expected(t, res, err)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestBunCrudRepository_UpdateOrCreate(t *testing.T) {
+	t.Parallel()
+
+	updateQuery := "^" + regexp.QuoteMeta(`UPDATE "test_simple_entities" AS "test_simple_entities" SET "name" = 'b' WHERE (test_simple_entities.id = 1) RETURNING *`) + "$"
+	insertQuery := "^" + regexp.QuoteMeta(`INSERT INTO "test_simple_entities" AS "test_simple_entities" ("id", "name") VALUES (1, 'b') ON CONFLICT DO NOTHING`) + "$"
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		values   map[string]any
+		expected func(t *testing.T, res *TestSimpleEnt, err error)
+	}{
+		{
+			name: "update or create updated",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery(updateQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "b"))
+			},
+			values: map[string]any{"name": "b"},
+			expected: func(t *testing.T, res *TestSimpleEnt, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, "b", res.Name)
+			},
+		},
+		{
+			name: "update or create created",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery(updateQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+				conn.Mock.ExpectExec(insertQuery).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			values: map[string]any{"name": "b"},
+			expected: func(t *testing.T, res *TestSimpleEnt, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, "b", res.Name)
+			},
+		},
+		{
+			name: "update or create lost race",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery(updateQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+				conn.Mock.ExpectExec(insertQuery).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				conn.Mock.ExpectQuery(updateQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "b"))
+			},
+			values: map[string]any{"name": "b"},
+			expected: func(t *testing.T, res *TestSimpleEnt, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 1, res.ID)
+			},
+		},
+		{
+			name:   "update or create with unknown column",
+			mock:   func(conn *MockBunConnSet) {},
+			values: map[string]any{"unknown": "b"},
+			expected: func(t *testing.T, res *TestSimpleEnt, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrUnknownColumn)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+			tt.mock(subject.conn)
+
+			res, err := repo.UpdateOrCreate(
+				context.Background(),
+				nil,
+				dataspec.NewEqual("id", 1),
+				tt.values,
+				&TestSimpleEnt{ID: 1},
+				nil,
+			)
+			tt.expected(t, res, err)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestBunCrudRepository_FirstOrCreateLocked(t *testing.T) {
+	t.Parallel()
+
+	lockQuery := "^" + regexp.QuoteMeta(`SELECT pg_advisory_xact_lock(hashtext('TestSimpleEnt:a'))`) + "$"
+	selectQuery := "^" + regexp.QuoteMeta(`SELECT "test_simple_entities"."id", "test_simple_entities"."name" FROM "test_simple_entities" WHERE (test_simple_entities.name = 'a')`) + "$"
+
+	tests := []struct {
+		name     string
+		mock     func(set *MockBunConnSet)
+		expected func(t *testing.T, res *TestSimpleEnt, err error)
+	}{
+		{
+			name: "first or create locked existing",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectBegin()
+				conn.Mock.ExpectExec(lockQuery).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				conn.Mock.ExpectQuery(selectQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(7, "a"))
+				conn.Mock.ExpectCommit()
+			},
+			expected: func(t *testing.T, res *TestSimpleEnt, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, 7, res.ID)
+			},
+		},
+		{
+			name: "first or create locked created",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectBegin()
+				conn.Mock.ExpectExec(lockQuery).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				conn.Mock.ExpectQuery(selectQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+				conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`INSERT INTO "test_simple_entities" ("id", "name") VALUES (0, 'a')`)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				conn.Mock.ExpectCommit()
+			},
+			expected: func(t *testing.T, res *TestSimpleEnt, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+				assert.Equal(t, "a", res.Name)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestSimpleEntRepository(subject.conn)
+			tt.mock(subject.conn)
+
+			res, err := repo.FirstOrCreateLocked(
+				context.Background(),
+				nil,
+				"a",
+				dataspec.NewEqual("name", "a"),
+				&TestSimpleEnt{Name: "a"},
+				nil,
+			)
+			tt.expected(t, res, err)
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Validatable entity validates itself before CreateOne, CreateAll, UpdateOne and the bulk writes.
+type Validatable interface {
+	Validate(ctx context.Context) error
+}
+
+// StructValidator validates entities before writes, e.g. *validator.Validate of go-playground/validator.
+// Errors listing field errors, e.g. validator.ValidationErrors, are returned as ValidationError with
+// presenter field names and the failed tags as messages.
+type StructValidator interface {
+	StructCtx(ctx context.Context, s any) error
+}
+
+// structFieldError field error of a StructValidator, e.g. validator.FieldError. StructField is the Go field
+// name, Field the name the validator reports it by.
+type structFieldError interface {
+	Field() string
+	StructField() string
+	Tag() string
+}
+
+// FieldError invalid entity field, Field is the presenter name.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError structured validation failure, it matches ErrValidation.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func NewValidationError(fields ...FieldError) *ValidationError {
+	return &ValidationError{Fields: fields}
+}
+
+func (r *ValidationError) Error() string {
+	items := make([]string, len(r.Fields))
+	for i, f := range r.Fields {
+		items[i] = f.Field + ": " + f.Message
+	}
+
+	return ErrValidation.Error() + ": " + strings.Join(items, ", ")
+}
+
+func (r *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// validate runs the repository validator and Validatable entities, errors not matching ErrValidation are wrapped.
+func (r BunCrudRepository[E, T]) validate(ctx context.Context, entities ...E) error {
+	for i := range entities {
+		if err := r.validateOne(ctx, &entities[i]); err != nil {
+			if !errors.Is(err, ErrValidation) {
+				err = fmt.Errorf("%w: %w", ErrValidation, err)
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r BunCrudRepository[E, T]) validateOne(ctx context.Context, entity *E) error {
+	if r.Validator != nil {
+		if err := r.Validator.StructCtx(ctx, entity); err != nil {
+			return r.validationError(err)
+		}
+	}
+
+	if v, ok := any(*entity).(Validatable); ok {
+		return v.Validate(ctx)
+	}
+
+	if v, ok := any(entity).(Validatable); ok {
+		return v.Validate(ctx)
+	}
+
+	return nil
+}
+
+// validationError maps a StructValidator error listing field errors to ValidationError, other errors are
+// returned as is.
+func (r BunCrudRepository[E, T]) validationError(err error) error {
+	v := reflect.ValueOf(err)
+	if v.Kind() != reflect.Slice || v.Len() == 0 {
+		return err
+	}
+
+	fields := make([]FieldError, 0, v.Len())
+
+	for i := 0; i < v.Len(); i++ {
+		fe, ok := v.Index(i).Interface().(structFieldError)
+		if !ok {
+			return err
+		}
+
+		fields = append(fields, FieldError{Field: r.presenterField(fe), Message: fe.Tag()})
+	}
+
+	return NewValidationError(fields...)
+}
+
+// presenterField returns the presenter name of the field of a field error, by its Go field name or,
+// for validators reporting persistence names, through PersistencePresenterMapping.
+func (r BunCrudRepository[E, T]) presenterField(fe structFieldError) string {
+	if presenter := r.Meta.FieldToPresenter(fe.StructField()); presenter != "" {
+		return presenter
+	}
+
+	if presenter, ok := r.Meta.PersistencePresenterMapping()[fe.Field()]; ok {
+		return presenter
+	}
+
+	return fe.Field()
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/bun-pg-connector"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+var errTooLong = errors.New("too long")
+
+type TestValidatedEnt struct {
+	bun.BaseModel `bun:"table:test_validated_entities,alias:test_validated_entities"`
+
+	ID    int    `bun:"id,pk" json:"id"`
+	Email string `bun:"email" json:"email"`
+}
+
+func (r TestValidatedEnt) EntityName() string {
+	return "TestValidatedEnt"
+}
+
+func (r TestValidatedEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"id": r.ID}
+}
+
+func (r TestValidatedEnt) Validate(context.Context) error {
+	if !strings.Contains(r.Email, "@") {
+		return NewValidationError(FieldError{Field: "email", Message: "must be an email"})
+	}
+
+	return nil
+}
+
+type TestValidatedEntMeta struct {
+	TestValidatedEnt
+}
+
+func (r TestValidatedEntMeta) Entity() metadata.Entity { return r.TestValidatedEnt }
+
+func (r TestValidatedEntMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func NewTestValidatedEntRepository(
+	connSet bunpgconnector.BunConnSet,
+) BunCrudRepository[TestValidatedEnt, bun.Tx] {
+	return BunCrudRepository[TestValidatedEnt, bun.Tx]{
+		ConnSet: connSet,
+		Meta:    meta.Parser(TestValidatedEntMeta{}),
+	}
+}
+
+type lengthValidator struct{}
+
+func (r lengthValidator) StructCtx(_ context.Context, s any) error {
+	if len(s.(*TestValidatedEnt).Email) > 10 { //nolint:forcetypeassert
+		return errTooLong
+	}
+
+	return nil
+}
+
+// testFieldError field error of a StructValidator, as validator.FieldError.
+type testFieldError struct {
+	field       string
+	structField string
+	tag         string
+}
+
+func (r testFieldError) Field() string       { return r.field }
+func (r testFieldError) StructField() string { return r.structField }
+func (r testFieldError) Tag() string         { return r.tag }
+
+// testFieldErrors as validator.ValidationErrors.
+type testFieldErrors []testFieldError
+
+func (r testFieldErrors) Error() string {
+	return "validation failed"
+}
+
+type fieldsValidator struct {
+	err error
+}
+
+func (r fieldsValidator) StructCtx(context.Context, any) error {
+	return r.err
+}
+
+func TestBunCrudRepository_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		mock      func(set *MockBunConnSet)
+		validator StructValidator
+		call      func(ctx context.Context, repo BunCrudRepository[TestValidatedEnt, bun.Tx]) error
+		expected  func(t *testing.T, err error)
+	}{
+		{
+			name: "create one with valid entity",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^INSERT INTO \"test_validated_entities\"").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+			call: func(ctx context.Context, repo BunCrudRepository[TestValidatedEnt, bun.Tx]) error {
+				_, err := repo.CreateOne(ctx, nil, &TestValidatedEnt{ID: 1, Email: "j@x.io"}, []string{"*"})
+
+				return err
+			},
+			expected: func(t *testing.T, err error) {
+				t.Helper()
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "create all with invalid entity",
+			mock: func(conn *MockBunConnSet) {},
+			call: func(ctx context.Context, repo BunCrudRepository[TestValidatedEnt, bun.Tx]) error {
+				_, err := repo.CreateAll(ctx, nil, []TestValidatedEnt{{ID: 1, Email: "j@x.io"}, {ID: 2, Email: "john"}}, nil)
+
+				return err
+			},
+			expected: func(t *testing.T, err error) {
+				t.Helper()
+
+				var validationErr *ValidationError
+
+				assert.ErrorIs(t, err, ErrValidation)
+				assert.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, []FieldError{{Field: "email", Message: "must be an email"}}, validationErr.Fields)
+			},
+		},
+		{
+			name:      "update one rejected by validator",
+			mock:      func(conn *MockBunConnSet) {},
+			validator: lengthValidator{},
+			call: func(ctx context.Context, repo BunCrudRepository[TestValidatedEnt, bun.Tx]) error {
+				_, err := repo.UpdateOne(ctx, nil, &TestValidatedEnt{ID: 1, Email: "john@example.com"}, nil, nil)
+
+				return err
+			},
+			expected: func(t *testing.T, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrValidation)
+				assert.ErrorIs(t, err, errTooLong)
+			},
+		},
+		{
+			name: "create one rejected by validator with field errors",
+			mock: func(conn *MockBunConnSet) {},
+			validator: fieldsValidator{err: testFieldErrors{
+				{field: "Email", structField: "Email", tag: "required"},
+				{field: "id", structField: "Ident", tag: "gt"},
+			}},
+			call: func(ctx context.Context, repo BunCrudRepository[TestValidatedEnt, bun.Tx]) error {
+				_, err := repo.CreateOne(ctx, nil, &TestValidatedEnt{}, nil)
+
+				return err
+			},
+			expected: func(t *testing.T, err error) {
+				t.Helper()
+
+				var validationErr *ValidationError
+
+				assert.ErrorIs(t, err, ErrValidation)
+				assert.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, []FieldError{{Field: "email", Message: "required"}, {Field: "id", Message: "gt"}}, validationErr.Fields)
+			},
+		},
+		{
+			name: "first or create with invalid entity",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^SELECT .* FROM \"test_validated_entities\"").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			call: func(ctx context.Context, repo BunCrudRepository[TestValidatedEnt, bun.Tx]) error {
+				_, err := repo.FirstOrCreate(ctx, nil, dataspec.NewEqual("email", "john"), &TestValidatedEnt{Email: "john"}, nil)
+
+				return err
+			},
+			expected: func(t *testing.T, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrValidation)
+			},
+		},
+		{
+			name: "first or create locked with invalid entity",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectBegin()
+				conn.Mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+				conn.Mock.ExpectQuery("^SELECT .* FROM \"test_validated_entities\"").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				conn.Mock.ExpectRollback()
+			},
+			call: func(ctx context.Context, repo BunCrudRepository[TestValidatedEnt, bun.Tx]) error {
+				_, err := repo.FirstOrCreateLocked(
+					ctx, nil, "john", dataspec.NewEqual("email", "john"), &TestValidatedEnt{Email: "john"}, nil,
+				)
+
+				return err
+			},
+			expected: func(t *testing.T, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrValidation)
+			},
+		},
+		{
+			name: "update or create inserting invalid entity",
+			mock: func(conn *MockBunConnSet) {
+				conn.Mock.ExpectQuery("^UPDATE \"test_validated_entities\"").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			call: func(ctx context.Context, repo BunCrudRepository[TestValidatedEnt, bun.Tx]) error {
+				_, err := repo.UpdateOrCreate(
+					ctx, nil, dataspec.NewEqual("id", 1), map[string]any{"email": "john"}, &TestValidatedEnt{ID: 1}, nil,
+				)
+
+				return err
+			},
+			expected: func(t *testing.T, err error) {
+				t.Helper()
+				assert.ErrorIs(t, err, ErrValidation)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject := crudRepositoryShortTestSetUp(t)
+			repo := NewTestValidatedEntRepository(subject.conn)
+			repo.Validator = tt.validator
+
+			tt.mock(subject.conn)
+
+			err := tt.call(context.Background(), repo)
+
+			assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+
+			tt.expected(t, err)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/uptrace/bun"
+)
+
+// Materialized entity is backed by a materialized view, results older than MaxStaleness are reported stale.
+type Materialized interface {
+	MaxStaleness() time.Duration
+}
+
+// ViewRefreshes keeps the last refresh time of materialized views refreshed through the repositories sharing it.
+type ViewRefreshes struct {
+	mu sync.RWMutex
+	at map[string]time.Time
+}
+
+func NewViewRefreshes() *ViewRefreshes {
+	return &ViewRefreshes{at: make(map[string]time.Time)}
+}
+
+// RefreshedAt returns the last refresh time of the view, false when it wasn't refreshed yet.
+func (r *ViewRefreshes) RefreshedAt(view string) (time.Time, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	at, ok := r.at[view]
+
+	return at, ok
+}
+
+func (r *ViewRefreshes) refreshed(view string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.at[view] = at
+}
+
+// ViewResult view rows with their staleness, Stale is set when the refresh time is unknown as well.
+type ViewResult[E any] struct {
+	Items       []E
+	RefreshedAt time.Time
+	Stale       bool
+}
+
+// RefreshMaterializedView refreshes the entity view, concurrently without locking out reads,
+// which requires a unique index on the view.
+func (r BunCrudRepository[E, T]) RefreshMaterializedView(ctx context.Context, tx bun.IDB, concurrently bool) error {
+	if _, ok := any(*new(E)).(Materialized); !ok {
+		return fmt.Errorf("refresh materialized view: %w", ErrViewNotSupported)
+	}
+
+	tx, err := r.primaryDB(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("refresh materialized view: %w", err)
+	}
+
+	query := "REFRESH MATERIALIZED VIEW ?"
+	if concurrently {
+		query = "REFRESH MATERIALIZED VIEW CONCURRENTLY ?"
+	}
+
+	if _, err := tx.ExecContext(ctx, query, bun.Ident(r.Meta.PersistenceName())); err != nil {
+		return fmt.Errorf("refresh materialized view: %w", dbError(err))
+	}
+
+	if r.Refreshes != nil {
+		r.Refreshes.refreshed(r.Meta.PersistenceName(), time.Now())
+	}
+
+	return nil
+}
+
+// RefreshEvery refreshes the entity view every interval until ctx is done, passing refresh errors to onError.
+// It blocks, run it in a goroutine or call RefreshMaterializedView from an external scheduler instead.
+func (r BunCrudRepository[E, T]) RefreshEvery(
+	ctx context.Context,
+	every time.Duration,
+	concurrently bool,
+	onError func(err error),
+) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.RefreshMaterializedView(ctx, nil, concurrently); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// FindAllView is FindAll reporting the staleness of the view by the last refresh tracked in Refreshes.
+func (r BunCrudRepository[E, T]) FindAllView(
+	ctx context.Context,
+	tx bun.IDB,
+	columns []string,
+	spec dataset.Specifier,
+) (ViewResult[E], error) {
+	res := ViewResult[E]{Items: make([]E, 0), Stale: true}
+
+	materialized, ok := any(*new(E)).(Materialized)
+	if !ok {
+		return res, fmt.Errorf("find all view: %w", ErrViewNotSupported)
+	}
+
+	items, err := r.FindAll(ctx, tx, columns, spec)
+	if err != nil {
+		return res, fmt.Errorf("find all view: %w", err)
+	}
+
+	res.Items = items
+
+	if r.Refreshes != nil {
+		if at, ok := r.Refreshes.RefreshedAt(r.Meta.PersistenceName()); ok {
+			res.RefreshedAt = at
+			res.Stale = time.Since(at) > materialized.MaxStaleness()
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type TestReportEnt struct {
+	bun.BaseModel `bun:"table:test_reports,alias:test_reports"`
+
+	Day   string `bun:"day,pk" json:"day"`
+	Total int    `bun:"total" json:"total"`
+}
+
+func (r TestReportEnt) EntityName() string {
+	return "TestReportEnt"
+}
+
+func (r TestReportEnt) PrimaryKey() metadata.PrimaryKey {
+	return metadata.PrimaryKey{"day": r.Day}
+}
+
+func (r TestReportEnt) MaxStaleness() time.Duration {
+	return time.Hour
+}
+
+type TestReportEntMeta struct {
+	TestReportEnt
+}
+
+func (r TestReportEntMeta) Entity() metadata.Entity { return r.TestReportEnt }
+
+func (r TestReportEntMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func TestBunCrudRepository_RefreshMaterializedView(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := BunCrudRepository[TestReportEnt, bun.Tx]{
+		ConnSet:   subject.conn,
+		Meta:      meta.Parser(TestReportEntMeta{}),
+		Refreshes: NewViewRefreshes(),
+	}
+
+	rows := func() *sqlmock.Rows { return sqlmock.NewRows([]string{"day", "total"}).AddRow("2025-01-01", 3) }
+	selectAll := "^" + regexp.QuoteMeta(`SELECT * FROM "test_reports"`) + "$"
+
+	subject.conn.Mock.ExpectQuery(selectAll).WillReturnRows(rows())
+	subject.conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`REFRESH MATERIALIZED VIEW "test_reports"`) + "$").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	subject.conn.Mock.ExpectExec("^" + regexp.QuoteMeta(`REFRESH MATERIALIZED VIEW CONCURRENTLY "test_reports"`) + "$").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	subject.conn.Mock.ExpectQuery(selectAll).WillReturnRows(rows())
+
+	res, err := repo.FindAllView(context.Background(), nil, []string{"*"}, nil)
+	assert.NoError(t, err)
+	assert.True(t, res.Stale)
+	assert.True(t, res.RefreshedAt.IsZero())
+
+	assert.NoError(t, repo.RefreshMaterializedView(context.Background(), nil, false))
+	assert.NoError(t, repo.RefreshMaterializedView(context.Background(), nil, true))
+
+	res, err = repo.FindAllView(context.Background(), nil, []string{"*"}, nil)
+	assert.NoError(t, err)
+	assert.False(t, res.Stale)
+	assert.WithinDuration(t, time.Now(), res.RefreshedAt, time.Minute)
+	assert.Equal(t, []TestReportEnt{{Day: "2025-01-01", Total: 3}}, res.Items)
+
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+
+	err = NewTestSimpleEntRepository(subject.conn).RefreshMaterializedView(context.Background(), nil, false)
+	assert.ErrorIs(t, err, ErrViewNotSupported)
+}
+
+func TestBunCrudRepository_RefreshEvery(t *testing.T) {
+	t.Parallel()
+
+	subject := crudRepositoryShortTestSetUp(t)
+	repo := BunCrudRepository[TestReportEnt, bun.Tx]{
+		ConnSet: subject.conn,
+		Meta:    meta.Parser(TestReportEntMeta{}),
+	}
+
+	failure := errors.New("failure")
+
+	subject.conn.Mock.ExpectExec("^REFRESH MATERIALIZED VIEW").WillReturnResult(sqlmock.NewResult(0, 0))
+	subject.conn.Mock.ExpectExec("^REFRESH MATERIALIZED VIEW").WillReturnError(failure)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var got error
+
+	repo.RefreshEvery(ctx, time.Millisecond, false, func(err error) {
+		got = err
+
+		cancel()
+	})
+
+	assert.ErrorIs(t, got, failure)
+	assert.NoError(t, subject.conn.Mock.ExpectationsWereMet())
+}
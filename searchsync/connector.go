@@ -0,0 +1,259 @@
+package searchsync
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+// Document is an indexed entity, fields are named by presenter names.
+type Document struct {
+	ID     string
+	Fields map[string]any
+}
+
+// Index is a search index client, e.g. for Elasticsearch, OpenSearch or Meilisearch.
+type Index interface {
+	Upsert(ctx context.Context, docs []Document) error
+	Delete(ctx context.Context, ids []string) error
+}
+
+// Walker streams entities in batches, e.g. repository.BunCrudRepository.ForEachBatchByPk.
+type Walker[E any] interface {
+	ForEachBatchByPk(ctx context.Context, tx bun.IDB, spec dataset.Specifier, batchSize int, fn func([]E) error) error
+}
+
+// Connector decorates a repository keeping the index in sync with its writes. The index is updated right
+// after successful writes, so writes in a transaction rolled back later stay indexed until Reindex.
+type Connector[E metadata.Entity, T bun.Tx] struct {
+	repository.CrudRepository[E, T]
+
+	Index Index
+
+	columns []meta.Column
+}
+
+// NewConnector indexes the listed presenter fields of the described entity, all of its columns when none are given.
+func NewConnector[E metadata.Entity, T bun.Tx](
+	repo repository.CrudRepository[E, T],
+	info meta.EntityInfo,
+	index Index,
+	fields ...string,
+) Connector[E, T] {
+	columns := make([]meta.Column, 0, len(info.Columns))
+
+	for _, c := range info.Columns {
+		if len(fields) == 0 || slices.Contains(fields, c.Presenter) {
+			columns = append(columns, c)
+		}
+	}
+
+	return Connector[E, T]{
+		CrudRepository: repo,
+		Index:          index,
+		columns:        columns,
+	}
+}
+
+// Document maps the entity to its index document.
+func (r Connector[E, T]) Document(entity E) Document {
+	pk := entity.PrimaryKey()
+	keys := pk.SortedKeys()
+	ids := make([]string, len(keys))
+
+	for i, k := range keys {
+		ids[i] = fmt.Sprint(pk[k])
+	}
+
+	doc := Document{ID: strings.Join(ids, ":"), Fields: make(map[string]any, len(r.columns))}
+	v := reflect.ValueOf(entity)
+
+	for _, c := range r.columns {
+		if f := fieldByName(v, c.Field); f.IsValid() {
+			doc.Fields[c.Presenter] = f.Interface()
+		}
+	}
+
+	return doc
+}
+
+// Reindex upserts all entities matching spec, streaming them in batches.
+func (r Connector[E, T]) Reindex(ctx context.Context, walker Walker[E], spec dataset.Specifier, batchSize int) error {
+	err := walker.ForEachBatchByPk(ctx, nil, spec, batchSize, func(batch []E) error {
+		return r.upsert(ctx, batch...)
+	})
+	if err != nil {
+		return fmt.Errorf("reindex: %w", err)
+	}
+
+	return nil
+}
+
+func (r Connector[E, T]) CreateOne(ctx context.Context, tx bun.IDB, entity *E, columns []string) (*E, error) {
+	res, err := r.CrudRepository.CreateOne(ctx, tx, entity, columns)
+	if err != nil {
+		return res, err //nolint:wrapcheck
+	}
+
+	return res, r.upsert(ctx, *res)
+}
+
+func (r Connector[E, T]) CreateAll(ctx context.Context, tx bun.IDB, entities []E, columns []string) ([]E, error) {
+	res, err := r.CrudRepository.CreateAll(ctx, tx, entities, columns)
+	if err != nil {
+		return res, err //nolint:wrapcheck
+	}
+
+	return res, r.upsert(ctx, res...)
+}
+
+func (r Connector[E, T]) FirstOrCreate(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	res, err := r.CrudRepository.FirstOrCreate(ctx, tx, spec, entity, columns)
+	if err != nil {
+		return res, err //nolint:wrapcheck
+	}
+
+	return res, r.upsert(ctx, *res)
+}
+
+func (r Connector[E, T]) FirstOrCreateLocked(
+	ctx context.Context,
+	tx bun.IDB,
+	key string,
+	spec dataset.Specifier,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	res, err := r.CrudRepository.FirstOrCreateLocked(ctx, tx, key, spec, entity, columns)
+	if err != nil {
+		return res, err //nolint:wrapcheck
+	}
+
+	return res, r.upsert(ctx, *res)
+}
+
+func (r Connector[E, T]) UpdateOrCreate(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	values map[string]any,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	res, err := r.CrudRepository.UpdateOrCreate(ctx, tx, spec, values, entity, columns)
+	if err != nil {
+		return res, err //nolint:wrapcheck
+	}
+
+	return res, r.upsert(ctx, *res)
+}
+
+func (r Connector[E, T]) UpdateOne(
+	ctx context.Context,
+	tx bun.IDB,
+	entity *E,
+	columnsToUpdate []string,
+	columns []string,
+) (*E, error) {
+	res, err := r.CrudRepository.UpdateOne(ctx, tx, entity, columnsToUpdate, columns)
+	if err != nil {
+		return res, err //nolint:wrapcheck
+	}
+
+	return res, r.upsert(ctx, *res)
+}
+
+func (r Connector[E, T]) ForceDelete(ctx context.Context, tx bun.IDB, spec dataset.Specifier) (int, error) {
+	return r.delete(ctx, tx, spec, r.CrudRepository.ForceDelete)
+}
+
+func (r Connector[E, T]) Delete(ctx context.Context, tx bun.IDB, spec dataset.Specifier) (int, error) {
+	return r.delete(ctx, tx, spec, r.CrudRepository.Delete)
+}
+
+// delete finds rows matching spec before deleting them, to remove their documents afterwards.
+func (r Connector[E, T]) delete(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	del func(ctx context.Context, tx bun.IDB, spec dataset.Specifier) (int, error),
+) (int, error) {
+	entities, err := r.CrudRepository.FindAll(ctx, tx, []string{"*"}, spec)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	rows, err := del(ctx, tx, spec)
+	if err != nil || len(entities) == 0 {
+		return rows, err
+	}
+
+	ids := make([]string, len(entities))
+	for i, v := range entities {
+		ids[i] = r.Document(v).ID
+	}
+
+	if err := r.Index.Delete(ctx, ids); err != nil {
+		return rows, fmt.Errorf("search index delete: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r Connector[E, T]) upsert(ctx context.Context, entities ...E) error {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	docs := make([]Document, len(entities))
+	for i, v := range entities {
+		docs[i] = r.Document(v)
+	}
+
+	if err := r.Index.Upsert(ctx, docs); err != nil {
+		return fmt.Errorf("search index upsert: %w", err)
+	}
+
+	return nil
+}
+
+// fieldByName finds the struct field, following embedded structs the way meta columns list them.
+func fieldByName(v reflect.Value, name string) reflect.Value {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+
+		v = v.Elem()
+	}
+
+	if f := v.FieldByName(name); f.IsValid() {
+		return f
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() != reflect.Struct || !v.Type().Field(i).IsExported() {
+			continue
+		}
+
+		if f := fieldByName(v.Field(i), name); f.IsValid() {
+			return f
+		}
+	}
+
+	return reflect.Value{}
+}
@@ -0,0 +1,136 @@
+package searchsync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type testAudit struct {
+	CreatedBy string `bun:"created_by" json:"createdBy"`
+}
+
+type testArticle struct {
+	bun.BaseModel `bun:"table:articles,alias:articles"`
+
+	ID     int       `bun:"id,pk" json:"id"`
+	Title  string    `bun:"title" json:"title"`
+	Secret string    `bun:"secret" json:"secret"`
+	Audit  testAudit `bun:"embed:audit_" json:"audit"`
+}
+
+func (r testArticle) EntityName() string { return "Article" }
+
+func (r testArticle) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"id": r.ID} }
+
+type testArticleMeta struct{ testArticle }
+
+func (r testArticleMeta) Entity() metadata.Entity { return r.testArticle }
+
+func (r testArticleMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+type stubRepo struct {
+	repository.CrudRepository[testArticle, bun.Tx]
+
+	stored []testArticle
+}
+
+func (r *stubRepo) CreateOne(_ context.Context, _ bun.IDB, entity *testArticle, _ []string) (*testArticle, error) {
+	return entity, nil
+}
+
+func (r *stubRepo) FindAll(context.Context, bun.IDB, []string, dataset.Specifier) ([]testArticle, error) {
+	return r.stored, nil
+}
+
+func (r *stubRepo) Delete(context.Context, bun.IDB, dataset.Specifier) (int, error) {
+	return len(r.stored), nil
+}
+
+func (r *stubRepo) ForEachBatchByPk(
+	_ context.Context,
+	_ bun.IDB,
+	_ dataset.Specifier,
+	batchSize int,
+	fn func([]testArticle) error,
+) error {
+	for i := 0; i < len(r.stored); i += batchSize {
+		if err := fn(r.stored[i:min(i+batchSize, len(r.stored))]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type stubIndex struct {
+	upserted [][]Document
+	deleted  []string
+}
+
+func (r *stubIndex) Upsert(_ context.Context, docs []Document) error {
+	r.upserted = append(r.upserted, docs)
+
+	return nil
+}
+
+func (r *stubIndex) Delete(_ context.Context, ids []string) error {
+	r.deleted = append(r.deleted, ids...)
+
+	return nil
+}
+
+func newTestConnector(t *testing.T, repo *stubRepo, index *stubIndex) Connector[testArticle, bun.Tx] {
+	t.Helper()
+
+	c := meta.NewContainer()
+	c.Add(testArticleMeta{}, meta.Parser)
+
+	info, _ := c.Describe("Article")
+
+	return NewConnector[testArticle, bun.Tx](repo, info, index, "id", "title", "audit_createdBy")
+}
+
+func TestConnector_Writes(t *testing.T) {
+	t.Parallel()
+
+	repo := &stubRepo{stored: []testArticle{{ID: 1}, {ID: 2}}}
+	index := &stubIndex{}
+	conn := newTestConnector(t, repo, index)
+
+	_, err := conn.CreateOne(context.Background(), nil, &testArticle{
+		ID:     3,
+		Title:  "Go",
+		Secret: "x",
+		Audit:  testAudit{CreatedBy: "bob"},
+	}, nil)
+	assert.NoError(t, err)
+
+	rows, err := conn.Delete(context.Background(), nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rows)
+
+	assert.Equal(t, [][]Document{{{
+		ID:     "3",
+		Fields: map[string]any{"id": 3, "title": "Go", "audit_createdBy": "bob"},
+	}}}, index.upserted)
+	assert.Equal(t, []string{"1", "2"}, index.deleted)
+}
+
+func TestConnector_Reindex(t *testing.T) {
+	t.Parallel()
+
+	repo := &stubRepo{stored: []testArticle{{ID: 1}, {ID: 2}, {ID: 3}}}
+	index := &stubIndex{}
+	conn := newTestConnector(t, repo, index)
+
+	assert.NoError(t, conn.Reindex(context.Background(), repo, nil, 2))
+	assert.Len(t, index.upserted, 2)
+	assert.Equal(t, "3", index.upserted[1][0].ID)
+}
@@ -0,0 +1,95 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+// Report of a seed run, rows are in the declared order.
+type Report[E any] struct {
+	// Inserted are declared rows that were missing.
+	Inserted []E
+	// Drifted are stored rows differing from the declared ones, as found.
+	Drifted []E
+	// Corrected are drifted rows reset to the declared values.
+	Corrected []E
+	// Unknown are stored rows that are not declared, e.g. added manually.
+	Unknown []E
+}
+
+// HasChanges reports whether the table differed from the declared rows.
+func (r Report[E]) HasChanges() bool {
+	return len(r.Inserted) > 0 || len(r.Drifted) > 0 || len(r.Unknown) > 0
+}
+
+// Seed makes sure declared rows of a lookup table exist, matching stored rows by primary key.
+// Rows are compared with reflect.DeepEqual, so they should be declared the way they are scanned.
+// Drifted rows are reset to the declared values when correct is set, unknown rows are only reported.
+func Seed[E metadata.Entity, T bun.Tx](
+	ctx context.Context,
+	tx bun.IDB,
+	repo repository.CrudRepository[E, T],
+	rows []E,
+	correct bool,
+) (Report[E], error) {
+	var res Report[E]
+
+	stored, err := repo.FindAll(ctx, tx, []string{"*"}, nil)
+	if err != nil {
+		return res, fmt.Errorf("seed: %w", err)
+	}
+
+	byKey := make(map[string]E, len(stored))
+	for _, v := range stored {
+		byKey[key(v)] = v
+	}
+
+	declared := make(map[string]bool, len(rows))
+
+	for _, row := range rows {
+		k := key(row)
+		declared[k] = true
+
+		current, ok := byKey[k]
+
+		switch {
+		case !ok:
+			res.Inserted = append(res.Inserted, row)
+		case !reflect.DeepEqual(current, row):
+			res.Drifted = append(res.Drifted, current)
+
+			if correct {
+				res.Corrected = append(res.Corrected, row)
+			}
+		}
+	}
+
+	for _, v := range stored {
+		if !declared[key(v)] {
+			res.Unknown = append(res.Unknown, v)
+		}
+	}
+
+	if len(res.Inserted) > 0 {
+		if _, err := repo.CreateAll(ctx, tx, res.Inserted, nil); err != nil {
+			return res, fmt.Errorf("seed: %w", err)
+		}
+	}
+
+	for i := range res.Corrected {
+		if _, err := repo.UpdateOne(ctx, tx, &res.Corrected[i], nil, nil); err != nil {
+			return res, fmt.Errorf("seed: %w", err)
+		}
+	}
+
+	return res, nil
+}
+
+func key(entity metadata.Entity) string {
+	return fmt.Sprint(entity.PrimaryKey().Sorted())
+}
@@ -0,0 +1,90 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type testStatus struct {
+	Code  string
+	Title string
+}
+
+func (r testStatus) EntityName() string { return "testStatus" }
+
+func (r testStatus) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"code": r.Code} }
+
+type stubRepo struct {
+	repository.CrudRepository[testStatus, bun.Tx]
+
+	stored  []testStatus
+	created []testStatus
+	updated []testStatus
+	err     error
+}
+
+func (r *stubRepo) FindAll(context.Context, bun.IDB, []string, dataset.Specifier) ([]testStatus, error) {
+	return r.stored, nil
+}
+
+func (r *stubRepo) CreateAll(_ context.Context, _ bun.IDB, entities []testStatus, _ []string) ([]testStatus, error) {
+	r.created = append(r.created, entities...)
+
+	return entities, r.err
+}
+
+func (r *stubRepo) UpdateOne(_ context.Context, _ bun.IDB, entity *testStatus, _, _ []string) (*testStatus, error) {
+	r.updated = append(r.updated, *entity)
+
+	return entity, r.err
+}
+
+func TestSeed(t *testing.T) {
+	t.Parallel()
+
+	declared := []testStatus{{Code: "new", Title: "New"}, {Code: "paid", Title: "Paid"}, {Code: "sent", Title: "Sent"}}
+	stored := []testStatus{{Code: "new", Title: "New"}, {Code: "paid", Title: "Payed"}, {Code: "lost", Title: "Lost"}}
+
+	tests := []struct {
+		name    string
+		correct bool
+		err     error
+		updated []testStatus
+	}{
+		{name: "report drift", correct: false},
+		{name: "correct drift", correct: true, updated: []testStatus{{Code: "paid", Title: "Paid"}}},
+		{name: "write failure", correct: true, err: errors.New("failure")},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &stubRepo{stored: stored, err: tt.err}
+
+			res, err := Seed[testStatus, bun.Tx](context.Background(), nil, repo, declared, tt.correct)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, res.HasChanges())
+			assert.Equal(t, []testStatus{{Code: "sent", Title: "Sent"}}, res.Inserted)
+			assert.Equal(t, []testStatus{{Code: "paid", Title: "Payed"}}, res.Drifted)
+			assert.Equal(t, []testStatus{{Code: "lost", Title: "Lost"}}, res.Unknown)
+			assert.Equal(t, res.Inserted, repo.created)
+			assert.Equal(t, tt.updated, repo.updated)
+			assert.Equal(t, tt.updated, res.Corrected)
+		})
+	}
+}
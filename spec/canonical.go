@@ -0,0 +1,302 @@
+// Package spec provides helpers working on top of go-ddd specifications.
+package spec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun/schema"
+)
+
+// Canonical renders spec joins and condition with values inlined into a stable form: branches of
+// And/Or built by this package and items of its In lists are sorted and deduplicated, nested branches
+// of the same kind are flattened, whitespace is collapsed, times are rendered in UTC and pointers are
+// dereferenced. Other specifications are rendered as they are, their expressions are never reordered.
+// Equivalent specs built in a different order render the same.
+func Canonical(dialect schema.Dialect, meta metadata.Meta, spec dataset.Specifier) string {
+	if spec == nil || spec.IsEmpty() {
+		return ""
+	}
+
+	fmter := schema.NewFormatter(dialect)
+
+	var joins []string
+
+	for _, j := range spec.Joins(meta) {
+		joins = append(joins, collapse(fmter.FormatQuery(j.JoinString, normalize(j.Args)...)))
+	}
+
+	sort.Strings(joins)
+	joins = dedupe(joins)
+
+	where := canonical(fmter, meta, spec)
+
+	if len(joins) == 0 {
+		return where
+	}
+
+	return strings.Join(joins, " ") + " WHERE " + where
+}
+
+// Key returns a short stable key of the canonical spec prefixed with the entity name.
+func Key(dialect schema.Dialect, meta metadata.Meta, spec dataset.Specifier) string {
+	sum := sha256.Sum256([]byte(Canonical(dialect, meta, spec)))
+
+	return meta.EntityName() + ":" + hex.EncodeToString(sum[:16])
+}
+
+func normalize(values []any) []any {
+	res := make([]any, len(values))
+
+	for i, v := range values {
+		if _, ok := v.(schema.QueryAppender); ok {
+			res[i] = v
+
+			continue
+		}
+
+		rv := reflect.ValueOf(v)
+		for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+			rv = rv.Elem()
+		}
+
+		if rv.IsValid() && !(rv.Kind() == reflect.Pointer && rv.IsNil()) {
+			v = rv.Interface()
+		}
+
+		if t, ok := v.(time.Time); ok {
+			v = t.UTC()
+		}
+
+		res[i] = v
+	}
+
+	return res
+}
+
+// canonical renders a spec walking And/Or trees of this package, other specs are leaves.
+func canonical(fmter schema.Formatter, meta metadata.Meta, spec dataset.Specifier) string {
+	switch s := spec.(type) {
+	case *CompositeSpecification:
+		parts := branches(fmter, meta, s, nil)
+
+		sort.Strings(parts)
+		parts = dedupe(parts)
+
+		op := " AND "
+		if s.or {
+			op = " OR "
+		}
+
+		if len(parts) < 2 {
+			return strings.Join(parts, "")
+		}
+
+		return "(" + strings.Join(parts, op) + ")"
+	case *ConditionSpecification:
+		expr := leaf(fmter, meta, s)
+
+		if s.cond == condIn || s.cond == condNotIn {
+			return sortInLists(expr)
+		}
+
+		return expr
+	default:
+		return leaf(fmter, meta, spec)
+	}
+}
+
+// branches appends rendered non-empty children of s, flattening nested composites of the same kind.
+func branches(fmter schema.Formatter, meta metadata.Meta, s *CompositeSpecification, parts []string) []string {
+	for _, child := range s.children {
+		if child == nil || child.IsEmpty() {
+			continue
+		}
+
+		if c, ok := child.(*CompositeSpecification); ok && c.or == s.or {
+			parts = branches(fmter, meta, c, parts)
+
+			continue
+		}
+
+		parts = append(parts, canonical(fmter, meta, child))
+	}
+
+	return parts
+}
+
+func leaf(fmter schema.Formatter, meta metadata.Meta, spec dataset.Specifier) string {
+	return unwrap(collapse(fmter.FormatQuery(spec.Query(meta), normalize(spec.Values())...)))
+}
+
+// sortInLists sorts items of IN (...) lists of an atomic condition.
+func sortInLists(expr string) string {
+	idx := topLevelIndex(expr, " IN (")
+	if idx < 0 {
+		return expr
+	}
+
+	start := idx + len(" IN (")
+	end := closing(expr, start-1)
+
+	if end < 0 {
+		return expr
+	}
+
+	items := split(expr[start:end], ", ")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+
+	sort.Strings(items)
+
+	return expr[:start] + strings.Join(dedupe(items), ", ") + expr[end:]
+}
+
+// unwrap drops parentheses enclosing the whole expression.
+func unwrap(expr string) string {
+	for len(expr) > 1 && expr[0] == '(' && closing(expr, 0) == len(expr)-1 {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+
+	return expr
+}
+
+// split splits expr by sep outside of quotes and parentheses.
+func split(expr, sep string) []string {
+	var (
+		parts []string
+		last  int
+	)
+
+	scan(expr, func(i int, depth int) bool {
+		if depth == 0 && strings.HasPrefix(expr[i:], sep) {
+			parts = append(parts, expr[last:i])
+			last = i + len(sep)
+		}
+
+		return true
+	})
+
+	return append(parts, expr[last:])
+}
+
+func topLevelIndex(expr, sub string) int {
+	idx := -1
+
+	scan(expr, func(i int, depth int) bool {
+		if depth == 0 && strings.HasPrefix(expr[i:], sub) {
+			idx = i
+
+			return false
+		}
+
+		return true
+	})
+
+	return idx
+}
+
+// closing returns the index of the parenthesis closing the one at open or -1.
+func closing(expr string, open int) int {
+	idx := -1
+
+	scan(expr[open:], func(i int, depth int) bool {
+		if depth == 1 && expr[open+i] == ')' {
+			idx = open + i
+
+			return false
+		}
+
+		return true
+	})
+
+	return idx
+}
+
+// scan calls fn for every position outside of quotes with the parentheses depth before it.
+func scan(expr string, fn func(i int, depth int) bool) {
+	var (
+		depth int
+		quote byte
+	)
+
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+
+			continue
+		}
+
+		if !fn(i, depth) {
+			return
+		}
+
+		switch c {
+		case '\'', '"':
+			quote = c
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+	}
+}
+
+// collapse replaces whitespace runs outside of quotes with a single space.
+func collapse(expr string) string {
+	var (
+		b     strings.Builder
+		quote byte
+		space bool
+	)
+
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+
+		if quote == 0 && (c == ' ' || c == '\t' || c == '\n' || c == '\r') {
+			space = true
+
+			continue
+		}
+
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+
+		space = false
+
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '\'' || c == '"'):
+			quote = c
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+func dedupe(sorted []string) []string {
+	res := sorted[:0]
+
+	for i, s := range sorted {
+		if i == 0 || s != sorted[i-1] {
+			res = append(res, s)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,145 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/uptrace/bun"
+)
+
+type condition int
+
+const (
+	condCompare condition = iota
+	condIsNull
+	condNotIsNull
+	condLike
+	condIn
+	condNotIn
+)
+
+// ConditionSpecification is a go-ddd dataspec condition on a single field keeping its operands,
+// so Match can evaluate it in memory. It renders the same SQL as the wrapped dataspec spec.
+type ConditionSpecification struct {
+	dataset.Specifier
+
+	field dataspec.Field
+	cond  condition
+	op    Op
+	value any
+	list  []any
+	like  *regexp.Regexp
+	// negate inverts LIKE matches
+	negate bool
+	err    error
+}
+
+// CompositeSpecification is a go-ddd dataspec And or Or keeping its children, so Match and Canonical
+// can walk them.
+type CompositeSpecification struct {
+	dataset.CompositeSpecifier
+
+	or       bool
+	children []dataset.Specifier
+}
+
+func NewAnd(specs ...dataset.Specifier) dataset.CompositeSpecifier {
+	return &CompositeSpecification{CompositeSpecifier: dataspec.NewAnd(specs...), children: specs}
+}
+
+func NewOr(specs ...dataset.Specifier) dataset.CompositeSpecifier {
+	return &CompositeSpecification{CompositeSpecifier: dataspec.NewOr(specs...), or: true, children: specs}
+}
+
+func (r *CompositeSpecification) Append(spec dataset.Specifier) {
+	r.CompositeSpecifier.Append(spec)
+	r.children = append(r.children, spec)
+}
+
+func NewEqual(field string, value any) dataset.Specifier {
+	return compareCondition(dataspec.NewEqual(field, value), field, OpEqual, value)
+}
+
+func NewNotEqual(field string, value any) dataset.Specifier {
+	return compareCondition(dataspec.NewNotEqual(field, value), field, OpNotEqual, value)
+}
+
+func NewGt(field string, value any) dataset.Specifier {
+	return compareCondition(dataspec.NewGt(field, value), field, OpGreater, value)
+}
+
+func NewGte(field string, value any) dataset.Specifier {
+	return compareCondition(dataspec.NewGte(field, value), field, OpGreaterEqual, value)
+}
+
+func NewLt(field string, value any) dataset.Specifier {
+	return compareCondition(dataspec.NewLt(field, value), field, OpLess, value)
+}
+
+func NewLte(field string, value any) dataset.Specifier {
+	return compareCondition(dataspec.NewLte(field, value), field, OpLessEqual, value)
+}
+
+func NewIsNull(field string) dataset.Specifier {
+	return &ConditionSpecification{Specifier: dataspec.NewIsNull(field), field: dataspec.NewField(field), cond: condIsNull}
+}
+
+func NewNotIsNull(field string) dataset.Specifier {
+	return &ConditionSpecification{
+		Specifier: dataspec.NewNotIsNull(field),
+		field:     dataspec.NewField(field),
+		cond:      condNotIsNull,
+	}
+}
+
+func NewLike(field string, pattern string) dataset.Specifier {
+	return likeCondition(dataspec.NewLike(field, pattern), field, pattern, false, false)
+}
+
+func NewNotLike(field string, pattern string) dataset.Specifier {
+	return likeCondition(dataspec.NewNotLike(field, pattern), field, pattern, false, true)
+}
+
+func NewILike(field string, pattern string) dataset.Specifier {
+	return likeCondition(dataspec.NewILike(field, pattern), field, pattern, true, false)
+}
+
+func NewNotILike(field string, pattern string) dataset.Specifier {
+	return likeCondition(dataspec.NewNotILike(field, pattern), field, pattern, true, true)
+}
+
+// NewIn builds a column IN (...) condition of a slice of values, bound as bun.In.
+func NewIn(field string, values any) dataset.Specifier {
+	return inCondition(dataspec.NewIn(field, bun.In(values)), field, values, condIn)
+}
+
+// NewNotIn builds a column NOT IN (...) condition of a slice of values, bound as bun.In.
+func NewNotIn(field string, values any) dataset.Specifier {
+	return inCondition(dataspec.NewNotIn(field, bun.In(values)), field, values, condNotIn)
+}
+
+func compareCondition(s dataset.Specifier, field string, op Op, value any) *ConditionSpecification {
+	return &ConditionSpecification{Specifier: s, field: dataspec.NewField(field), cond: condCompare, op: op, value: value}
+}
+
+// likeCondition compiles the pattern once, matching doesn't depend on the evaluated entity.
+func likeCondition(s dataset.Specifier, field, pattern string, insensitive, negate bool) *ConditionSpecification {
+	return &ConditionSpecification{
+		Specifier: s,
+		field:     dataspec.NewField(field),
+		cond:      condLike,
+		like:      likeRegexp(pattern, insensitive),
+		negate:    negate,
+	}
+}
+
+func inCondition(s dataset.Specifier, field string, values any, cond condition) *ConditionSpecification {
+	list, err := inList(values)
+	if err != nil {
+		err = fmt.Errorf("%s: %w", field, err)
+	}
+
+	return &ConditionSpecification{Specifier: s, field: dataspec.NewField(field), cond: cond, list: list, err: err}
+}
@@ -0,0 +1,393 @@
+package spec
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+)
+
+var ErrUnsupportedSpec = errors.New("unsupported spec")
+
+// Match reports whether the entity satisfies the spec the way its SQL condition would,
+// e.g. comparisons with NULL never match and zero nullzero fields are NULL.
+// It supports the condition and And/Or specs of this package over own entity fields,
+// relation fields and other specs, including go-ddd dataspec ones, return ErrUnsupportedSpec.
+func Match[E any](meta metadata.Meta, s dataset.Specifier, entity E) (bool, error) {
+	v := reflect.ValueOf(entity)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return false, fmt.Errorf("nil entity: %w", ErrUnsupportedSpec)
+		}
+
+		v = v.Elem()
+	}
+
+	// an empty spec adds no WHERE clause
+	if s == nil || s.IsEmpty() {
+		return true, nil
+	}
+
+	return matcher{meta: meta, entity: v}.match(s)
+}
+
+type matcher struct {
+	meta   metadata.Meta
+	entity reflect.Value
+}
+
+func (r matcher) match(s dataset.Specifier) (bool, error) {
+	switch ts := s.(type) {
+	case *CompositeSpecification:
+		for _, child := range ts.children {
+			if ok, err := r.match(child); err != nil || ok == ts.or {
+				return ok, err
+			}
+		}
+
+		return !ts.or, nil
+	case *ConditionSpecification:
+		return r.condition(ts)
+	default:
+		return false, fmt.Errorf("%T: %w", s, ErrUnsupportedSpec)
+	}
+}
+
+//nolint:cyclop
+func (r matcher) condition(c *ConditionSpecification) (bool, error) {
+	if c.err != nil {
+		return false, c.err
+	}
+
+	field, err := r.field(c.field)
+	if err != nil {
+		return false, err
+	}
+
+	switch c.cond {
+	case condIsNull:
+		return field == nil, nil
+	case condNotIsNull:
+		return field != nil, nil
+	case condLike:
+		str, ok := field.(string)
+
+		return ok && c.like.MatchString(str) != c.negate, nil
+	case condIn, condNotIn:
+		return inMatch(field, c.list, c.cond == condNotIn)
+	default:
+	}
+
+	res, comparable, err := compare(field, sqlValue(c.value))
+	if err != nil || !comparable {
+		return false, err
+	}
+
+	switch c.op {
+	case OpEqual:
+		return res == 0, nil
+	case OpNotEqual:
+		return res != 0, nil
+	case OpGreater:
+		return res > 0, nil
+	case OpGreaterEqual:
+		return res >= 0, nil
+	case OpLess:
+		return res < 0, nil
+	case OpLessEqual:
+		return res <= 0, nil
+	default:
+		return false, fmt.Errorf("operator %s: %w", c.op, ErrUnsupportedSpec)
+	}
+}
+
+// inMatch evaluates field IN list, NULL fields never match.
+func inMatch(field any, list []any, negate bool) (bool, error) {
+	if field == nil {
+		return false, nil
+	}
+
+	for _, item := range list {
+		if item == nil {
+			// x NOT IN (..., NULL) is never true
+			if negate {
+				return false, nil
+			}
+
+			continue
+		}
+
+		c, comparable, err := compare(field, item)
+		if err != nil {
+			return false, err
+		}
+
+		if comparable && c == 0 {
+			return !negate, nil
+		}
+	}
+
+	return negate, nil
+}
+
+// field returns the normalized value of the entity field a spec field points to.
+func (r matcher) field(f dataspec.Field) (any, error) {
+	if f.EntName() != "" && f.EntName() != r.meta.EntityName() {
+		return nil, fmt.Errorf("%s: relation field: %w", f.Key(), ErrUnsupportedSpec)
+	}
+
+	column := r.meta.PresenterToPersistence(f.FieldName())
+	if column == "" {
+		return nil, fmt.Errorf("%s: unknown field: %w", f.Key(), ErrUnsupportedSpec)
+	}
+
+	v, nullZero, ok := fieldByColumn(r.entity, column, "")
+	if !ok {
+		return nil, fmt.Errorf("%s: no struct field for %s: %w", f.Key(), column, ErrUnsupportedSpec)
+	}
+
+	if nullZero && v.IsZero() {
+		return nil, nil
+	}
+
+	return sqlValue(v.Interface()), nil
+}
+
+// fieldByColumn finds the struct field of a persistence column by bun tags, following embed prefixes.
+func fieldByColumn(v reflect.Value, column, prefix string) (reflect.Value, bool, bool) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		tag, ok := sf.Tag.Lookup("bun")
+		if !ok || !sf.IsExported() || sf.Name == "BaseModel" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+
+		if strings.HasPrefix(parts[0], "embed:") {
+			if sf.Type.Kind() == reflect.Struct {
+				if fv, nz, ok := fieldByColumn(v.Field(i), column, strings.TrimPrefix(parts[0], "embed:")); ok {
+					return fv, nz, true
+				}
+			}
+
+			continue
+		}
+
+		if prefix+parts[0] == column {
+			nullZero := false
+
+			for _, p := range parts[1:] {
+				nullZero = nullZero || p == "nullzero"
+			}
+
+			return v.Field(i), nullZero, true
+		}
+	}
+
+	return reflect.Value{}, false, false
+}
+
+// sqlValue dereferences pointers and valuers, nil stands for NULL.
+func sqlValue(v any) any {
+	for {
+		if v == nil {
+			return nil
+		}
+
+		if valuer, ok := v.(driver.Valuer); ok {
+			rv := reflect.ValueOf(v)
+			if rv.Kind() == reflect.Pointer && rv.IsNil() {
+				return nil
+			}
+
+			dv, err := valuer.Value()
+			if err != nil {
+				return nil
+			}
+
+			v = dv
+
+			continue
+		}
+
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Pointer {
+			return v
+		}
+
+		if rv.IsNil() {
+			return nil
+		}
+
+		v = rv.Elem().Interface()
+	}
+}
+
+// compare compares normalized values, the second result is false for incomparable or NULL values.
+func compare(a, b any) (int, bool, error) {
+	if a == nil || b == nil {
+		return 0, false, nil
+	}
+
+	if at, ok := a.(time.Time); ok {
+		bt, ok := b.(time.Time)
+		if !ok {
+			return 0, false, fmt.Errorf("%T to %T: %w", a, b, ErrUnsupportedSpec)
+		}
+
+		return at.Compare(bt), true, nil
+	}
+
+	if ai, ok := toInt(a); ok {
+		if bi, ok := toInt(b); ok {
+			return cmp(ai, bi), true, nil
+		}
+	}
+
+	if af, ok := toFloat(a); ok {
+		if bf, ok := toFloat(b); ok {
+			return cmp(af, bf), true, nil
+		}
+	}
+
+	switch av := a.(type) {
+	case string:
+		if bs, ok := toString(b); ok {
+			return strings.Compare(av, bs), true, nil
+		}
+	case []byte:
+		if bs, ok := toString(b); ok {
+			return bytes.Compare(av, []byte(bs)), true, nil
+		}
+	case bool:
+		if bb, ok := b.(bool); ok {
+			return cmp(boolInt(av), boolInt(bb)), true, nil
+		}
+	}
+
+	return 0, false, fmt.Errorf("%T to %T: %w", a, b, ErrUnsupportedSpec)
+}
+
+func cmp[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+func toInt(v any) (int64, bool) {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() <= 1<<63-1 {
+			return int64(rv.Uint()), true
+		}
+	default:
+	}
+
+	return 0, false
+}
+
+// toFloat converts numbers and numeric strings, e.g. decimals bound as text.
+func toFloat(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	case reflect.String:
+		f, err := strconv.ParseFloat(rv.String(), 64)
+
+		return f, err == nil
+	default:
+	}
+
+	if i, ok := toInt(v); ok {
+		return float64(i), true
+	}
+
+	return 0, false
+}
+
+func toString(v any) (string, bool) {
+	switch tv := v.(type) {
+	case string:
+		return tv, true
+	case []byte:
+		return string(tv), true
+	default:
+		return "", false
+	}
+}
+
+// likeRegexp translates a LIKE pattern, % and _ are wildcards and backslash escapes.
+func likeRegexp(pattern string, insensitive bool) *regexp.Regexp {
+	var sb strings.Builder
+
+	if insensitive {
+		sb.WriteString("(?i)")
+	}
+
+	sb.WriteString("(?s)^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case c == '%':
+			sb.WriteString(".*")
+		case c == '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	sb.WriteString("$")
+
+	return regexp.MustCompile(sb.String())
+}
+
+// inList returns normalized items of a slice or array.
+func inList(value any) ([]any, error) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%T: %w", value, ErrUnsupportedSpec)
+	}
+
+	list := make([]any, rv.Len())
+	for i := range list {
+		list[i] = sqlValue(rv.Index(i).Interface())
+	}
+
+	return list, nil
+}
@@ -0,0 +1,135 @@
+package spec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type testMatchAudit struct {
+	CreatedBy string `bun:"created_by" json:"createdBy"`
+}
+
+type testMatchEnt struct {
+	bun.BaseModel `bun:"table:match_ents,alias:match_ents"`
+
+	ID        int             `bun:"id,pk" json:"id"`
+	Name      string          `bun:"name" json:"name"`
+	Nick      *string         `bun:"nick" json:"nick"`
+	Code      string          `bun:"code,nullzero" json:"code"`
+	Amount    decimal.Decimal `bun:"amount,type:numeric" json:"amount"`
+	CreatedAt time.Time       `bun:"created_at" json:"createdAt"`
+	Audit     testMatchAudit  `bun:"embed:audit_" json:"audit"`
+}
+
+func (r testMatchEnt) EntityName() string { return "MatchEnt" }
+
+func (r testMatchEnt) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"id": r.ID} }
+
+type testMatchEntMeta struct{ testMatchEnt }
+
+func (r testMatchEntMeta) Entity() metadata.Entity { return r.testMatchEnt }
+
+func (r testMatchEntMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	ent := testMatchEnt{
+		ID:        7,
+		Name:      "Alice_1",
+		Amount:    decimal.RequireFromString("10.50"),
+		CreatedAt: at,
+		Audit:     testMatchAudit{CreatedBy: "bob"},
+	}
+
+	tests := []struct {
+		name  string
+		spec  dataset.Specifier
+		match bool
+		err   error
+	}{
+		{name: "empty", spec: NewAnd(), match: true},
+		{name: "equal", spec: NewEqual("id", 7), match: true},
+		{name: "equal cross kind", spec: NewEqual("id", int64(7)), match: true},
+		{name: "equal pointer value", spec: NewEqual("name", ptr("Alice_1")), match: true},
+		{name: "not equal", spec: NewNotEqual("id", 7), match: false},
+		{name: "gt", spec: NewGt("id", 6), match: true},
+		{name: "lte float", spec: NewLte("id", 6.5), match: false},
+		{name: "decimal", spec: NewGte("amount", 10.5), match: true},
+		{name: "time", spec: NewLt("createdAt", at.Add(time.Second)), match: true},
+		{name: "embedded", spec: NewEqual("audit_createdBy", "bob"), match: true},
+		{name: "null pointer is null", spec: NewIsNull("nick"), match: true},
+		{name: "nullzero is null", spec: NewIsNull("code"), match: true},
+		{name: "not null", spec: NewNotIsNull("name"), match: true},
+		{name: "null never equal", spec: NewNotEqual("nick", "x"), match: false},
+		{name: "in", spec: NewIn("id", []int{1, 7}), match: true},
+		{name: "in plain slice", spec: NewIn("name", []string{"a"}), match: false},
+		{name: "in null", spec: NewIn("id", []*int{nil, ptr(7)}), match: true},
+		{name: "like", spec: NewLike("name", "Ali%"), match: true},
+		{name: "like escaped underscore", spec: NewLike("name", `Alice\__`), match: true},
+		{name: "like case", spec: NewLike("name", "ali%"), match: false},
+		{name: "ilike", spec: NewILike("name", "ali%"), match: true},
+		{name: "not ilike", spec: NewNotILike("name", "%bob%"), match: true},
+		{name: "not like null", spec: NewNotLike("nick", "x"), match: false},
+		{
+			name: "and or",
+			spec: NewAnd(
+				NewOr(NewEqual("name", "x"), NewEqual("id", 7)),
+				NewGt("amount", "10"),
+			),
+			match: true,
+		},
+		{name: "relation field", spec: NewEqual("Manager.name", "x"), err: ErrUnsupportedSpec},
+		{name: "unknown field", spec: NewEqual("missing", "x"), err: ErrUnsupportedSpec},
+		{name: "not in", spec: NewNotIn("id", []int{1, 2}), match: true},
+		{name: "not in null", spec: NewNotIn("id", []*int{nil, ptr(1)}), match: false},
+		{name: "in not a slice", spec: NewIn("id", 7), err: ErrUnsupportedSpec},
+		{name: "unsupported spec", spec: NewRelativeToNow("createdAt", OpLess, 0), err: ErrUnsupportedSpec},
+		{name: "dataspec spec", spec: dataspec.NewEqual("id", 7), err: ErrUnsupportedSpec},
+		{name: "incomparable", spec: NewEqual("createdAt", "x"), err: ErrUnsupportedSpec},
+	}
+
+	m := meta.Parser(testMatchEntMeta{})
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := Match(m, tt.spec, &ent)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.match, res)
+		})
+	}
+}
+
+func TestConditionSpecification_Query(t *testing.T) {
+	t.Parallel()
+
+	m := meta.Parser(testMatchEntMeta{})
+
+	and := NewAnd(NewEqual("id", 7))
+	and.Append(NewOr(NewLike("name", "a%"), NewIn("id", []int{1, 2})))
+
+	expected := dataspec.NewAnd(dataspec.NewEqual("id", 7))
+	expected.Append(dataspec.NewOr(dataspec.NewLike("name", "a%"), dataspec.NewIn("id", bun.In([]int{1, 2}))))
+
+	assert.Equal(t, expected.Query(m), and.Query(m))
+	assert.Equal(t, expected.Values()[:2], and.Values()[:2])
+	assert.Len(t, and.Values(), 3)
+
+	res, err := Match(m, and, testMatchEnt{ID: 7, Name: "ab"})
+	assert.NoError(t, err)
+	assert.True(t, res)
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,44 @@
+package spec
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aso779/crud-repository/interval"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+)
+
+// RelativeToNowSpecification compares a time column with now() shifted by an interval,
+// e.g. expires_at < now() - interval '1 hour' for NewRelativeToNow("expiresAt", OpLess, -time.Hour).
+type RelativeToNowSpecification struct {
+	field  dataspec.Field
+	op     Op
+	offset time.Duration
+}
+
+// NewRelativeToNow builds a column op (now() + offset) condition, negative offsets point to the past.
+func NewRelativeToNow(field string, op Op, offset time.Duration) dataset.Specifier {
+	return &RelativeToNowSpecification{
+		field:  dataspec.NewField(field),
+		op:     op,
+		offset: offset,
+	}
+}
+
+func (r *RelativeToNowSpecification) Joins(meta metadata.Meta) []metadata.Join {
+	return join(meta, r.field)
+}
+
+func (r *RelativeToNowSpecification) Query(meta metadata.Meta) string {
+	return fmt.Sprintf("%s %s (now() + ?::interval)", r.field.ColumnName(meta), r.op)
+}
+
+func (r *RelativeToNowSpecification) Values() []any {
+	return []any{interval.Duration(r.offset)}
+}
+
+func (r *RelativeToNowSpecification) IsEmpty() bool {
+	return r.op == ""
+}
@@ -0,0 +1,152 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/uptrace/bun"
+)
+
+var ErrInvalidValues = errors.New("invalid values")
+
+// StructInSpecification multi-column IN condition over a slice of structs, a safer form of
+// dataspec.NewCompositeIn where columns and values are paired by struct fields instead of by position.
+type StructInSpecification struct {
+	fields []structInField
+	values []any
+}
+
+type structInField struct {
+	field  dataspec.Field
+	column string
+}
+
+// NewStructIn builds a (a, b) IN ((1, 2), ...) condition from a slice of structs or struct pointers,
+// e.g. []struct{ TenantID int `json:"tenantId"`; Code string `json:"code"` }. Exported struct fields
+// are mapped to entity fields by their json tag, the way entity meta names them, or to columns by
+// their bun tag; fields tagged json:"-" are skipped. An empty slice matches no rows.
+func NewStructIn(values any) (dataset.Specifier, error) {
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("%T is not a slice: %w", values, ErrInvalidValues)
+	}
+
+	typ := v.Type().Elem()
+
+	isPtr := typ.Kind() == reflect.Pointer
+	if isPtr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%T is not a slice of structs: %w", values, ErrInvalidValues)
+	}
+
+	var (
+		fields []structInField
+		index  [][]int
+	)
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		field, ok, err := newStructInField(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", typ, err)
+		}
+
+		if ok {
+			fields = append(fields, field)
+			index = append(index, f.Index)
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%s has no fields: %w", typ, ErrInvalidValues)
+	}
+
+	tuples := make([]any, 0, v.Len())
+
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		if isPtr {
+			if elem.IsNil() {
+				return nil, fmt.Errorf("nil value at %d: %w", i, ErrInvalidValues)
+			}
+
+			elem = elem.Elem()
+		}
+
+		tuple := make([]any, len(index))
+		for j, idx := range index {
+			tuple[j] = elem.FieldByIndex(idx).Interface()
+		}
+
+		tuples = append(tuples, tuple)
+	}
+
+	return &StructInSpecification{
+		fields: fields,
+		values: tuples,
+	}, nil
+}
+
+// newStructInField maps f by its json or bun tag, reporting false for skipped fields.
+func newStructInField(f reflect.StructField) (structInField, bool, error) {
+	if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name == "-" {
+		return structInField{}, false, nil
+	} else if name != "" {
+		return structInField{field: dataspec.NewField(name)}, true, nil
+	}
+
+	if column, _, _ := strings.Cut(f.Tag.Get("bun"), ","); column != "" && column != "-" {
+		return structInField{column: column}, true, nil
+	}
+
+	return structInField{}, false, fmt.Errorf("field %s has no json or bun tag: %w", f.Name, ErrInvalidValues)
+}
+
+func (r structInField) columnName(meta metadata.Meta) string {
+	if r.column != "" {
+		return meta.PersistenceName() + "." + r.column
+	}
+
+	return r.field.ColumnName(meta)
+}
+
+func (r *StructInSpecification) Joins(_ metadata.Meta) []metadata.Join {
+	return []metadata.Join{}
+}
+
+func (r *StructInSpecification) Query(meta metadata.Meta) string {
+	if len(r.values) == 0 {
+		return "FALSE"
+	}
+
+	columnNames := make([]string, len(r.fields))
+	for i, f := range r.fields {
+		columnNames[i] = f.columnName(meta)
+	}
+
+	return fmt.Sprintf("(%s) IN (?)", strings.Join(columnNames, ","))
+}
+
+func (r *StructInSpecification) Values() []any {
+	if len(r.values) == 0 {
+		return nil
+	}
+
+	return []any{bun.In(r.values)}
+}
+
+func (r *StructInSpecification) IsEmpty() bool {
+	return false
+}
@@ -0,0 +1,79 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/aso779/crud-repository/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/schema"
+)
+
+func TestNewStructIn(t *testing.T) {
+	t.Parallel()
+
+	type key struct {
+		Name string `json:"name"`
+		ID   int    `json:"id"`
+		note string
+	}
+
+	type column struct {
+		ID   int    `bun:"id"`
+		Nick string `json:"-"`
+	}
+
+	tests := []struct {
+		name     string
+		values   any
+		expected string
+		err      error
+	}{
+		{
+			name:     "structs",
+			values:   []key{{Name: "a", ID: 1, note: "x"}, {Name: "b", ID: 2}},
+			expected: `(match_ents.name,match_ents.id) IN (('a', 1), ('b', 2))`,
+		},
+		{
+			name:     "struct pointers",
+			values:   []*key{{Name: "a", ID: 1}},
+			expected: `(match_ents.name,match_ents.id) IN (('a', 1))`,
+		},
+		{
+			name:     "bun tag and skipped field",
+			values:   []column{{ID: 7, Nick: "x"}},
+			expected: `(match_ents.id) IN ((7))`,
+		},
+		{
+			name:     "empty",
+			values:   []key{},
+			expected: `FALSE`,
+		},
+		{name: "not a slice", values: key{}, err: ErrInvalidValues},
+		{name: "not structs", values: []int{1}, err: ErrInvalidValues},
+		{name: "nil pointer", values: []*key{nil}, err: ErrInvalidValues},
+		{name: "no fields", values: []struct{ id int }{{id: 1}}, err: ErrInvalidValues},
+		{name: "untagged field", values: []struct{ ID int }{{ID: 1}}, err: ErrInvalidValues},
+	}
+
+	m := meta.Parser(testMatchEntMeta{})
+	fmter := schema.NewFormatter(pgdialect.New())
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := NewStructIn(tt.values)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.False(t, s.IsEmpty())
+			assert.Equal(t, tt.expected, fmter.FormatQuery(s.Query(m), s.Values()...))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package spec
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+)
+
+// Op is a comparison operator.
+type Op string
+
+const (
+	OpEqual        Op = "="
+	OpNotEqual     Op = "<>"
+	OpLess         Op = "<"
+	OpLessEqual    Op = "<="
+	OpGreater      Op = ">"
+	OpGreaterEqual Op = ">="
+)
+
+// AtTimeZoneSpecification compares the wall clock time of a timestamptz column in a location,
+// e.g. to match local business days regardless of the session time zone.
+type AtTimeZoneSpecification struct {
+	field dataspec.Field
+	loc   *time.Location
+	op    Op
+	value time.Time
+}
+
+// NewAtTimeZone builds a (column AT TIME ZONE loc) op value condition, value is converted to loc wall clock.
+func NewAtTimeZone(field string, loc *time.Location, op Op, value time.Time) dataset.Specifier {
+	return &AtTimeZoneSpecification{
+		field: dataspec.NewField(field),
+		loc:   loc,
+		op:    op,
+		value: value,
+	}
+}
+
+func (r *AtTimeZoneSpecification) Joins(meta metadata.Meta) []metadata.Join {
+	return join(meta, r.field)
+}
+
+func (r *AtTimeZoneSpecification) Query(meta metadata.Meta) string {
+	return fmt.Sprintf("(%s AT TIME ZONE ?) %s ?", r.field.ColumnName(meta), r.op)
+}
+
+func (r *AtTimeZoneSpecification) Values() []any {
+	return []any{r.loc.String(), r.value.In(r.loc).Format("2006-01-02 15:04:05.999999")}
+}
+
+func (r *AtTimeZoneSpecification) IsEmpty() bool {
+	return r.loc == nil || r.op == ""
+}
+
+// join returns relation joins required by the field the same way go-ddd specs do.
+func join(meta metadata.Meta, field dataspec.Field) []metadata.Join {
+	var joins []metadata.Join
+
+	if rel, ok := meta.Relations()[field.EntName()]; ok {
+		joins = append(joins, rel.Join()...)
+	}
+
+	for _, key := range field.RelKeys() {
+		if rel, ok := meta.Relations()[key]; ok {
+			joins = append(joins, rel.Join()...)
+		}
+	}
+
+	return joins
+}
@@ -0,0 +1,102 @@
+package sqltest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// OpenDB opens a pgdialect database accepting every statement without a server,
+// e.g. for benchmarks of query building.
+func OpenDB() *bun.DB {
+	return bun.NewDB(sql.OpenDB(connector{}), pgdialect.New())
+}
+
+// connector opens connections accepting every statement: queries return no rows and execs affect none.
+type connector struct{}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return conn{}, nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return noopDriver{}
+}
+
+type noopDriver struct{}
+
+func (d noopDriver) Open(string) (driver.Conn, error) {
+	return conn{}, nil
+}
+
+type conn struct{}
+
+func (c conn) Prepare(string) (driver.Stmt, error) {
+	return stmt{}, nil
+}
+
+func (c conn) Close() error {
+	return nil
+}
+
+func (c conn) Begin() (driver.Tx, error) {
+	return tx{}, nil
+}
+
+func (c conn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return tx{}, nil
+}
+
+func (c conn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+func (c conn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return rows{}, nil
+}
+
+type stmt struct{}
+
+func (s stmt) Close() error {
+	return nil
+}
+
+func (s stmt) NumInput() int {
+	return -1
+}
+
+func (s stmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+func (s stmt) Query([]driver.Value) (driver.Rows, error) {
+	return rows{}, nil
+}
+
+type tx struct{}
+
+func (t tx) Commit() error {
+	return nil
+}
+
+func (t tx) Rollback() error {
+	return nil
+}
+
+type rows struct{}
+
+func (r rows) Columns() []string {
+	return nil
+}
+
+func (r rows) Close() error {
+	return nil
+}
+
+func (r rows) Next([]driver.Value) error {
+	return io.EOF
+}
@@ -0,0 +1,145 @@
+// Package sqltest records SQL generated by repository calls into golden files.
+//
+// Golden files live in testdata/<name>.golden next to the test, run tests with
+// GOLDEN_UPDATE=1 to create or update them.
+package sqltest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+// UpdateEnv is the environment variable which makes Assert rewrite golden files.
+const UpdateEnv = "GOLDEN_UPDATE"
+
+// Golden records formatted queries, bun inlines args, so a recorded line holds SQL and args.
+// Queries don't reach a database: selects return no rows and execs affect none.
+type Golden struct {
+	t    testing.TB
+	path string
+	db   *bun.DB
+
+	mu    sync.Mutex
+	lines []string
+}
+
+func NewGolden(t testing.TB, name string) *Golden {
+	t.Helper()
+
+	g := &Golden{
+		t:    t,
+		path: filepath.Join("testdata", name+".golden"),
+		db:   OpenDB(),
+	}
+
+	g.db.AddQueryHook(g)
+	t.Cleanup(func() { _ = g.db.Close() })
+
+	return g
+}
+
+// DB returns the recording database.
+func (r *Golden) DB() *bun.DB {
+	return r.db
+}
+
+// ReadPool implements bunpgconnector.BunConnSet.
+func (r *Golden) ReadPool() *bun.DB {
+	return r.db
+}
+
+// WritePool implements bunpgconnector.BunConnSet.
+func (r *Golden) WritePool() *bun.DB {
+	return r.db
+}
+
+// Step labels queries recorded after it.
+func (r *Golden) Step(label string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lines = append(r.lines, "-- "+label)
+}
+
+// String returns the recording in golden file format.
+func (r *Golden) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return strings.Join(r.lines, "\n") + "\n"
+}
+
+// Assert compares the recording with the golden file or rewrites it when GOLDEN_UPDATE is set.
+func (r *Golden) Assert() {
+	r.t.Helper()
+
+	got := r.String()
+
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+			r.t.Fatalf("golden: %v", err)
+		}
+
+		if err := os.WriteFile(r.path, []byte(got), 0o644); err != nil { //nolint:gosec
+			r.t.Fatalf("golden: %v", err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(r.path)
+	if err != nil {
+		r.t.Fatalf("golden: %v, run with %s=1 to create it", err, UpdateEnv)
+	}
+
+	if d := diff(string(want), got); d != "" {
+		r.t.Errorf("golden %s mismatch, run with %s=1 to update:\n%s", r.path, UpdateEnv, d)
+	}
+}
+
+func (r *Golden) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (r *Golden) AfterQuery(_ context.Context, event *bun.QueryEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lines = append(r.lines, strings.Join(strings.Fields(event.Query), " "))
+}
+
+// diff lists differing lines as -want and +got pairs.
+func diff(want, got string) string {
+	if want == got {
+		return ""
+	}
+
+	w, g := strings.Split(want, "\n"), strings.Split(got, "\n")
+
+	var sb strings.Builder
+
+	for i := 0; i < max(len(w), len(g)); i++ {
+		var wl, gl string
+
+		if i < len(w) {
+			wl = w[i]
+		}
+
+		if i < len(g) {
+			gl = g[i]
+		}
+
+		if wl != gl {
+			fmt.Fprintf(&sb, "line %d:\n- %s\n+ %s\n", i+1, wl, gl)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,62 @@
+package sqltest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aso779/crud-repository/meta"
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/aso779/go-ddd/infrastructure/dataspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type testUser struct {
+	bun.BaseModel `bun:"table:users,alias:users"`
+
+	ID   int    `bun:"id,pk,autoincrement" json:"id"`
+	Name string `bun:"name" json:"name"`
+}
+
+func (r testUser) EntityName() string { return "User" }
+
+func (r testUser) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"id": r.ID} }
+
+type testUserMeta struct{ testUser }
+
+func (r testUserMeta) Entity() metadata.Entity { return r.testUser }
+
+func (r testUserMeta) Relations() (relations map[string]metadata.Relation) { return }
+
+func TestGolden(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	g := NewGolden(t, "repository")
+	repo := repository.BunCrudRepository[testUser, bun.Tx]{
+		ConnSet: g,
+		Meta:    meta.Parser(testUserMeta{}),
+	}
+
+	g.Step("find all")
+	_, _ = repo.FindAll(ctx, nil, []string{"id", "name"}, dataspec.NewAnd(
+		dataspec.NewEqual("name", "a"),
+		dataspec.NewIn("id", bun.In([]int{1, 2})),
+	))
+
+	g.Step("create one")
+	_, _ = repo.CreateOne(ctx, nil, &testUser{Name: "b"}, []string{"*"})
+
+	g.Step("force delete")
+	_, _ = repo.ForceDelete(ctx, nil, dataspec.NewEqual("id", 3))
+
+	g.Assert()
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", diff("a\nb\n", "a\nb\n"))
+	assert.Equal(t, "line 2:\n- b\n+ c\nline 3:\n- \n+ d\n", diff("a\nb\n", "a\nc\nd"))
+}
@@ -0,0 +1,249 @@
+package summary
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/uptrace/bun"
+)
+
+// Maintained decorates a repository of the summaries source table refreshing summary rows of written keys
+// in the write transaction. Writes without a transaction are wrapped in one opened on DB.
+type Maintained[E metadata.Entity, T bun.Tx] struct {
+	repository.CrudRepository[E, T]
+
+	DB        bun.IDB
+	Summaries []Summary
+}
+
+func NewMaintained[E metadata.Entity, T bun.Tx](
+	repo repository.CrudRepository[E, T],
+	db bun.IDB,
+	summaries ...Summary,
+) Maintained[E, T] {
+	return Maintained[E, T]{
+		CrudRepository: repo,
+		DB:             db,
+		Summaries:      summaries,
+	}
+}
+
+// Rebuild recomputes all summaries from their sources.
+func (r Maintained[E, T]) Rebuild(ctx context.Context) error {
+	for _, s := range r.Summaries {
+		if err := s.Rebuild(ctx, r.DB); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r Maintained[E, T]) CreateOne(ctx context.Context, tx bun.IDB, entity *E, columns []string) (*E, error) {
+	return r.writeOne(ctx, tx, nil, func(tx bun.IDB) (*E, error) {
+		return r.CrudRepository.CreateOne(ctx, tx, entity, columns)
+	})
+}
+
+func (r Maintained[E, T]) CreateAll(ctx context.Context, tx bun.IDB, entities []E, columns []string) ([]E, error) {
+	var res []E
+
+	err := r.inTx(ctx, tx, func(tx bun.IDB) error {
+		var err error
+
+		res, err = r.CrudRepository.CreateAll(ctx, tx, entities, columns)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		return r.refresh(ctx, tx, res)
+	})
+
+	return res, err
+}
+
+func (r Maintained[E, T]) FirstOrCreate(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	return r.writeOne(ctx, tx, nil, func(tx bun.IDB) (*E, error) {
+		return r.CrudRepository.FirstOrCreate(ctx, tx, spec, entity, columns)
+	})
+}
+
+func (r Maintained[E, T]) FirstOrCreateLocked(
+	ctx context.Context,
+	tx bun.IDB,
+	key string,
+	spec dataset.Specifier,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	return r.writeOne(ctx, tx, nil, func(tx bun.IDB) (*E, error) {
+		return r.CrudRepository.FirstOrCreateLocked(ctx, tx, key, spec, entity, columns)
+	})
+}
+
+func (r Maintained[E, T]) UpdateOrCreate(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	values map[string]any,
+	entity *E,
+	columns []string,
+) (*E, error) {
+	return r.writeOne(ctx, tx, spec, func(tx bun.IDB) (*E, error) {
+		return r.CrudRepository.UpdateOrCreate(ctx, tx, spec, values, entity, columns)
+	})
+}
+
+// UpdateOne refreshes both the previous and the new keys of the entity, as the update may move it between groups.
+func (r Maintained[E, T]) UpdateOne(
+	ctx context.Context,
+	tx bun.IDB,
+	entity *E,
+	columnsToUpdate []string,
+	columns []string,
+) (*E, error) {
+	var res *E
+
+	err := r.inTx(ctx, tx, func(tx bun.IDB) error {
+		prev, err := r.CrudRepository.FindOneByPk(ctx, tx, []string{"*"}, (*entity).PrimaryKey())
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		res, err = r.CrudRepository.UpdateOne(ctx, tx, entity, columnsToUpdate, columns)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		return r.refresh(ctx, tx, []E{*prev, *res})
+	})
+
+	return res, err
+}
+
+func (r Maintained[E, T]) ForceDelete(ctx context.Context, tx bun.IDB, spec dataset.Specifier) (int, error) {
+	return r.delete(ctx, tx, spec, r.CrudRepository.ForceDelete)
+}
+
+func (r Maintained[E, T]) Delete(ctx context.Context, tx bun.IDB, spec dataset.Specifier) (int, error) {
+	return r.delete(ctx, tx, spec, r.CrudRepository.Delete)
+}
+
+// writeOne runs a single entity write, refreshing keys of rows matching spec before the write when given.
+func (r Maintained[E, T]) writeOne(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	write func(tx bun.IDB) (*E, error),
+) (*E, error) {
+	var res *E
+
+	err := r.inTx(ctx, tx, func(tx bun.IDB) error {
+		var prev []E
+
+		if spec != nil {
+			var err error
+
+			if prev, err = r.CrudRepository.FindAll(ctx, tx, []string{"*"}, spec); err != nil {
+				return err //nolint:wrapcheck
+			}
+		}
+
+		var err error
+
+		if res, err = write(tx); err != nil {
+			return err
+		}
+
+		return r.refresh(ctx, tx, append(prev, *res))
+	})
+
+	return res, err
+}
+
+// delete finds rows matching spec before deleting them, to refresh their keys afterwards.
+func (r Maintained[E, T]) delete(
+	ctx context.Context,
+	tx bun.IDB,
+	spec dataset.Specifier,
+	del func(ctx context.Context, tx bun.IDB, spec dataset.Specifier) (int, error),
+) (int, error) {
+	var rows int
+
+	err := r.inTx(ctx, tx, func(tx bun.IDB) error {
+		entities, err := r.CrudRepository.FindAll(ctx, tx, []string{"*"}, spec)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if rows, err = del(ctx, tx, spec); err != nil {
+			return err
+		}
+
+		return r.refresh(ctx, tx, entities)
+	})
+
+	return rows, err
+}
+
+func (r Maintained[E, T]) inTx(ctx context.Context, tx bun.IDB, fn func(tx bun.IDB) error) error {
+	if tx != nil {
+		return fn(tx)
+	}
+
+	return r.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error { //nolint:wrapcheck
+		return fn(tx)
+	})
+}
+
+// refresh recomputes summary rows of the distinct keys of the entities.
+func (r Maintained[E, T]) refresh(ctx context.Context, tx bun.IDB, entities []E) error {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	table := tx.Dialect().Tables().Get(reflect.TypeOf(entities[0]))
+
+	for _, s := range r.Summaries {
+		seen := make(map[string]struct{}, len(entities))
+		keys := make([][]any, 0, len(entities))
+
+		for i := range entities {
+			v := reflect.ValueOf(&entities[i]).Elem()
+			key := make([]any, len(s.Keys))
+
+			for j, k := range s.Keys {
+				field, ok := table.FieldMap[k]
+				if !ok {
+					return fmt.Errorf("refresh summary %s: %w: %s", s.Table, repository.ErrUnknownColumn, k)
+				}
+
+				key[j] = field.Value(v).Interface()
+			}
+
+			id := fmt.Sprintf("%#v", key)
+			if _, ok := seen[id]; ok {
+				continue
+			}
+
+			seen[id] = struct{}{}
+			keys = append(keys, key)
+		}
+
+		if err := s.Refresh(ctx, tx, keys); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
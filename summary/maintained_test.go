@@ -0,0 +1,171 @@
+package summary
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aso779/crud-repository/repository"
+	"github.com/aso779/go-ddd/domain/usecase/dataset"
+	"github.com/aso779/go-ddd/domain/usecase/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+type testItem struct {
+	bun.BaseModel `bun:"table:items,alias:items"`
+
+	ID         int `bun:"id,pk" json:"id"`
+	CategoryID int `bun:"category_id" json:"categoryId"`
+}
+
+func (r testItem) EntityName() string { return "Item" }
+
+func (r testItem) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"id": r.ID} }
+
+type stubRepo struct {
+	repository.CrudRepository[testItem, bun.Tx]
+
+	stored []testItem
+}
+
+func (r *stubRepo) CreateAll(_ context.Context, _ bun.IDB, entities []testItem, _ []string) ([]testItem, error) {
+	return entities, nil
+}
+
+func (r *stubRepo) FindOneByPk(_ context.Context, _ bun.IDB, _ []string, pk metadata.PrimaryKey) (*testItem, error) {
+	for _, v := range r.stored {
+		if v.ID == pk["id"] {
+			return &v, nil
+		}
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+func (r *stubRepo) UpdateOne(_ context.Context, _ bun.IDB, entity *testItem, _, _ []string) (*testItem, error) {
+	return entity, nil
+}
+
+func (r *stubRepo) FindAll(context.Context, bun.IDB, []string, dataset.Specifier) ([]testItem, error) {
+	return r.stored, nil
+}
+
+func (r *stubRepo) Delete(context.Context, bun.IDB, dataset.Specifier) (int, error) {
+	return len(r.stored), nil
+}
+
+func refreshKeys(keys string) string {
+	return regexp.QuoteMeta(`WHERE (("category_id") IN (` + keys + `))`)
+}
+
+func TestMaintained_Writes(t *testing.T) {
+	t.Parallel()
+
+	stored := []testItem{{ID: 1, CategoryID: 1}, {ID: 2, CategoryID: 1}, {ID: 3, CategoryID: 2}}
+
+	tests := []struct {
+		name  string
+		write func(r Maintained[testItem, bun.Tx]) error
+		keys  string
+	}{
+		{
+			name: "create all refreshes distinct keys",
+			write: func(r Maintained[testItem, bun.Tx]) error {
+				_, err := r.CreateAll(context.Background(), nil, []testItem{{ID: 4, CategoryID: 3}, {ID: 5, CategoryID: 3}}, nil)
+
+				return err
+			},
+			keys: "(3)",
+		},
+		{
+			name: "update refreshes previous and new keys",
+			write: func(r Maintained[testItem, bun.Tx]) error {
+				_, err := r.UpdateOne(context.Background(), nil, &testItem{ID: 3, CategoryID: 1}, nil, nil)
+
+				return err
+			},
+			keys: "(2), (1)",
+		},
+		{
+			name: "delete refreshes deleted keys",
+			write: func(r Maintained[testItem, bun.Tx]) error {
+				_, err := r.Delete(context.Background(), nil, nil)
+
+				return err
+			},
+			keys: "(1), (2)",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := summarySetUp(t)
+			repo := NewMaintained[testItem, bun.Tx](&stubRepo{stored: stored}, db, categoryCounts)
+
+			mock.ExpectBegin()
+			mock.ExpectExec(refreshKeys(tt.keys)).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(`DELETE FROM "category_counts"`).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectCommit()
+
+			assert.NoError(t, tt.write(repo))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMaintained_RollsBackOnRefreshError(t *testing.T) {
+	t.Parallel()
+
+	db, mock := summarySetUp(t)
+	repo := NewMaintained[testItem, bun.Tx](&stubRepo{}, db, categoryCounts)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(refreshKeys("(1)")).WillReturnError(errDB)
+	mock.ExpectRollback()
+
+	_, err := repo.CreateAll(context.Background(), nil, []testItem{{ID: 1, CategoryID: 1}}, nil)
+
+	assert.ErrorIs(t, err, errDB)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+type testPlace struct {
+	bun.BaseModel `bun:"table:places,alias:places"`
+
+	ID     int    `bun:"id,pk" json:"id"`
+	Region string `bun:"region" json:"region"`
+	City   string `bun:"city" json:"city"`
+}
+
+func (r testPlace) EntityName() string { return "Place" }
+
+func (r testPlace) PrimaryKey() metadata.PrimaryKey { return metadata.PrimaryKey{"id": r.ID} }
+
+func TestMaintained_RefreshDistinctKeys(t *testing.T) {
+	t.Parallel()
+
+	db, mock := summarySetUp(t)
+	repo := Maintained[testPlace, bun.Tx]{
+		DB:        db,
+		Summaries: []Summary{{Table: "city_counts", Source: "places", Keys: []string{"region", "city"}}},
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(`IN (('a b', 'c'), ('a', 'b c'))`)).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`DELETE FROM "city_counts"`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.refresh(context.Background(), db, []testPlace{
+		{ID: 1, Region: "a b", City: "c"},
+		{ID: 2, Region: "a", City: "b c"},
+		{ID: 3, Region: "a", City: "b c"},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
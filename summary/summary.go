@@ -0,0 +1,148 @@
+package summary
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/uptrace/bun"
+)
+
+// Summary declares a denormalized table of aggregates over a source table grouped by key columns,
+// e.g. per-category item counts. Key columns have the same names in both tables and are the
+// summary table primary key.
+type Summary struct {
+	Table  string
+	Source string
+	Keys   []string
+	// Aggregates are aggregate expressions over the source table by summary column, e.g. "count(*)".
+	Aggregates map[string]string
+}
+
+// Refresh recomputes summary rows of the given key values, listed in Keys order,
+// and removes summary rows whose keys have no source rows left.
+func (r Summary) Refresh(ctx context.Context, tx bun.IDB, keys [][]any) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	tuples := make([]any, len(keys))
+	for i, k := range keys {
+		tuples[i] = bun.SafeQuery("(?)", bun.In(k))
+	}
+
+	sel := r.aggregate(tx).Where("(?) IN (?)", bun.In(r.keyIdents("")), bun.In(tuples))
+	if _, err := r.upsert(tx, sel).Exec(ctx); err != nil {
+		return fmt.Errorf("refresh summary %s: %w", r.Table, err)
+	}
+
+	source := tx.NewSelect().
+		TableExpr("? AS src", bun.Ident(r.Source)).
+		ColumnExpr("1")
+	for _, k := range r.Keys {
+		source.Where("src.? = s.?", bun.Ident(k), bun.Ident(k))
+	}
+
+	_, err := tx.NewDelete().
+		TableExpr("? AS s", bun.Ident(r.Table)).
+		Where("(?) IN (?)", bun.In(r.keyIdents("s")), bun.In(tuples)).
+		Where("NOT EXISTS (?)", source).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("refresh summary %s: %w", r.Table, err)
+	}
+
+	return nil
+}
+
+// Rebuild recomputes the whole summary table from the source table in a transaction.
+func (r Summary) Rebuild(ctx context.Context, db bun.IDB) error {
+	err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().TableExpr("?", bun.Ident(r.Table)).Where("TRUE").Exec(ctx); err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		_, err := r.upsert(tx, r.aggregate(tx)).Exec(ctx)
+
+		return err //nolint:wrapcheck
+	})
+	if err != nil {
+		return fmt.Errorf("rebuild summary %s: %w", r.Table, err)
+	}
+
+	return nil
+}
+
+// aggregate selects key columns and aggregates of the source table grouped by keys.
+func (r Summary) aggregate(tx bun.IDB) *bun.SelectQuery {
+	query := tx.NewSelect().TableExpr("?", bun.Ident(r.Source))
+
+	for _, k := range r.Keys {
+		query.ColumnExpr("?", bun.Ident(k))
+	}
+
+	for _, c := range r.columns() {
+		query.ColumnExpr(r.Aggregates[c])
+	}
+
+	for _, k := range r.Keys {
+		query.GroupExpr("?", bun.Ident(k))
+	}
+
+	return query
+}
+
+// upsert inserts rows selected by query into the summary table, overwriting aggregates on key conflict.
+func (r Summary) upsert(tx bun.IDB, query *bun.SelectQuery) *bun.RawQuery {
+	columns := r.columns()
+
+	set := make([]any, len(columns))
+	for i, c := range columns {
+		set[i] = bun.SafeQuery("? = EXCLUDED.?", bun.Ident(c), bun.Ident(c))
+	}
+
+	return tx.NewRaw(
+		"INSERT INTO ? (?, ?) ? ON CONFLICT (?) DO UPDATE SET ?",
+		bun.Ident(r.Table),
+		bun.In(r.keyIdents("")),
+		bun.In(idents(columns)),
+		query,
+		bun.In(r.keyIdents("")),
+		bun.In(set),
+	)
+}
+
+func (r Summary) keyIdents(alias string) []any {
+	res := make([]any, len(r.Keys))
+
+	for i, k := range r.Keys {
+		if alias == "" {
+			res[i] = bun.Ident(k)
+		} else {
+			res[i] = bun.Ident(alias + "." + k)
+		}
+	}
+
+	return res
+}
+
+// columns are the aggregate columns in a stable order.
+func (r Summary) columns() []string {
+	res := make([]string, 0, len(r.Aggregates))
+	for k := range r.Aggregates {
+		res = append(res, k)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func idents(names []string) []any {
+	res := make([]any, len(names))
+	for i, n := range names {
+		res[i] = bun.Ident(n)
+	}
+
+	return res
+}
@@ -0,0 +1,107 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+var errDB = errors.New("db failed")
+
+var categoryCounts = Summary{
+	Table:  "category_counts",
+	Source: "items",
+	Keys:   []string{"category_id"},
+	Aggregates: map[string]string{
+		"items": "count(*)",
+		"total": "sum(price)",
+	},
+}
+
+const (
+	refreshUpsert = `INSERT INTO "category_counts" ("category_id", "items", "total") ` +
+		`SELECT "category_id", count(*), sum(price) FROM "items" WHERE (("category_id") IN ((1), (2))) GROUP BY "category_id" ` +
+		`ON CONFLICT ("category_id") DO UPDATE SET "items" = EXCLUDED."items", "total" = EXCLUDED."total"`
+	refreshDelete = `DELETE FROM "category_counts" AS s WHERE (("s"."category_id") IN ((1), (2))) ` +
+		`AND (NOT EXISTS (SELECT 1 FROM "items" AS src WHERE (src."category_id" = s."category_id")))`
+)
+
+func summarySetUp(t *testing.T) (*bun.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	return bun.NewDB(db, pgdialect.New()), mock
+}
+
+func TestSummary_Refresh(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		keys    [][]any
+		expect  func(mock sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name:   "no keys",
+			expect: func(mock sqlmock.Sqlmock) {},
+		},
+		{
+			name: "upsert and delete emptied",
+			keys: [][]any{{1}, {2}},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(refreshUpsert)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(refreshDelete)).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "upsert error",
+			keys: [][]any{{1}, {2}},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(refreshUpsert)).WillReturnError(errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := summarySetUp(t)
+			tt.expect(mock)
+
+			err := categoryCounts.Refresh(context.Background(), db, tt.keys)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSummary_Rebuild(t *testing.T) {
+	t.Parallel()
+
+	db, mock := summarySetUp(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "category_counts" WHERE (TRUE)`)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "category_counts" ("category_id", "items", "total") ` +
+		`SELECT "category_id", count(*), sum(price) FROM "items" GROUP BY "category_id" ON CONFLICT`)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	assert.NoError(t, categoryCounts.Rebuild(context.Background(), db))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}